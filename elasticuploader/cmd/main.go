@@ -80,9 +80,50 @@ func main() {
 		consumptionIndexName, // index name to save the consumption values to
 		"@midnight",          // index recreation time
 	)
+
+	// The manifests of the parser runs are only uploaded if their index is set.
+	runManifestIndexName := os.Getenv("RUN_MANIFEST_INDEX_NAME")
+	fmt.Println("RUN_MANIFEST_INDEX_NAME:", runManifestIndexName)
+	uploaderService.SetRunManifestIndexName(runManifestIndexName)
+
 	uploaderService.HandleMessages()
 
+	startReportUploader(rabbitMqURL, processedDataExchangeName, esClient)
+
 	log.Printf(" [ESUPLOADER] Waiting for messages. To exit press CTRL+C")
 
 	<-forever
 }
+
+// startReportUploader starts uploading the parse reports of the parser service,
+// if the PARSE_REPORT_ROUTING_KEY environment variable is set.
+func startReportUploader(rabbitMqURL string, processedDataExchangeName string, esClient *elastic.EsClientWrapper) {
+	parseReportRoutingKey := os.Getenv("PARSE_REPORT_ROUTING_KEY")
+	fmt.Println("PARSE_REPORT_ROUTING_KEY:", parseReportRoutingKey)
+	if len(parseReportRoutingKey) == 0 {
+		return
+	}
+
+	parseReportQueueName := os.Getenv("PARSE_REPORT_QUEUE")
+	fmt.Println("PARSE_REPORT_QUEUE:", parseReportQueueName)
+	if len(parseReportQueueName) == 0 {
+		log.Fatal("The PARSE_REPORT_QUEUE environment variable is not set")
+	}
+
+	parseReportIndexName := os.Getenv("PARSE_REPORT_INDEX_NAME")
+	fmt.Println("PARSE_REPORT_INDEX_NAME:", parseReportIndexName)
+	if len(parseReportIndexName) == 0 {
+		log.Fatal("The PARSE_REPORT_INDEX_NAME environment variable is not set")
+	}
+
+	// The reports are consumed on their own connection, the connection is kept open while the service runs.
+	reportConsumer := rabbitmq.NewAmqpConsumer(
+		rabbitMqURL,
+		processedDataExchangeName,
+		parseReportRoutingKey,
+		parseReportQueueName)
+	reportConsumer.Connect()
+
+	reportUploader := uploader.NewReportUploader(reportConsumer, esClient, parseReportIndexName)
+	reportUploader.HandleReports()
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/kozgot/go-log-processing/elasticuploader/internal/elastic"
+	"github.com/kozgot/go-log-processing/elasticuploader/internal/rabbitmq"
+	"github.com/kozgot/go-log-processing/elasticuploader/internal/uploader"
+	parserservice "github.com/kozgot/go-log-processing/parser/pkg/service"
+	postprocessorservice "github.com/kozgot/go-log-processing/postprocessor/pkg/service"
+	"github.com/streadway/amqp"
+)
+
+// deliveryBufferSize is the number of messages buffered between two stages of the pipeline,
+// a stage waits for the next one when its buffer is full.
+const deliveryBufferSize = 1000
+
+// The pipeline runs the parser, the postprocessor and the elasticuploader in a single process,
+// without RabbitMQ. The stages are connected by in-memory channels carrying the same messages
+// as the RabbitMQ queues, so the processing is the same as in the distributed deployment.
+// The parser API, the log file sources and the Elasticsearch indexes are configured
+// with the same environment variables as the services.
+func main() {
+	log.Println("Pipeline starting...")
+
+	elasticSearchURL := os.Getenv("ELASTICSEARCH_URL")
+	fmt.Println("ELASTICSEARCH_URL:", elasticSearchURL)
+	if len(elasticSearchURL) == 0 {
+		log.Fatal("The ELASTICSEARCH_URL environment variable is not set")
+	}
+
+	eventIndexName := os.Getenv("EVENT_INDEX_NAME")
+	fmt.Println("EVENT_INDEX_NAME:", eventIndexName)
+	if len(eventIndexName) == 0 {
+		log.Fatal("The EVENT_INDEX_NAME environment variable is not set")
+	}
+
+	consumptionIndexName := os.Getenv("CONSUMPTION_INDEX_NAME")
+	fmt.Println("CONSUMPTION_INDEX_NAME:", consumptionIndexName)
+	if len(consumptionIndexName) == 0 {
+		log.Fatal("The CONSUMPTION_INDEX_NAME environment variable is not set")
+	}
+
+	entries := make(chan amqp.Delivery, deliveryBufferSize)
+	processedData := make(chan amqp.Delivery, deliveryBufferSize)
+
+	// Setup ES client.
+	esClient := elastic.NewEsClientWrapper(elasticSearchURL)
+
+	// Start handling the processed data.
+	uploaderService := uploader.NewUploaderService(
+		rabbitmq.NewChannelConsumer(processedData),
+		esClient,
+		eventIndexName,       // index name to save the events to
+		consumptionIndexName, // index name to save the consumption values to
+		"@midnight",          // index recreation time
+	)
+
+	// The manifests of the parser runs are only uploaded if their index is set.
+	runManifestIndexName := os.Getenv("RUN_MANIFEST_INDEX_NAME")
+	fmt.Println("RUN_MANIFEST_INDEX_NAME:", runManifestIndexName)
+	uploaderService.SetRunManifestIndexName(runManifestIndexName)
+
+	uploaderService.HandleMessages()
+
+	reports := startReportUploader(esClient)
+
+	postprocessorservice.StartInProcess(entries, processedData)
+
+	parserService, err := parserservice.NewInProcessService(entries, reports)
+	if err != nil {
+		log.Fatalf("Could not create the parser service: %s", err)
+	}
+
+	// The parser API keeps running until the process is stopped.
+	log.Fatal(parserService.ListenAndServe(":8080"))
+}
+
+// startReportUploader starts uploading the parse reports of the parser,
+// if the PARSE_REPORT_INDEX_NAME environment variable is set. Returns the channel of the reports,
+// or nil if the reports are not uploaded.
+func startReportUploader(esClient *elastic.EsClientWrapper) chan amqp.Delivery {
+	parseReportIndexName := os.Getenv("PARSE_REPORT_INDEX_NAME")
+	fmt.Println("PARSE_REPORT_INDEX_NAME:", parseReportIndexName)
+	if len(parseReportIndexName) == 0 {
+		return nil
+	}
+
+	reports := make(chan amqp.Delivery, deliveryBufferSize)
+	reportUploader := uploader.NewReportUploader(rabbitmq.NewChannelConsumer(reports), esClient, parseReportIndexName)
+	reportUploader.HandleReports()
+
+	return reports
+}
@@ -6,7 +6,13 @@ require (
 	github.com/cenkalti/backoff/v4 v4.1.2
 	github.com/dustin/go-humanize v1.0.0
 	github.com/elastic/go-elasticsearch/v7 v7.15.1
+	github.com/kozgot/go-log-processing/parser v0.0.0-20211130125815-f7855ac3898c
 	github.com/kozgot/go-log-processing/postprocessor v0.0.0-20211125094118-81abb0e40767
 	github.com/robfig/cron/v3 v3.0.1
 	github.com/streadway/amqp v1.0.0
 )
+
+replace (
+	github.com/kozgot/go-log-processing/parser => ../parser
+	github.com/kozgot/go-log-processing/postprocessor => ../postprocessor
+)
@@ -0,0 +1,30 @@
+package rabbitmq
+
+import "github.com/streadway/amqp"
+
+// ChannelConsumer implements the MessageConsumer interface, it receives the messages
+// from an in-memory channel instead of RabbitMQ.
+type ChannelConsumer struct {
+	deliveries <-chan amqp.Delivery
+}
+
+// NewChannelConsumer creates a new ChannelConsumer that receives the deliveries of the given channel.
+func NewChannelConsumer(deliveries <-chan amqp.Delivery) *ChannelConsumer {
+	consumer := ChannelConsumer{deliveries: deliveries}
+	return &consumer
+}
+
+// Connect does nothing, the channel is ready to use when the consumer is created.
+func (c *ChannelConsumer) Connect() {
+	// NOOP
+}
+
+// CloseChannelAndConnection does nothing, the channel is closed by its producer.
+func (c *ChannelConsumer) CloseChannelAndConnection() {
+	// NOOP
+}
+
+// Consume returns the deliveries of the channel.
+func (c *ChannelConsumer) Consume() (<-chan amqp.Delivery, error) {
+	return c.deliveries, nil
+}
@@ -0,0 +1,52 @@
+package uploader
+
+import (
+	"log"
+
+	"github.com/kozgot/go-log-processing/elasticuploader/internal/elastic"
+	"github.com/kozgot/go-log-processing/elasticuploader/internal/rabbitmq"
+	"github.com/kozgot/go-log-processing/elasticuploader/internal/utils"
+	"github.com/kozgot/go-log-processing/elasticuploader/pkg/models"
+)
+
+// ReportUploader uploads the parse reports published by the parser service to a dedicated ES index.
+// The index is not recreated, so the reports of previous runs are kept for comparison.
+type ReportUploader struct {
+	rabbitMQConsumer rabbitmq.MessageConsumer
+	esClient         elastic.EsClient
+	reportIndexName  string
+}
+
+// NewReportUploader creates a new report uploader instance.
+func NewReportUploader(
+	messageConsumer rabbitmq.MessageConsumer,
+	esClient elastic.EsClient,
+	reportIndexName string,
+) *ReportUploader {
+	reportUploader := ReportUploader{
+		rabbitMQConsumer: messageConsumer,
+		esClient:         esClient,
+		reportIndexName:  reportIndexName,
+	}
+	return &reportUploader
+}
+
+// HandleReports consumes parse reports from rabbitMQ and uploads each of them to ES as a single document.
+func (reportUploader *ReportUploader) HandleReports() {
+	msgs, err := reportUploader.rabbitMQConsumer.Consume()
+	utils.FailOnError(err, " [REPORT UPLOADER] Failed to register a consumer")
+
+	go func() {
+		for delivery := range msgs {
+			// Reports are rare, so they are uploaded right away without buffering.
+			reportUploader.esClient.BulkUpload(
+				[]models.ESDocument{{Content: delivery.Body}},
+				reportUploader.reportIndexName,
+			)
+			log.Println(" [REPORT UPLOADER] Uploaded parse report to index: " + reportUploader.reportIndexName)
+
+			err := delivery.Ack(false)
+			utils.FailOnError(err, " [REPORT UPLOADER] Could not acknowledge message")
+		}
+	}()
+}
@@ -1,6 +1,8 @@
 package uploader
 
 import (
+	"log"
+
 	"github.com/kozgot/go-log-processing/elasticuploader/internal/elastic"
 	"github.com/kozgot/go-log-processing/elasticuploader/internal/rabbitmq"
 	"github.com/kozgot/go-log-processing/elasticuploader/internal/utils"
@@ -15,6 +17,9 @@ type UploaderService struct {
 	eventIndexName          string
 	consumptionIndexName    string
 	indexRecreationTimeSpec string
+
+	// runManifestIndexName is the index of the run manifests, the manifests are not uploaded if it is empty.
+	runManifestIndexName string
 }
 
 // NewUploaderService creates a new uploader service instance.
@@ -37,6 +42,12 @@ func NewUploaderService(
 	return &service
 }
 
+// SetRunManifestIndexName sets the index of the manifests of the processed parser runs.
+// The index is not recreated, so the manifests of previous runs are kept.
+func (service *UploaderService) SetRunManifestIndexName(runManifestIndexName string) {
+	service.runManifestIndexName = runManifestIndexName
+}
+
 // HandleMessages consumes messages from rabbitMQ and uploads them to ES.
 func (service *UploaderService) HandleMessages() {
 	uploadBuffer := NewUploadBuffer(
@@ -53,18 +64,49 @@ func (service *UploaderService) HandleMessages() {
 	go func() {
 		for delivery := range msgs {
 			// Deserialize the received data.
-			data := postprocmodels.DataUnit{}
-			data.Deserialize(delivery.Body)
+			data, err := openDataUnit(delivery.Body)
+			if err != nil {
+				// Messages of an unknown schema version are not requeued, so a newer postprocessor cannot block the queue.
+				log.Printf(" [UPLOADER SERVICE] Rejected message: %s", err)
+				err = delivery.Reject(false)
+				utils.FailOnError(err, " [UPLOADER SERVICE] Could not reject message")
+				continue
+			}
 
-			// Append it to the buffer.
-			uploadBuffer.AppendAndUploadIfNeeded(
-				models.ESDocument{Content: data.Data},
-				data.DataType,
-			)
+			if data.DataType == postprocmodels.Manifest {
+				service.uploadRunManifest(models.ESDocument{Content: data.Data})
+			} else {
+				// Append it to the buffer.
+				uploadBuffer.AppendAndUploadIfNeeded(
+					models.ESDocument{Content: data.Data},
+					data.DataType,
+				)
+			}
 
 			// Acknowledge message.
-			err := delivery.Ack(false)
+			err = delivery.Ack(false)
 			utils.FailOnError(err, " [UPLOADER SERVICE] Could not acknowledge message")
 		}
 	}()
 }
+
+// uploadRunManifest uploads the manifest of a run right away, the manifests are rare, so they are not buffered.
+func (service *UploaderService) uploadRunManifest(manifest models.ESDocument) {
+	if service.runManifestIndexName == "" {
+		log.Println(" [UPLOADER SERVICE] Skipped run manifest, the run manifest index is not set")
+		return
+	}
+
+	service.esClient.BulkUpload([]models.ESDocument{manifest}, service.runManifestIndexName)
+	log.Println(" [UPLOADER SERVICE] Uploaded run manifest to index: " + service.runManifestIndexName)
+}
+
+// openDataUnit opens the envelope of a received message, and deserializes the data unit it carries.
+func openDataUnit(body []byte) (postprocmodels.DataUnit, error) {
+	envelope, err := postprocmodels.OpenEnvelope(body)
+	if err != nil {
+		return postprocmodels.DataUnit{}, err
+	}
+
+	return envelope.DataUnit()
+}
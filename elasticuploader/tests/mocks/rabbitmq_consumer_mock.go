@@ -54,13 +54,15 @@ func (m *MessageConsumerMock) Consume() (<-chan amqp.Delivery, error) {
 
 	for i, cons := range m.TestData.Consumptions {
 		message := postprocmodels.DataUnit{DataType: postprocmodels.Consumption, Data: cons.Serialize()}
-		mockDelivery := NewMockDelivery(message.Serialize(), uint64(i+1), m.acknowledger)
+		envelope := postprocmodels.NewEnvelope("mock-producer", "", message)
+		mockDelivery := NewMockDelivery(envelope.Serialize(), uint64(i+1), m.acknowledger)
 		deliveries <- mockDelivery
 	}
 
 	for i, event := range m.TestData.Events {
 		message := postprocmodels.DataUnit{DataType: postprocmodels.Event, Data: event.Serialize()}
-		mockDelivery := NewMockDelivery(message.Serialize(), uint64(i), m.acknowledger)
+		envelope := postprocmodels.NewEnvelope("mock-producer", "", message)
+		mockDelivery := NewMockDelivery(envelope.Serialize(), uint64(i), m.acknowledger)
 		deliveries <- mockDelivery
 	}
 
@@ -71,7 +73,8 @@ func (m *MessageConsumerMock) Consume() (<-chan amqp.Delivery, error) {
 			log.Printf("%d seconds passed, continue consuming messages...", m.deliveryDelaySeconds)
 			for i, event := range m.TestData.Events {
 				message := postprocmodels.DataUnit{DataType: postprocmodels.Event, Data: event.Serialize()}
-				mockDelivery := NewMockDelivery(message.Serialize(), uint64(i), m.acknowledger)
+				envelope := postprocmodels.NewEnvelope("mock-producer", "", message)
+				mockDelivery := NewMockDelivery(envelope.Serialize(), uint64(i), m.acknowledger)
 				deliveries <- mockDelivery
 			}
 		}()
@@ -69,12 +69,14 @@ func (producer *TestRabbitMqProducer) PublishTestInput(
 ) {
 	for _, event := range testData.Events {
 		dataToSend := postprocmodels.DataUnit{DataType: postprocmodels.Event, Data: event.Serialize()}
-		producer.publishData(dataToSend.Serialize())
+		envelope := postprocmodels.NewEnvelope("test-producer", "", dataToSend)
+		producer.publishData(envelope.Serialize())
 	}
 
 	for _, event := range testData.Consumptions {
 		dataToSend := postprocmodels.DataUnit{DataType: postprocmodels.Consumption, Data: event.Serialize()}
-		producer.publishData(dataToSend.Serialize())
+		envelope := postprocmodels.NewEnvelope("test-producer", "", dataToSend)
+		producer.publishData(envelope.Serialize())
 	}
 }
 
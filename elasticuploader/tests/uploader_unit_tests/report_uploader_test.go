@@ -0,0 +1,67 @@
+package uploaderunittests
+
+import (
+	"testing"
+
+	"github.com/kozgot/go-log-processing/elasticuploader/internal/uploader"
+	"github.com/kozgot/go-log-processing/elasticuploader/pkg/models"
+	"github.com/kozgot/go-log-processing/elasticuploader/tests/mocks"
+	"github.com/streadway/amqp"
+)
+
+// reportConsumerMock delivers the given parse reports.
+type reportConsumerMock struct {
+	reports      [][]byte
+	acknowledger *mocks.MockAcknowledger
+}
+
+func (m *reportConsumerMock) Connect() {
+	// noop
+}
+
+func (m *reportConsumerMock) CloseChannelAndConnection() {
+	// noop
+}
+
+func (m *reportConsumerMock) Consume() (<-chan amqp.Delivery, error) {
+	deliveries := make(chan amqp.Delivery, len(m.reports))
+	for i, report := range m.reports {
+		deliveries <- mocks.NewMockDelivery(report, uint64(i), m.acknowledger)
+	}
+
+	return deliveries, nil
+}
+
+func TestReportUploader(t *testing.T) {
+	reports := [][]byte{
+		[]byte(`{"Totals":{"LinesRead":10}}`),
+		[]byte(`{"Totals":{"LinesRead":20}}`),
+	}
+
+	allMessagesAcknowledged := make(chan bool)
+	mockConsumer := reportConsumerMock{
+		reports:      reports,
+		acknowledger: mocks.NewMockAcknowleder(len(reports), allMessagesAcknowledged),
+	}
+	mockESClient := mocks.NewESClientMock(make(map[string][]models.ESDocument))
+
+	reportUploader := uploader.NewReportUploader(&mockConsumer, mockESClient, "test_parse_reports")
+	reportUploader.HandleReports()
+
+	<-allMessagesAcknowledged
+
+	if len(mockESClient.Indexes) != 1 {
+		t.Fatalf("Expected to upload to %d index, uploaded to %d", 1, len(mockESClient.Indexes))
+	}
+
+	documents := mockESClient.Indexes["test_parse_reports"]
+	if len(documents) != len(reports) {
+		t.Fatalf("Expected %d documents in the report index, actual doc count %d", len(reports), len(documents))
+	}
+
+	for i, document := range documents {
+		if string(document.Content) != string(reports[i]) {
+			t.Fatalf("Expected document %s, got %s", reports[i], document.Content)
+		}
+	}
+}
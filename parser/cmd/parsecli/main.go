@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/dedup"
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/formatregistry"
+	"github.com/kozgot/go-log-processing/parser/internal/ndjson"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/timezone"
+)
+
+// The parser CLI parses log files offline, without RabbitMQ, and writes the parsed entries
+// to the standard output or a file in NDJSON format, one ParsedLogEntry per line.
+// The files are given as arguments, the standard input is parsed if there are none, or an argument is "-".
+func main() {
+	os.Exit(run())
+}
+
+// run parses the log files, and returns the exit code of the CLI.
+// The parsed entries are flushed, and the output file is closed before returning, even if the parsing failed.
+func run() int {
+	outputFile := flag.String("o", "", "the output file, the entries are written to the standard output if it is empty")
+	levels := flag.String("levels", "", "comma separated list of the log levels of the written entries, eg.: INFO,WARN")
+	from := flag.String("from", "", "the earliest timestamp of the written entries in RFC 3339 format")
+	to := flag.String("to", "", "the end of the time range of the written entries in RFC 3339 format (exclusive)")
+	stdinName := flag.String("name", "dc_main.log",
+		"the file name of the standard input, selects its continuation rule and timezone")
+	formatRegistryFile := flag.String("formats", "", "YAML or JSON file containing declarative log and entry formats")
+	defaultTimezone := flag.String("timezone", "", "the timezone of the DCs writing the log files (default UTC)")
+	fileTimezones := flag.String("timezones", "",
+		"comma separated list of file name pattern=timezone pairs, eg.: dc18/*=Indian/Antananarivo")
+	includeRawLines := flag.Bool("raw-lines", false, "add a copy of the original line to the source location")
+	stateChanges := flag.Bool("verbose-state-changes", false, "parse the VERBOSE SMC state change entries")
+	taskLaunches := flag.Bool("verbose-task-launches", false, "parse the VERBOSE task launch entries")
+	dedupWindow := flag.Duration("dedup-window", 0,
+		"suppress the entries already written from another file of the same DC, "+
+			"and keep their fingerprints for the given time, eg.: 1h (disabled by default)")
+	quiet := flag.Bool("quiet", false, "do not log the progress of the parser to the standard error")
+	flag.Parse()
+
+	if *quiet {
+		log.SetOutput(ioutil.Discard)
+	}
+
+	filter, err := ndjson.ParseFilter(*levels, *from, *to)
+	if err != nil {
+		return failf("Invalid entry filter: %s", err)
+	}
+
+	output := io.Writer(os.Stdout)
+	if *outputFile != "" {
+		file, err := os.Create(*outputFile)
+		if err != nil {
+			return failf("Could not create output file: %s", err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	entryWriter := ndjson.NewEntryWriter(output, filter)
+
+	// The entries parsed before a failure are written too, the error of the flush is checked at the end of the run.
+	defer entryWriter.Flush()
+
+	runProgress := progress.NewProgress()
+	fileParser := fileparser.NewFileParser(context.Background(), entryWriter, runProgress)
+	fileParser.SetIncludeRawLines(*includeRawLines)
+	fileParser.SetVerboseEntries(fileparser.VerboseEntries{StateChanges: *stateChanges, TaskLaunches: *taskLaunches})
+	timezones, err := createTimezoneResolver(*defaultTimezone, *fileTimezones)
+	if err != nil {
+		return failf("Could not create the timezone resolver: %s", err)
+	}
+	fileParser.SetTimezoneResolver(timezones)
+	if *dedupWindow > 0 {
+		fileParser.SetDeduplicator(dedup.NewDeduplicator(*dedupWindow))
+	}
+
+	if *formatRegistryFile != "" {
+		registry, err := formatregistry.LoadRegistry(*formatRegistryFile)
+		if err != nil {
+			return failf("Could not load the format registry: %s", err)
+		}
+		fileParser.SetFormatRegistry(registry)
+
+		// The log formats of the registry replace the default continuation rules.
+		rules, err := fileparser.RegistryContinuationRules(registry)
+		if err != nil {
+			return failf("Could not load the format registry: %s", err)
+		}
+
+		if rules != nil {
+			fileParser.SetContinuationRules(rules)
+		}
+	}
+
+	fileNames := flag.Args()
+	if len(fileNames) == 0 {
+		fileNames = []string{"-"}
+	}
+
+	runProgress.SetFilesTotal(len(fileNames))
+	for _, fileName := range fileNames {
+		var err error
+		if fileName == "-" {
+			err = fileParser.ParseSingleFile(ioutil.NopCloser(os.Stdin), *stdinName)
+		} else {
+			file, openErr := os.Open(fileName)
+			if openErr != nil {
+				return failf("Could not open log file: %s", openErr)
+			}
+			err = fileParser.ParseSingleFile(file, fileName)
+		}
+
+		if err != nil {
+			return failf("Could not write parsed entries: %s", err)
+		}
+
+		runProgress.FileDone()
+	}
+
+	err = entryWriter.Flush()
+	if err != nil {
+		return failf("Could not write parsed entries: %s", err)
+	}
+	runProgress.RunFinished()
+
+	snapshot := runProgress.Snapshot()
+	log.Printf("  [PARSER] Lines read: %d, entries parsed: %d, duplicates suppressed: %d, entries written: %d",
+		snapshot.LinesRead, snapshot.EntriesPublished, snapshot.DuplicatesSuppressed, entryWriter.EntriesWritten())
+
+	if len(snapshot.Errors) > 0 {
+		return failf("Parsing finished with %d errors", len(snapshot.Errors))
+	}
+
+	return 0
+}
+
+// createTimezoneResolver creates the resolver of the timezones of the log files,
+// files without a configured timezone use the timezone of their settings entries.
+func createTimezoneResolver(defaultTimezone string, fileTimezones string) (*timezone.Resolver, error) {
+	var defaultLocation *time.Location
+	if defaultTimezone != "" {
+		var err error
+		defaultLocation, err = time.LoadLocation(defaultTimezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", defaultTimezone)
+		}
+	}
+
+	fileLocations, err := timezone.ParseFileLocations(fileTimezones)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezones: %w", err)
+	}
+
+	return timezone.NewResolver(defaultLocation, fileLocations), nil
+}
+
+// failf writes the error to the standard error even in quiet mode, and returns the exit code of a failure.
+func failf(format string, args ...interface{}) int {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	return 1
+}
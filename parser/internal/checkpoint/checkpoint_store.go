@@ -0,0 +1,36 @@
+package checkpoint
+
+import (
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+)
+
+// Checkpoint records how much of a log file has already been parsed.
+type Checkpoint struct {
+	FileName     string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	CreationTime time.Time
+
+	// Offset is the number of bytes of the file that have been parsed, it always points to the start of a line.
+	Offset int64
+
+	// LineNumber is the number of lines of the file that have been parsed.
+	LineNumber int64
+
+	// Compressed is true if the file is compressed, compressed files are always parsed as a whole.
+	Compressed bool
+
+	// Head is the fingerprint of the first parsed bytes of the file, it is compared before parsing the file
+	// from the offset, because not every file downloader can tell if the file was replaced.
+	// It is empty in the checkpoints saved by older parsers.
+	Head filedownloader.FileHead
+}
+
+// Store interface describes the methods needed to save and load checkpoints.
+type Store interface {
+	Get(fileName string) (Checkpoint, bool)
+	Save(checkpoint Checkpoint) error
+}
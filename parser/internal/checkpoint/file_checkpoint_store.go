@@ -0,0 +1,89 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore implements the Store interface, it persists the checkpoints in a JSON file.
+type FileStore struct {
+	path        string
+	checkpoints map[string]Checkpoint
+	mutex       sync.Mutex
+}
+
+// NewFileStore creates a new FileStore, and loads the checkpoints saved in the given file if it exists.
+// Returns an error if the file exists, but it could not be read.
+func NewFileStore(path string) (*FileStore, error) {
+	store := FileStore{path: path, checkpoints: make(map[string]Checkpoint)}
+
+	bytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &store, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read checkpoint file: %w", err)
+	}
+
+	err = json.Unmarshal(bytes, &store.checkpoints)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse checkpoint file: %w", err)
+	}
+
+	return &store, nil
+}
+
+// Get returns the checkpoint of the given file, and false if the file has not been parsed yet.
+func (store *FileStore) Get(fileName string) (Checkpoint, bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	checkpoint, ok := store.checkpoints[fileName]
+	return checkpoint, ok
+}
+
+// Save saves the checkpoint, and writes all checkpoints to the file.
+func (store *FileStore) Save(checkpoint Checkpoint) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.checkpoints[checkpoint.FileName] = checkpoint
+	return store.write()
+}
+
+// write writes the checkpoints to a temporary file first,
+// so that the checkpoint file is never left half written.
+func (store *FileStore) write() error {
+	bytes, err := json.MarshalIndent(store.checkpoints, "", " ")
+	if err != nil {
+		return fmt.Errorf("could not serialize checkpoints: %w", err)
+	}
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(store.path), filepath.Base(store.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temporary checkpoint file: %w", err)
+	}
+
+	_, err = tempFile.Write(bytes)
+	if err != nil {
+		tempFile.Close()
+		return fmt.Errorf("could not write checkpoint file: %w", err)
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return fmt.Errorf("could not write checkpoint file: %w", err)
+	}
+
+	err = os.Rename(tempFile.Name(), store.path)
+	if err != nil {
+		return fmt.Errorf("could not write checkpoint file: %w", err)
+	}
+
+	return nil
+}
@@ -7,13 +7,20 @@ import (
 	"time"
 
 	"github.com/kozgot/go-log-processing/parser/internal/formats"
-	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/internal/timezone"
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
+var (
+	dateRegex                = regexp.MustCompile(formats.DateFormatRegex)
+	timeRangeFromRegex       = regexp.MustCompile(formats.TimeRangeFromRegex)
+	timeRangeStartTicksRegex = regexp.MustCompile(formats.TimeRangeStartTicksRegex)
+	timeRangeToRegex         = regexp.MustCompile(formats.TimeRangeToRegex)
+	timeRangeEndTicksRegex   = regexp.MustCompile(formats.TimeRangeEndTicksRegex)
+)
+
 // ParseFieldInBracketsAsString parses a field in a log entry surrounded by brackets.
-func ParseFieldInBracketsAsString(line string, regexString string) string {
-	regex, _ := regexp.Compile(regexString)
+func ParseFieldInBracketsAsString(line string, regex *regexp.Regexp) string {
 	textualField := regex.FindString(line)
 
 	if textualField == "" {
@@ -27,8 +34,7 @@ func ParseFieldInBracketsAsString(line string, regexString string) string {
 }
 
 // ParseFieldInDoubleBracketsAsString parses a field in a log entry surrounded by double brackets.
-func ParseFieldInDoubleBracketsAsString(line string, regexString string) string {
-	regex, _ := regexp.Compile(regexString)
+func ParseFieldInDoubleBracketsAsString(line string, regex *regexp.Regexp) string {
 	textualField := regex.FindString(line)
 
 	if textualField == "" {
@@ -42,8 +48,7 @@ func ParseFieldInDoubleBracketsAsString(line string, regexString string) string
 }
 
 // ParseFieldInParenthesesAsString parses a field in a log entry surrounded by parentheses.
-func ParseFieldInParenthesesAsString(line string, regexString string) string {
-	regex, _ := regexp.Compile(regexString)
+func ParseFieldInParenthesesAsString(line string, regex *regexp.Regexp) string {
 	textualField := regex.FindString(line)
 
 	if textualField == "" {
@@ -57,8 +62,7 @@ func ParseFieldInParenthesesAsString(line string, regexString string) string {
 }
 
 // ParseFieldAsString parses a field of a log entry as string.
-func ParseFieldAsString(line string, regexString string) string {
-	regex, _ := regexp.Compile(regexString)
+func ParseFieldAsString(line string, regex *regexp.Regexp) string {
 	textualField := regex.FindString(line)
 
 	if textualField == "" {
@@ -69,76 +73,71 @@ func ParseFieldAsString(line string, regexString string) string {
 	return textualField
 }
 
-// TryParseIntFromString parses an integer value from a string representation.
-func TryParseIntFromString(stringRepresentation string) int {
+// TryParseIntFromString parses an integer value from a string representation, an empty string is parsed as 0.
+func TryParseIntFromString(stringRepresentation string) (int, error) {
 	if stringRepresentation != "" {
-		parsedNumber, err := strconv.Atoi(stringRepresentation)
-		utils.FailOnError(err, "Could not parse integer value from string representation.")
-		return parsedNumber
+		return strconv.Atoi(stringRepresentation)
 	}
 
-	return 0
+	return 0, nil
 }
 
-// TryParseInt64FromString parses an int64 value from a string representation.
-func TryParseInt64FromString(stringRepresentation string) int64 {
+// TryParseInt64FromString parses an int64 value from a string representation, an empty string is parsed as 0.
+func TryParseInt64FromString(stringRepresentation string) (int64, error) {
 	if stringRepresentation != "" {
-		parsedNumber, err := strconv.ParseInt(stringRepresentation, 10, 64)
-		utils.FailOnError(err, "Could not parse int64 value from string representation.")
-		return parsedNumber
+		return strconv.ParseInt(stringRepresentation, 10, 64)
 	}
 
-	return 0
+	return 0, nil
 }
 
-// TryParseFloat64FromString parses a float value from a string representation.
-func TryParseFloat64FromString(stringRepresentation string) float64 {
+// TryParseFloat64FromString parses a float value from a string representation, an empty string is parsed as 0.
+func TryParseFloat64FromString(stringRepresentation string) (float64, error) {
 	if stringRepresentation != "" {
-		parsedNumber, err := strconv.ParseFloat(stringRepresentation, 64)
-		utils.FailOnError(err, "Could not parse float value from string representation.")
-		return parsedNumber
+		return strconv.ParseFloat(stringRepresentation, 64)
 	}
 
-	return 0
+	return 0, nil
 }
 
-// ParseDateTimeField parses a datetime field sorrounded by brackets.
-func ParseDateTimeField(line string, regex string) time.Time {
-	timeFieldRegex, _ := regexp.Compile(regex)
-	timeField := timeFieldRegex.FindString(line)
+// ParseDateTimeField parses a datetime field sorrounded by brackets, the date is a local time of the location.
+// Returns the zero time if the line has no such field.
+func ParseDateTimeField(line string, regex *regexp.Regexp, location *time.Location) (time.Time, error) {
+	timeField := regex.FindString(line)
 
 	if timeField != "" {
 		timeString := strings.Split(timeField, "[")[1]
 		timeString = strings.Replace(timeString, "]", "", 1)
 
-		dateTime := ParseDateTime(timeString)
-		return dateTime
+		return ParseDateTime(timeString, location)
 	}
 
-	return time.Time{}
+	return time.Time{}, nil
 }
 
-// ParseDateTime parses a datetime field.
-func ParseDateTime(timeString string) time.Time {
-	dateRegex, _ := regexp.Compile(formats.DateFormatRegex)
-
+// ParseDateTime parses a datetime field, the date is a local time of the location, and it is converted to UTC.
+// If the location is nil, the date is in UTC. Returns the zero time if the string contains no date.
+func ParseDateTime(timeString string, location *time.Location) (time.Time, error) {
 	dateString := dateRegex.FindString(timeString)
 	if dateString != "" {
 		date, err := time.ParseInLocation(formats.DateLayoutString, dateString, time.UTC)
 		if err != nil {
-			// Do not die here, log instead
-			panic(err)
+			return time.Time{}, err
 		}
 
-		return date
+		return timezone.ToUTC(date, location), nil
 	}
 
-	return time.Time{}
+	return time.Time{}, nil
 }
 
 // ParseTimeFieldFromSeconds parses a time field represented by seconds.
-func ParseTimeFieldFromSeconds(line string, timeStampRegex string) time.Time {
-	seconds := TryParseInt64FromString(ParseFieldInBracketsAsString(line, timeStampRegex))
+func ParseTimeFieldFromSeconds(line string, timeStampRegex *regexp.Regexp) (time.Time, error) {
+	seconds, err := TryParseInt64FromString(ParseFieldInBracketsAsString(line, timeStampRegex))
+	if err != nil {
+		return time.Time{}, err
+	}
+
 	if seconds != 0 {
 		dateTimeFromsSecs := time.Unix(seconds, 0)
 		utcDatTime := time.Date(
@@ -150,15 +149,19 @@ func ParseTimeFieldFromSeconds(line string, timeStampRegex string) time.Time {
 			dateTimeFromsSecs.Second(),
 			dateTimeFromsSecs.Nanosecond(),
 			time.UTC)
-		return utcDatTime
+		return utcDatTime, nil
 	}
 
-	return time.Time{}
+	return time.Time{}, nil
 }
 
 // ParseTimeFieldFromMilliSeconds parses a time field represented by milliseconds.
-func ParseTimeFieldFromMilliSeconds(line string, timeStampRegex string) time.Time {
-	milliseconds := TryParseInt64FromString(ParseFieldInBracketsAsString(line, timeStampRegex))
+func ParseTimeFieldFromMilliSeconds(line string, timeStampRegex *regexp.Regexp) (time.Time, error) {
+	milliseconds, err := TryParseInt64FromString(ParseFieldInBracketsAsString(line, timeStampRegex))
+	if err != nil {
+		return time.Time{}, err
+	}
+
 	if milliseconds != 0 {
 		dateTimeFromsSecs := time.Unix(0, convertMillisecondsToSeconds(milliseconds))
 		utcDatTime := time.Date(
@@ -170,32 +173,47 @@ func ParseTimeFieldFromMilliSeconds(line string, timeStampRegex string) time.Tim
 			dateTimeFromsSecs.Second(),
 			dateTimeFromsSecs.Nanosecond(),
 			time.UTC)
-		return utcDatTime
+		return utcDatTime, nil
 	}
 
-	return time.Time{}
+	return time.Time{}, nil
 }
 
-// ParseTimeRange parses a time range from a log entry.
-func ParseTimeRange(line string) *models.TimeRange {
-	from := ParseDateTimeField(line, formats.TimeRangeFromRegex)
-	if !IsValidDate(from) {
-		// The from field is in a different format, try using that.
-		from = ParseTimeFieldFromSeconds(line, formats.TimeRangeStartTicksRegex)
+// ParseTimeRange parses a time range from a log entry, the formatted dates are local times of the location.
+// Returns nil if the entry has no time range.
+func ParseTimeRange(line string, location *time.Location) (*models.TimeRange, error) {
+	from, err := parseTimeRangeBound(line, timeRangeFromRegex, timeRangeStartTicksRegex, location)
+	if err != nil {
+		return nil, err
 	}
 
-	to := ParseDateTimeField(line, formats.TimeRangeToRegex)
-	if !IsValidDate(to) {
-		// The to field is in a different format, try using that.
-		to = ParseTimeFieldFromSeconds(line, formats.TimeRangeEndTicksRegex)
+	to, err := parseTimeRangeBound(line, timeRangeToRegex, timeRangeEndTicksRegex, location)
+	if err != nil {
+		return nil, err
 	}
 
 	if IsValidDate(from) && IsValidDate(to) {
 		result := models.TimeRange{From: from, To: to}
-		return &result
+		return &result, nil
+	}
+
+	return nil, nil
+}
+
+// parseTimeRangeBound parses a bound of a time range, that is either a formatted date or a timestamp in seconds.
+func parseTimeRangeBound(
+	line string,
+	dateRegex *regexp.Regexp,
+	ticksRegex *regexp.Regexp,
+	location *time.Location,
+) (time.Time, error) {
+	date, err := ParseDateTimeField(line, dateRegex, location)
+	if err != nil || IsValidDate(date) {
+		return date, err
 	}
 
-	return nil
+	// The bound is in a different format, try using that.
+	return ParseTimeFieldFromSeconds(line, ticksRegex)
 }
 
 // IsValidDate checks if the parsed date is valid by checking if the year value is big enough.
@@ -1,7 +1,6 @@
 package contentparser
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/kozgot/go-log-processing/parser/internal/formats"
@@ -27,7 +26,6 @@ func (c *ConnectionAttemptParser) Parse() *models.ConnectionAttemptParams {
 	attempt.SmcUID = c.parseUID()
 
 	// Parse the (@ 0021) like part
-	atFieldRegex, _ := regexp.Compile(formats.AtRegex)
 	atFieldString := atFieldRegex.FindString(c.line.Rest)
 
 	if atFieldString != "" {
@@ -1,20 +1,26 @@
 package contentparser
 
 import (
+	"errors"
 	"log"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/kozgot/go-log-processing/parser/internal/common"
-	"github.com/kozgot/go-log-processing/parser/internal/formats"
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
+var (
+	errMissingTimeRange = errors.New("the time range of the read index low profiles message is missing")
+	errMissingCount     = errors.New("the count of the read index profiles message is missing")
+)
+
 type MessageEntryParser struct {
 	line models.EntryWithLevelAndTimestamp
 }
 
-func (messageEntryParser *MessageEntryParser) Parse() *models.DCMessageParams {
+func (messageEntryParser *MessageEntryParser) Parse() (*models.DCMessageParams, error) {
 	dcMessageParams := models.DCMessageParams{}
 
 	source := messageEntryParser.parseSource()
@@ -22,130 +28,157 @@ func (messageEntryParser *MessageEntryParser) Parse() *models.DCMessageParams {
 
 	if source == "" && dest == "" {
 		// it is not a message entry
-		return nil
+		return nil, nil
 	}
 
 	if source != "" {
 		dcMessageParams.IsInComing = true
 		dcMessageParams.SourceOrDestName = source
 		messageTypeString := common.ParseFieldInBracketsAsString(
-			messageEntryParser.line.Rest, formats.IncomingMessageTypeRegex,
+			messageEntryParser.line.Rest, incomingMessageTypeRegex,
 		)
 		dcMessageParams.MessageType = models.ParseDCmessageTypeFromString(messageTypeString)
 	} else if dest != "" {
 		dcMessageParams.IsInComing = false
 		dcMessageParams.SourceOrDestName = dest
 		dcMessageTypeString := common.ParseFieldInBracketsAsString(
-			messageEntryParser.line.Rest, formats.OutGoingMessageTypeRegex,
+			messageEntryParser.line.Rest, outGoingMessageTypeRegex,
 		)
 		dcMessageParams.MessageType = models.ParseDCmessageTypeFromString(dcMessageTypeString)
 	}
 
-	dcMessageParams.Payload = messageEntryParser.parseDCMessagePayload(dcMessageParams.MessageType, dest)
+	payload, err := messageEntryParser.parseDCMessagePayload(dcMessageParams.MessageType, dest)
+	if err != nil {
+		return nil, err
+	}
 
-	return &dcMessageParams
+	dcMessageParams.Payload = payload
+	return &dcMessageParams, nil
 }
 
 func (messageEntryParser *MessageEntryParser) parseSource() string {
 	inComingMessageSource := common.ParseFieldInParenthesesAsString(
-		messageEntryParser.line.Rest, formats.IncomingMessageSourceRegex)
+		messageEntryParser.line.Rest, incomingMessageSourceRegex)
 	return inComingMessageSource
 }
 
 func (messageEntryParser *MessageEntryParser) parseDestination() string {
 	outGoingMessageSource := common.ParseFieldInParenthesesAsString(
-		messageEntryParser.line.Rest, formats.OutGoingMessageDestRegex)
+		messageEntryParser.line.Rest, outGoingMessageDestRegex)
 	return outGoingMessageSource
 }
 
-func (messageEntryParser *MessageEntryParser) parsePayloadTime() time.Time {
+func (messageEntryParser *MessageEntryParser) parsePayloadTime() (time.Time, error) {
 	// Parse the time[] field of the message.
 	// It can be a formatted date or in a date represented by a timestamp in seconds.
-	dateTime := common.ParseDateTimeField(messageEntryParser.line.Rest, formats.DateTimeFieldRegex)
-	if common.IsValidDate(dateTime) {
-		return dateTime
+	dateTime, err := common.ParseDateTimeField(
+		messageEntryParser.line.Rest, dateTimeFieldRegex, messageEntryParser.line.Location)
+	if err != nil || common.IsValidDate(dateTime) {
+		return dateTime, err
 	}
-	datefromSeconds := common.ParseTimeFieldFromSeconds(messageEntryParser.line.Rest, formats.TimeTicksRegex)
-	if common.IsValidDate(datefromSeconds) {
-		return datefromSeconds
+
+	datefromSeconds, err := common.ParseTimeFieldFromSeconds(messageEntryParser.line.Rest, timeTicksRegex)
+	if err != nil || common.IsValidDate(datefromSeconds) {
+		return datefromSeconds, err
 	}
 
-	return time.Time{}
+	return time.Time{}, nil
 }
 
 func (messageEntryParser *MessageEntryParser) parseDCMessagePayload(
 	messageType models.DCMessageType,
 	destination string,
-) *models.DcMessagePayload {
+) (*models.DcMessagePayload, error) {
 	payload := models.DcMessagePayload{}
+	var err error
 
-	payload.SmcUID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.SmcUIDRegex)
-	payload.PodUID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.PodUIDRegex)
-	payload.ServiceLevelID = common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.ServiceLevelIDRegex),
+	payload.SmcUID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, smcUIDRegex)
+	payload.PodUID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, podUIDRegex)
+	payload.ServiceLevelID, err = common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, serviceLevelIDRegex),
 	)
-	payload.Value = common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.ValueRegex),
+	if err != nil {
+		return nil, err
+	}
+
+	payload.Value, err = common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, valueRegex),
 	)
-	payload.Time = messageEntryParser.parsePayloadTime()
-	payload.TimeRange = common.ParseTimeRange(messageEntryParser.line.Rest)
+	if err != nil {
+		return nil, err
+	}
+
+	payload.Time, err = messageEntryParser.parsePayloadTime()
+	if err != nil {
+		return nil, err
+	}
+
+	payload.TimeRange, err = common.ParseTimeRange(messageEntryParser.line.Rest, messageEntryParser.line.Location)
+	if err != nil {
+		return nil, err
+	}
 
 	switch messageType {
 	case models.NewSmc:
 		payload.SmcUID = messageEntryParser.parseNewSmcUID()
 
 	case models.MessageSentToSVI:
-		payload.MessagePayload = messageEntryParser.parseMessagePayload()
+		payload.MessagePayload, err = messageEntryParser.parseMessagePayload()
 
 	case models.Connect:
 		if destination == "PLC" {
 			payload.ConnectToPLCPayload = messageEntryParser.parseConnectToPLC()
 		} else {
 			// destination is SVI or UDS
-			payload.ConnectOrDisconnectPayload = messageEntryParser.parseConnectOrDisconnectPayload()
+			payload.ConnectOrDisconnectPayload, err = messageEntryParser.parseConnectOrDisconnectPayload()
 		}
 
 	case models.PodConfig:
-		payload.PodConfigPayload = messageEntryParser.parsePodConfigPayload()
+		payload.PodConfigPayload, err = messageEntryParser.parsePodConfigPayload()
 
 	case models.SmcConfig:
-		payload.SmcConfigPayload = messageEntryParser.parseSmcConfigPayload()
+		payload.SmcConfigPayload, err = messageEntryParser.parseSmcConfigPayload()
 
 	case models.SmcAddress:
-		payload.SmcAddressPayload = messageEntryParser.parseSmcAddressPayload()
+		payload.SmcAddressPayload, err = messageEntryParser.parseSmcAddressPayload()
 
 	case models.ServiceLevel:
-		payload.ServiceLevelPayload = messageEntryParser.parseServiceLevelPayload()
+		payload.ServiceLevelPayload, err = messageEntryParser.parseServiceLevelPayload()
 
 	case models.Settings:
-		payload.SettingsPayload = messageEntryParser.parseSettingsPayload()
+		payload.SettingsPayload, err = messageEntryParser.parseSettingsPayload()
 
 	case models.DLMSLogs:
-		payload.DLMSLogPayload = messageEntryParser.parseDLMSLogPayload()
+		payload.DLMSLogPayload, err = messageEntryParser.parseDLMSLogPayload()
 
 	case models.IndexReceived:
-		payload.IndexPayload = messageEntryParser.parseIndexPayload()
+		payload.IndexPayload, err = messageEntryParser.parseIndexPayload()
 
 	case models.Consumption:
 		// NOOP: all the aparams are parsed in the root payload property
 		break
 
+	case models.LastIndex:
+		// NOOP: the pod UID, time and value params are parsed in the root payload property
+		break
+
 	case models.IndexLowProfileGeneric:
-		payload.GenericIndexProfilePayload = messageEntryParser.parseGenericIndexProfile()
+		payload.GenericIndexProfilePayload, err = messageEntryParser.parseGenericIndexProfile()
 
 	case models.IndexHighProfileGeneric:
-		payload.GenericIndexProfilePayload = messageEntryParser.parseGenericIndexProfile()
+		payload.GenericIndexProfilePayload, err = messageEntryParser.parseGenericIndexProfile()
 
 	case models.ReadIndexLowProfiles:
-		payload.ReadIndexLowProfilesEntryPayload = messageEntryParser.parseReadIndexLowProfilesEntry()
+		payload.ReadIndexLowProfilesEntryPayload, err = messageEntryParser.parseReadIndexLowProfilesEntry(
+			payload.TimeRange)
 
 	case models.ReadIndexProfiles:
-		payload.ReadIndexProfilesEntryPayload = messageEntryParser.parseReadIndexProfilesEntry()
+		payload.ReadIndexProfilesEntryPayload, err = messageEntryParser.parseReadIndexProfilesEntry()
 
 	case models.Statistics:
 		if destination == "SVI" {
 			// The destination could be 'DB' as well, but in that case, we have no more params to parse.
-			payload.StatisticsEntryPayload = messageEntryParser.parseStatisticsEntry()
+			payload.StatisticsEntryPayload, err = messageEntryParser.parseStatisticsEntry()
 		}
 
 	case models.UnknownDCMessage:
@@ -153,86 +186,111 @@ func (messageEntryParser *MessageEntryParser) parseDCMessagePayload(
 		break
 	}
 
-	return &payload
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
 }
 
 func (
 	messageEntryParser *MessageEntryParser,
-) parseReadIndexLowProfilesEntry() *models.ReadIndexLowProfilesEntryPayload {
-	timeRange := common.ParseTimeRange(messageEntryParser.line.Rest)
+) parseReadIndexLowProfilesEntry(timeRange *models.TimeRange) (*models.ReadIndexLowProfilesEntryPayload, error) {
+	if timeRange == nil {
+		return nil, errMissingTimeRange
+	}
+
 	result := models.ReadIndexLowProfilesEntryPayload{}
 	result.To = timeRange.To
 	result.From = timeRange.From
-	result.SmcUID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.SMCUIDRegex)
+	result.SmcUID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, smcUIDFieldRegex)
 
-	return &result
+	return &result, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseReadIndexProfilesEntry() *models.ReadIndexProfilesEntryPayload {
+func (
+	messageEntryParser *MessageEntryParser,
+) parseReadIndexProfilesEntry() (*models.ReadIndexProfilesEntryPayload, error) {
 	result := models.ReadIndexProfilesEntryPayload{}
-	result.SmcUID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.SMCUIDRegex)
+	result.SmcUID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, smcUIDFieldRegex)
 
 	// Get the count part between the parentheses.
 	// <--[read index profiles]--(SMC) smc_uid[dc18-smc9] (6) (smart_meter_cabinet.cc::190)
-	correctCount := 4
+	countIndex := 2
 	parts := strings.Split(messageEntryParser.line.Rest, "(")
-	if len(parts) < correctCount {
-		// Default if we could not parse...
-		result.Count = 0
+	if len(parts) <= countIndex {
+		return nil, errMissingCount
 	}
 
 	// eg.: '6) '
-	countString := parts[2]
+	countString := parts[countIndex]
 
 	// trim off the ) and space from the end
 	countString = strings.Replace(countString, ") ", "", 1)
 
 	// convert to int
-	count := common.TryParseIntFromString(countString)
-	result.Count = count
+	count, err := common.TryParseIntFromString(countString)
+	if err != nil {
+		return nil, err
+	}
 
-	return &result
+	result.Count = count
+	return &result, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseStatisticsEntry() *models.StatisticsEntryPayload {
+func (messageEntryParser *MessageEntryParser) parseStatisticsEntry() (*models.StatisticsEntryPayload, error) {
 	result := models.StatisticsEntryPayload{}
-	result.Type = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.StatisticsTypeRegex)
-	result.SourceID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.StatisticsSourceIDRegex)
-	result.Time = common.ParseTimeFieldFromSeconds(messageEntryParser.line.Rest, formats.TimeTicksRegex)
-	valueString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.StatisticsValueRegex)
+	var err error
+	result.Type = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, statisticsTypeRegex)
+	result.SourceID = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, statisticsSourceIDRegex)
+	result.Time, err = common.ParseTimeFieldFromSeconds(messageEntryParser.line.Rest, timeTicksRegex)
+	if err != nil {
+		return nil, err
+	}
 
-	value := common.TryParseFloat64FromString(valueString)
-	result.Value = value
+	valueString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, statisticsValueRegex)
 
-	return &result
+	value, err := common.TryParseFloat64FromString(valueString)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Value = value
+	return &result, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseGenericIndexProfile() *models.GenericIndexProfilePayload {
+func (messageEntryParser *MessageEntryParser) parseGenericIndexProfile() (*models.GenericIndexProfilePayload, error) {
 	result := models.GenericIndexProfilePayload{}
 	capturePeriodString := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.IndexProfileCapturePeriodRegex,
+		indexProfileCapturePeriodRegex,
 	)
-	capturePeriod := common.TryParseIntFromString(capturePeriodString)
+	capturePeriod, err := common.TryParseIntFromString(capturePeriodString)
+	if err != nil {
+		return nil, err
+	}
 
 	captureObjectsString := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.IndexProfileCaptureObjectsRegex,
+		indexProfileCaptureObjectsRegex,
 	)
-	captureObjects := common.TryParseIntFromString(captureObjectsString)
+	captureObjects, err := common.TryParseIntFromString(captureObjectsString)
+	if err != nil {
+		return nil, err
+	}
 
 	result.CaptureObjects = captureObjects
 	result.CapturePeriod = capturePeriod
 
-	return &result
+	return &result, nil
 }
 
 func (messageEntryParser *MessageEntryParser) parseConnectToPLC() *models.ConnectToPLCPayload {
 	result := models.ConnectToPLCPayload{}
-	result.Interface = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.ConnectToPLCIfaceRegex)
+	result.Interface = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, connectToPLCIfaceRegex)
 	result.DestinationAddress = common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.ConnectToPLCDestAddressRegex,
+		connectToPLCDestAddressRegex,
 	)
 
 	return &result
@@ -261,16 +319,29 @@ func (messageEntryParser *MessageEntryParser) parseNewSmcUID() string {
 	return result
 }
 
-func (messageEntryParser *MessageEntryParser) parseConnectOrDisconnectPayload() *models.ConnectOrDisconnectPayload {
-	resultType := common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.ConnectOrDisconnectTypeRegex))
-	clientID := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.ClientIDRegex)
-	URL := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.URLRegex)
-	topic := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.TopicRegex)
-	timeout := common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.TimeoutRegex))
-	connected := common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.ConnectedRegex)) == 1
+func (
+	messageEntryParser *MessageEntryParser,
+) parseConnectOrDisconnectPayload() (*models.ConnectOrDisconnectPayload, error) {
+	resultType, err := common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, connectOrDisconnectTypeRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	clientID := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, clientIDRegex)
+	URL := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, urlRegex)
+	topic := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, topicRegex)
+	timeout, err := common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, timeoutRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	connectedFlag, err := common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, connectedRegex))
+	if err != nil {
+		return nil, err
+	}
 
 	if clientID != "" || resultType != 0 || URL != "" || topic != "" || timeout != 0 {
 		result := models.ConnectOrDisconnectPayload{
@@ -279,98 +350,133 @@ func (messageEntryParser *MessageEntryParser) parseConnectOrDisconnectPayload()
 			URL:       URL,
 			Topic:     topic,
 			Timeout:   timeout,
-			Connected: connected}
-		return &result
+			Connected: connectedFlag == 1}
+		return &result, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseDLMSLogPayload() *models.DLMSLogPayload {
-	requestTimeFromSeconds := common.ParseTimeFieldFromMilliSeconds(
-		messageEntryParser.line.Rest, formats.DLMSRequestTimeRegex,
+func (messageEntryParser *MessageEntryParser) parseDLMSLogPayload() (*models.DLMSLogPayload, error) {
+	requestTimeFromSeconds, err := common.ParseTimeFieldFromMilliSeconds(
+		messageEntryParser.line.Rest, dlmsRequestTimeRegex,
 	)
-	responseTimeFromSeconds := common.ParseTimeFieldFromMilliSeconds(
-		messageEntryParser.line.Rest, formats.DLMSResponseTimeRegex,
+	if err != nil {
+		return nil, err
+	}
+
+	responseTimeFromSeconds, err := common.ParseTimeFieldFromMilliSeconds(
+		messageEntryParser.line.Rest, dlmsResponseTimeRegex,
 	)
-	DLMSError := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.DLMSErrorRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	DLMSError := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, dlmsErrorRegex)
 
 	if requestTimeFromSeconds.Year() > 1500 || responseTimeFromSeconds.Year() > 1500 || DLMSError != "" {
 		result := models.DLMSLogPayload{
 			DLMSRequestTime:  requestTimeFromSeconds,
 			DLMSResponseTime: responseTimeFromSeconds,
 			DLMSError:        DLMSError}
-		return &result
+		return &result, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseIndexPayload() *models.IndexPayload {
-	previousValueString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.PreviousValueRegex)
-	serialNumberString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.SerialNumberRegex)
-	previousTimeFromSeconds := common.ParseTimeFieldFromSeconds(messageEntryParser.line.Rest, formats.PreviousTimeRegex)
+func (messageEntryParser *MessageEntryParser) parseIndexPayload() (*models.IndexPayload, error) {
+	previousValueString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, previousValueRegex)
+	serialNumberString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, serialNumberRegex)
+	previousTimeFromSeconds, err := common.ParseTimeFieldFromSeconds(
+		messageEntryParser.line.Rest, previousTimeRegex)
+	if err != nil {
+		return nil, err
+	}
+
 	if previousTimeFromSeconds.Year() < 1000 && serialNumberString == "" && previousValueString == "" {
-		return nil
+		return nil, nil
 	}
 
-	previousValue := common.TryParseIntFromString(previousValueString)
-	serialNumber := common.TryParseIntFromString(serialNumberString)
+	previousValue, err := common.TryParseIntFromString(previousValueString)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber, err := common.TryParseIntFromString(serialNumberString)
+	if err != nil {
+		return nil, err
+	}
 
 	result := models.IndexPayload{}
 	result.PreviousTime = previousTimeFromSeconds
 	result.PreviousValue = previousValue
 	result.SerialNumber = serialNumber
 
-	return &result
+	return &result, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseMessagePayload() *models.MessagePayload {
-	currentString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.CurrentRegex)
-	totalString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.TotalRegex)
+func (messageEntryParser *MessageEntryParser) parseMessagePayload() (*models.MessagePayload, error) {
+	currentString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, currentRegex)
+	totalString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, totalRegex)
 	if currentString == "" && totalString == "" {
-		return nil
+		return nil, nil
 	}
 
 	result := models.MessagePayload{}
+	var err error
+
+	result.Current, err = common.TryParseFloat64FromString(currentString)
+	if err != nil {
+		return nil, err
+	}
 
-	result.Current = common.TryParseFloat64FromString(currentString)
-	result.Total = common.TryParseFloat64FromString(
-		common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.TotalRegex))
-	result.URL = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.URLRegex)
-	result.Topic = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.TopicRegex)
+	result.Total, err = common.TryParseFloat64FromString(totalString)
+	if err != nil {
+		return nil, err
+	}
 
-	return &result
+	result.URL = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, urlRegex)
+	result.Topic = common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, topicRegex)
+
+	return &result, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseSettingsPayload() *models.SettingsPayload {
-	dcUID := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.DcUIDRegex)
+func (messageEntryParser *MessageEntryParser) parseSettingsPayload() (*models.SettingsPayload, error) {
+	dcUID := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, dcUIDRegex)
 
 	indexCollectionString := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.IndexCollectionRegex,
+		indexCollectionRegex,
 	)
 	dataPublishString := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.DataPublishRegex,
+		dataPublishRegex,
 	)
 	frequencyBandChangedString := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.FrequencyBandChangedRegex,
+		frequencyBandChangedRegex,
 	)
 	frequencyBandRollBackDonestirng := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.FrequencyBandRollbackDoneRegex,
+		frequencyBandRollbackDoneRegex,
 	)
 
-	lastServerCommTimeFromSeconds := common.ParseTimeFieldFromSeconds(
+	lastServerCommTimeFromSeconds, err := common.ParseTimeFieldFromSeconds(
 		messageEntryParser.line.Rest,
-		formats.LastServerCommunicationTimeRegex,
+		lastServerCommunicationTimeRegex,
 	)
-	lastDcStartTimeFromSeconds := common.ParseTimeFieldFromSeconds(
+	if err != nil {
+		return nil, err
+	}
+
+	lastDcStartTimeFromSeconds, err := common.ParseTimeFieldFromSeconds(
 		messageEntryParser.line.Rest,
-		formats.LastDcStartTimeRegex,
+		lastDcStartTimeRegex,
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	if dcUID == "" &&
 		lastServerCommTimeFromSeconds.Year() < 1000 &&
@@ -379,26 +485,41 @@ func (messageEntryParser *MessageEntryParser) parseSettingsPayload() *models.Set
 		indexCollectionString == "" &&
 		frequencyBandChangedString == "" &&
 		frequencyBandRollBackDonestirng == "" {
-		return nil
+		return nil, nil
 	}
 
-	locality := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.LocalityRegex)
-	region := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.RegionRegex)
-	timezone := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.TimezoneRegex)
-	globalFtpAddress := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.GlobalFtpAddressRegex)
+	locality := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, localityRegex)
+	region := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, regionRegex)
+	timezone := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, timezoneRegex)
+	globalFtpAddress := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, globalFtpAddressRegex)
 	targetFirmwareVersion := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.TargetFirmwareVersionRegex,
+		targetFirmwareVersionRegex,
 	)
 	dcDistroTargetFirmwareVersion := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.DcDistroTargetFirmwareVersionRegex,
+		dcDistroTargetFirmwareVersionRegex,
 	)
 
-	indexCollection := common.TryParseIntFromString(indexCollectionString)
-	dataPublish := common.TryParseIntFromString(dataPublishString)
-	frequencyBandChanged := common.TryParseIntFromString(frequencyBandChangedString) == 1
-	frequencyBandRollBackDone := common.TryParseIntFromString(frequencyBandRollBackDonestirng) == 1
+	indexCollection, err := common.TryParseIntFromString(indexCollectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	dataPublish, err := common.TryParseIntFromString(dataPublishString)
+	if err != nil {
+		return nil, err
+	}
+
+	frequencyBandChanged, err := common.TryParseIntFromString(frequencyBandChangedString)
+	if err != nil {
+		return nil, err
+	}
+
+	frequencyBandRollBackDone, err := common.TryParseIntFromString(frequencyBandRollBackDonestirng)
+	if err != nil {
+		return nil, err
+	}
 
 	result := models.SettingsPayload{
 		DcUID:            dcUID,
@@ -411,62 +532,88 @@ func (messageEntryParser *MessageEntryParser) parseSettingsPayload() *models.Set
 	result.DcDistroTargetFirmwareVersion = dcDistroTargetFirmwareVersion
 	result.IndexCollection = indexCollection
 	result.DataPublish = dataPublish
-	result.FrequencyBandChanged = frequencyBandChanged
-	result.FrequencyBandRollBackDone = frequencyBandRollBackDone
+	result.FrequencyBandChanged = frequencyBandChanged == 1
+	result.FrequencyBandRollBackDone = frequencyBandRollBackDone == 1
 
-	return &result
+	return &result, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseServiceLevelPayload() *models.ServiceLevelPayload {
-	meterModeString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.MeterModeRegex)
-	maxActivePowerstring := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.MaxActivePowerRegex)
+func (messageEntryParser *MessageEntryParser) parseServiceLevelPayload() (*models.ServiceLevelPayload, error) {
+	meterModeString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, meterModeRegex)
+	maxActivePowerstring := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, maxActivePowerRegex)
 	loadSheddingDailyEnergyBudgetString := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.LoadSheddingDailyEnergyBudgetRegex)
+		loadSheddingDailyEnergyBudgetRegex)
 	localSheddingDailyEnergyBudgetString := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.LocalSheddingDailyEnergyBudgetRegex)
-	inServiceString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.InServiceRegex)
+		localSheddingDailyEnergyBudgetRegex)
+	inServiceString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, inServiceRegex)
 	if meterModeString == "" &&
 		maxActivePowerstring == "" &&
 		loadSheddingDailyEnergyBudgetString == "" &&
 		localSheddingDailyEnergyBudgetString == "" &&
 		inServiceString == "" {
 		// if we could not parse any of these fields, than most likely we will not be able to parse the remaining
-		return nil
+		return nil, nil
 	}
 
-	meterMode := common.TryParseIntFromString(meterModeString)
-	maxActivePower := common.TryParseIntFromString(maxActivePowerstring)
-	loadSheddingDailyEnergyBudget := common.TryParseIntFromString(loadSheddingDailyEnergyBudgetString)
-	localSheddingDailyEnergyBudget := common.TryParseIntFromString(localSheddingDailyEnergyBudgetString)
-	inService := common.TryParseIntFromString(inServiceString) == 1
+	meterMode, err := common.TryParseIntFromString(meterModeString)
+	if err != nil {
+		return nil, err
+	}
+
+	maxActivePower, err := common.TryParseIntFromString(maxActivePowerstring)
+	if err != nil {
+		return nil, err
+	}
+
+	loadSheddingDailyEnergyBudget, err := common.TryParseIntFromString(loadSheddingDailyEnergyBudgetString)
+	if err != nil {
+		return nil, err
+	}
+
+	localSheddingDailyEnergyBudget, err := common.TryParseIntFromString(localSheddingDailyEnergyBudgetString)
+	if err != nil {
+		return nil, err
+	}
+
+	inService, err := common.TryParseIntFromString(inServiceString)
+	if err != nil {
+		return nil, err
+	}
 
 	startHourDailyCycle := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.StartHourDailyCycleRegex,
+		startHourDailyCycleRegex,
 	)
-	name := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.NameRegex)
+	name := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, nameRegex)
 
-	hourlyEnergyLimits := messageEntryParser.parseHourlyEnergyLimits(formats.HourlyEnergyLimitsRegex)
-	localHourlyEnergyLimits := messageEntryParser.parseHourlyEnergyLimits(formats.LocalHourlyEnergyLimitsRegex)
+	hourlyEnergyLimits, err := messageEntryParser.parseHourlyEnergyLimits(hourlyEnergyLimitsRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	localHourlyEnergyLimits, err := messageEntryParser.parseHourlyEnergyLimits(localHourlyEnergyLimitsRegex)
+	if err != nil {
+		return nil, err
+	}
 
 	result := models.ServiceLevelPayload{
 		MeterMode:                      meterMode,
 		MaxActivePower:                 maxActivePower,
 		LoadSheddingDailyEnergyBudget:  loadSheddingDailyEnergyBudget,
 		LocalSheddingDailyEnergyBudget: localSheddingDailyEnergyBudget,
-		InService:                      inService,
+		InService:                      inService == 1,
 		StartHourDailyCycle:            startHourDailyCycle,
 		Name:                           name}
 	result.HourlyEnergyLimits = hourlyEnergyLimits
 	result.LocalHourlyEnergyLimits = localHourlyEnergyLimits
-	return &result
+	return &result, nil
 }
 
 func (messageEntryParser *MessageEntryParser) parseHourlyEnergyLimits(
-	energyLimitRegex string,
-) [24]models.HourlyEnergyLimit {
+	energyLimitRegex *regexp.Regexp,
+) ([24]models.HourlyEnergyLimit, error) {
 	var result [24]models.HourlyEnergyLimit
 	hoursInADay := 24
 	hourlyLimitsString := common.ParseFieldInDoubleBracketsAsString(messageEntryParser.line.Rest, energyLimitRegex)
@@ -474,30 +621,43 @@ func (messageEntryParser *MessageEntryParser) parseHourlyEnergyLimits(
 		limitParts := strings.Split(hourlyLimitsString, " ")
 		if len(limitParts) == hoursInADay {
 			for i, val := range limitParts {
-				result[i] = models.HourlyEnergyLimit{HourNumber: i, Limit: common.TryParseIntFromString(val)}
+				limit, err := common.TryParseIntFromString(val)
+				if err != nil {
+					return result, err
+				}
+
+				result[i] = models.HourlyEnergyLimit{HourNumber: i, Limit: limit}
 			}
 
-			return result
+			return result, nil
 		}
 
 		// This is an unexpected format, we always expect the limits string to be 24-long
 		log.Println(limitParts)
 	}
 
-	return result
+	return result, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseSmcAddressPayload() *models.SmcAddressParams {
-	smcUID := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.SMCUIDRegex)
-	physicalAddress := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.PhysicalAddressRegex)
-	logicalAddress := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.LogicalAddressRegex)
-	shortAddressString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.ShortAddressRegex)
+func (messageEntryParser *MessageEntryParser) parseSmcAddressPayload() (*models.SmcAddressParams, error) {
+	smcUID := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, smcUIDFieldRegex)
+	physicalAddress := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, physicalAddressRegex)
+	logicalAddress := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, logicalAddressRegex)
+	shortAddressString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, shortAddressRegex)
 	if smcUID == "" && physicalAddress == "" && logicalAddress == "" && shortAddressString == "" {
-		return nil
+		return nil, nil
 	}
 
-	lastJoiningDate := common.ParseDateTimeField(messageEntryParser.line.Rest, formats.LastJoiningDateRegex)
-	shortAddress := common.TryParseIntFromString(shortAddressString)
+	lastJoiningDate, err := common.ParseDateTimeField(
+		messageEntryParser.line.Rest, lastJoiningDateRegex, messageEntryParser.line.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	shortAddress, err := common.TryParseIntFromString(shortAddressString)
+	if err != nil {
+		return nil, err
+	}
 
 	result := models.SmcAddressParams{
 		SmcUID:          smcUID,
@@ -505,31 +665,39 @@ func (messageEntryParser *MessageEntryParser) parseSmcAddressPayload() *models.S
 		LogicalAddress:  logicalAddress,
 		ShortAddress:    shortAddress,
 		LastJoiningDate: lastJoiningDate}
-	return &result
+	return &result, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parseSmcConfigPayload() *models.SmcConfigPayload {
+func (messageEntryParser *MessageEntryParser) parseSmcConfigPayload() (*models.SmcConfigPayload, error) {
 	customerSerialNumber := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.CustomerSerialNumberRegex,
+		customerSerialNumberRegex,
 	)
-	physicalAddress := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.PhysicalAddressRegex)
-	smcStatus := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.SmcStatusRegex)
-	nextHopString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.NextHopRegex)
+	physicalAddress := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, physicalAddressRegex)
+	smcStatus := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, smcStatusRegex)
+	nextHopString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, nextHopRegex)
 
 	if customerSerialNumber == "" && physicalAddress == "" && smcStatus == "" && nextHopString == "" {
-		return nil
+		return nil, nil
 	}
 
-	currentApp1Fw := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.CurrentApp1FwRegex)
-	currentApp2Fw := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.CurrentApp2FwRegex)
-	currentPlcFw := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.CurrentPlcFwRegex)
+	currentApp1Fw := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, currentApp1FwRegex)
+	currentApp2Fw := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, currentApp2FwRegex)
+	currentPlcFw := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, currentPlcFwRegex)
 
-	lastSuccessfulDlmsResponseDate := common.ParseDateTimeField(
+	lastSuccessfulDlmsResponseDate, err := common.ParseDateTimeField(
 		messageEntryParser.line.Rest,
-		formats.LastSuccessfulRespDateRegex,
+		lastSuccessfulRespDateRegex,
+		messageEntryParser.line.Location,
 	)
-	nextHop := common.TryParseIntFromString(nextHopString)
+	if err != nil {
+		return nil, err
+	}
+
+	nextHop, err := common.TryParseIntFromString(nextHopString)
+	if err != nil {
+		return nil, err
+	}
 
 	result := models.SmcConfigPayload{}
 	result.CurrentApp1Fw = currentApp1Fw
@@ -541,29 +709,40 @@ func (messageEntryParser *MessageEntryParser) parseSmcConfigPayload() *models.Sm
 	result.NextHop = nextHop
 	result.LastSuccessfulDlmsResponseDate = lastSuccessfulDlmsResponseDate
 
-	return &result
+	return &result, nil
 }
 
-func (messageEntryParser *MessageEntryParser) parsePodConfigPayload() *models.PodConfigPayload {
-	serialNumberString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.SerialNumberRegex)
-	phaseString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.PhaseRegex)
-	positionInSmcString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, formats.PositionInSmcRegex)
+func (messageEntryParser *MessageEntryParser) parsePodConfigPayload() (*models.PodConfigPayload, error) {
+	serialNumberString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, serialNumberRegex)
+	phaseString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, phaseRegex)
+	positionInSmcString := common.ParseFieldInBracketsAsString(messageEntryParser.line.Rest, positionInSmcRegex)
 	softwareFirmwareVersion := common.ParseFieldInBracketsAsString(
 		messageEntryParser.line.Rest,
-		formats.SoftwareFirmwareVersionRegex,
+		softwareFirmwareVersionRegex,
 	)
 	if serialNumberString == "" && phaseString == "" && positionInSmcString == "" && softwareFirmwareVersion == "" {
-		return nil
+		return nil, nil
 	}
 
-	serialNumber := common.TryParseIntFromString(serialNumberString)
-	phase := common.TryParseIntFromString(phaseString)
-	positionInSmc := common.TryParseIntFromString(positionInSmcString)
+	serialNumber, err := common.TryParseIntFromString(serialNumberString)
+	if err != nil {
+		return nil, err
+	}
+
+	phase, err := common.TryParseIntFromString(phaseString)
+	if err != nil {
+		return nil, err
+	}
+
+	positionInSmc, err := common.TryParseIntFromString(positionInSmcString)
+	if err != nil {
+		return nil, err
+	}
 
 	result := models.PodConfigPayload{
 		Phase:                   phase,
 		SerialNumber:            serialNumber,
 		PositionInSmc:           positionInSmc,
 		SoftwareFirmwareVersion: softwareFirmwareVersion}
-	return &result
+	return &result, nil
 }
@@ -2,7 +2,6 @@ package contentparser
 
 import (
 	"github.com/kozgot/go-log-processing/parser/internal/common"
-	"github.com/kozgot/go-log-processing/parser/internal/formats"
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
@@ -15,10 +14,18 @@ func NewErrorParser(line models.EntryWithLevelAndTimestamp) *ErrorParser {
 }
 
 // ParseError parses an error level log entry.
-func (e *ErrorParser) ParseError() *models.ErrorParams {
-	errorCode := e.parseErrorCode()
+func (e *ErrorParser) ParseError() (*models.ErrorParams, error) {
+	errorCode, err := e.parseErrorCode()
+	if err != nil {
+		return nil, err
+	}
+
 	errorMessage := e.parseErrorMessage()
-	errorSeverity := e.parseErrorSeverity()
+	errorSeverity, err := e.parseErrorSeverity()
+	if err != nil {
+		return nil, err
+	}
+
 	errorDesc := e.parseErrorDesc()
 	errorSource := e.parseErrorSource()
 
@@ -29,25 +36,25 @@ func (e *ErrorParser) ParseError() *models.ErrorParams {
 		Description: errorDesc,
 		Source:      errorSource}
 
-	return &errorParams
+	return &errorParams, nil
 }
 
-func (e *ErrorParser) parseErrorCode() int {
-	return common.TryParseIntFromString(common.ParseFieldInBracketsAsString(e.line.Rest, formats.ErrorCodeRegex))
+func (e *ErrorParser) parseErrorCode() (int, error) {
+	return common.TryParseIntFromString(common.ParseFieldInBracketsAsString(e.line.Rest, errorCodeRegex))
 }
 
 func (e *ErrorParser) parseErrorMessage() string {
-	return common.ParseFieldInBracketsAsString(e.line.Rest, formats.MessageRegex)
+	return common.ParseFieldInBracketsAsString(e.line.Rest, messageRegex)
 }
 
 func (e *ErrorParser) parseErrorDesc() string {
-	return common.ParseFieldInBracketsAsString(e.line.Rest, formats.ErrorDescRegex)
+	return common.ParseFieldInBracketsAsString(e.line.Rest, errorDescRegex)
 }
 
 func (e *ErrorParser) parseErrorSource() string {
-	return common.ParseFieldInBracketsAsString(e.line.Rest, formats.ErrorSourceRegex)
+	return common.ParseFieldInBracketsAsString(e.line.Rest, errorSourceRegex)
 }
 
-func (e *ErrorParser) parseErrorSeverity() int {
-	return common.TryParseIntFromString(common.ParseFieldInBracketsAsString(e.line.Rest, formats.ErrorSeverityRegex))
+func (e *ErrorParser) parseErrorSeverity() (int, error) {
+	return common.TryParseIntFromString(common.ParseFieldInBracketsAsString(e.line.Rest, errorSeverityRegex))
 }
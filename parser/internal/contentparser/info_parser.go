@@ -1,6 +1,9 @@
 package contentparser
 
 import (
+	"strings"
+
+	"github.com/kozgot/go-log-processing/parser/internal/formats"
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
@@ -34,71 +37,117 @@ func NewInfoParser(line models.EntryWithLevelAndTimestamp) *InfoParser {
 	return &inforParser
 }
 
-func (infoParser *InfoParser) ParseInfo() *models.InfoParams {
+// ParseInfo parses an INFO entry with the parser of its entry type. The entry types are tried in a fixed order,
+// but a parser only runs if the entry contains the marker of its entry type, so the regular expressions
+// of the other entry types are not evaluated. Returns an error if a field of the entry is malformed.
+func (infoParser *InfoParser) ParseInfo() (*models.InfoParams, error) {
 	infoParams := models.InfoParams{}
 
-	routingMessage := infoParser.routingEntryParser.Parse()
-	if routingMessage != nil {
-		infoParams.RoutingMessage = routingMessage
-		infoParams.EntryType = models.Routing
-		return &infoParams
+	if infoParser.contains(formats.RoutingTableMarker) {
+		routingMessage, err := infoParser.routingEntryParser.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		if routingMessage != nil {
+			infoParams.RoutingMessage = routingMessage
+			infoParams.EntryType = models.Routing
+			return &infoParams, nil
+		}
 	}
 
-	joinMessage := infoParser.smcJoinEntryParser.Parse()
-	if joinMessage != nil {
-		infoParams.JoinMessage = joinMessage
-		infoParams.EntryType = models.SMCJoin
-		return &infoParams
+	if infoParser.contains(formats.SmcJoinRegex) {
+		joinMessage, err := infoParser.smcJoinEntryParser.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		if joinMessage != nil {
+			infoParams.JoinMessage = joinMessage
+			infoParams.EntryType = models.SMCJoin
+			return &infoParams, nil
+		}
 	}
 
-	statusMessage := infoParser.statusEntryParser.Parse()
-	if statusMessage != nil {
-		infoParams.StatusMessage = statusMessage
-		infoParams.EntryType = models.NetworkStatus
-		return &infoParams
+	if infoParser.contains(formats.StatusByteMarker) {
+		statusMessage := infoParser.statusEntryParser.Parse()
+		if statusMessage != nil {
+			infoParams.StatusMessage = statusMessage
+			infoParams.EntryType = models.NetworkStatus
+			return &infoParams, nil
+		}
 	}
 
-	dcMessage := infoParser.messageEntryParser.Parse()
-	if dcMessage != nil {
-		infoParams.DCMessage = dcMessage
-		infoParams.EntryType = models.DCMessage
-		return &infoParams
+	if infoParser.contains(formats.IncomingMessageMarker) || infoParser.contains(formats.OutGoingMessageMarker) {
+		dcMessage, err := infoParser.messageEntryParser.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		if dcMessage != nil {
+			infoParams.DCMessage = dcMessage
+			infoParams.EntryType = models.DCMessage
+			return &infoParams, nil
+		}
 	}
 
-	connectionAttempt := infoParser.connectionAttemptParser.Parse()
-	if connectionAttempt != nil {
-		infoParams.ConnectionAttempt = connectionAttempt
-		infoParams.EntryType = models.ConnectionAttempt
-		return &infoParams
+	if infoParser.contains(formats.ConnectionAttemptPrefix) {
+		connectionAttempt := infoParser.connectionAttemptParser.Parse()
+		if connectionAttempt != nil {
+			infoParams.ConnectionAttempt = connectionAttempt
+			infoParams.EntryType = models.ConnectionAttempt
+			return &infoParams, nil
+		}
 	}
 
-	configUpdate := infoParser.configUpdateParser.Parse()
-	if configUpdate != nil {
-		infoParams.SmcConfigUpdate = configUpdate
-		infoParams.EntryType = models.SmcConfigUpdate
-		return &infoParams
+	if infoParser.contains(formats.SmcConfigUpdatePrefix) {
+		configUpdate, err := infoParser.configUpdateParser.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		if configUpdate != nil {
+			infoParams.SmcConfigUpdate = configUpdate
+			infoParams.EntryType = models.SmcConfigUpdate
+			return &infoParams, nil
+		}
 	}
 
-	connectionReleased := infoParser.connectionReleasedEntryParser.Parse()
-	if connectionReleased != nil {
-		infoParams.ConnectionReleased = connectionReleased
-		infoParams.EntryType = models.ConnectionReleased
-		return &infoParams
+	if infoParser.contains(formats.ConnectionReleasedPrefix) {
+		connectionReleased := infoParser.connectionReleasedEntryParser.Parse()
+		if connectionReleased != nil {
+			infoParams.ConnectionReleased = connectionReleased
+			infoParams.EntryType = models.ConnectionReleased
+			return &infoParams, nil
+		}
 	}
 
-	initConnectionParams := infoParser.initConnectionEntryParser.Parse()
-	if initConnectionParams != nil {
-		infoParams.InitConnection = initConnectionParams
-		infoParams.EntryType = models.InitDLMSConnection
-		return &infoParams
+	if infoParser.contains(formats.InitConnectionPrefix) {
+		initConnectionParams := infoParser.initConnectionEntryParser.Parse()
+		if initConnectionParams != nil {
+			infoParams.InitConnection = initConnectionParams
+			infoParams.EntryType = models.InitDLMSConnection
+			return &infoParams, nil
+		}
 	}
 
-	internalDiagnosticsEntry := infoParser.internalDiagnosticsEntryParser.Parse()
-	if internalDiagnosticsEntry != nil {
-		infoParams.InternalDiagnosticsData = internalDiagnosticsEntry
-		infoParams.EntryType = models.InternalDiagnostics
-		return &infoParams
+	if infoParser.contains(formats.SmcInternalDiagnosticsPrefix) {
+		internalDiagnosticsEntry, err := infoParser.internalDiagnosticsEntryParser.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		if internalDiagnosticsEntry != nil {
+			infoParams.InternalDiagnosticsData = internalDiagnosticsEntry
+			infoParams.EntryType = models.InternalDiagnostics
+			return &infoParams, nil
+		}
 	}
 
-	return &infoParams
+	return &infoParams, nil
+}
+
+// contains checks if the entry contains the marker of an entry type.
+func (infoParser *InfoParser) contains(marker string) bool {
+	return strings.Contains(infoParser.line.Rest, marker)
 }
@@ -12,18 +12,23 @@ type InternalDiagnosticsEntryParser struct {
 	line models.EntryWithLevelAndTimestamp
 }
 
-func (i *InternalDiagnosticsEntryParser) Parse() *models.InternalDiagnosticsData {
+func (i *InternalDiagnosticsEntryParser) Parse() (*models.InternalDiagnosticsData, error) {
 	if strings.Contains(i.line.Rest, formats.SmcInternalDiagnosticsPrefix) {
 		// the entry looks like this:
 		// SMC internal diagnostics smc_uid[dc18-smc32] last_successful_dlms_response_date[n/a] (file_name...)
 		diagnosticsData := models.InternalDiagnosticsData{}
-		smcUID := common.ParseFieldInBracketsAsString(i.line.Rest, formats.SMCUIDRegex)
-		lastSuccessfulDlmsResponseDate := common.ParseDateTimeField(i.line.Rest, formats.LastSuccessfulDlmsResponseDateRegex)
+		smcUID := common.ParseFieldInBracketsAsString(i.line.Rest, smcUIDFieldRegex)
+		lastSuccessfulDlmsResponseDate, err := common.ParseDateTimeField(
+			i.line.Rest, lastSuccessfulDlmsResponseDateRegex, i.line.Location)
+		if err != nil {
+			return nil, err
+		}
+
 		diagnosticsData.SmcUID = smcUID
 		diagnosticsData.LastSuccessfulDlmsResponseDate = lastSuccessfulDlmsResponseDate
 
-		return &diagnosticsData
+		return &diagnosticsData, nil
 	}
 
-	return nil
+	return nil, nil
 }
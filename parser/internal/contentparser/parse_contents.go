@@ -5,37 +5,69 @@ import (
 )
 
 // ParseEntryContents extracts the custom contents of a log entry.
-func ParseEntryContents(line models.EntryWithLevelAndTimestamp) *models.ParsedLogEntry {
+// Returns nil if the entry is not recognised, and an error if a field of the entry is malformed.
+func ParseEntryContents(line models.EntryWithLevelAndTimestamp) (*models.ParsedLogEntry, error) {
 	parsedLine := models.ParsedLogEntry{
 		Level:     line.Level,
 		Timestamp: line.Timestamp}
 
-	infoParser := NewInfoParser(line)
-	warningParser := NewWarningParser(line)
-	errorParser := NewErrorParser(line)
+	var err error
 
+	// Only the parser of the log level of the entry is created.
 	switch line.Level {
 	case "ERROR":
-		errorParams := errorParser.ParseError()
-		parsedLine.ErrorParams = errorParams
+		parsedLine.ErrorParams, err = NewErrorParser(line).ParseError()
 
 	case "WARN":
-		warning := warningParser.ParseWarn()
-		if warning == nil {
-			return nil
+		parsedLine.WarningParams, err = NewWarningParser(line).ParseWarn()
+		if err == nil && parsedLine.WarningParams == nil {
+			return nil, nil
 		}
-		parsedLine.WarningParams = warning
 
 	// Log entries with 'WARNING' log level come from a different log file,
 	// and they have a completely different format, so they are handled separately.
 	case "WARNING":
-		warning := warningParser.ParseWarning()
-		parsedLine.WarningParams = warning
+		parsedLine.WarningParams, err = NewWarningParser(line).ParseWarning()
 
 	case "INFO":
-		info := infoParser.ParseInfo()
-		parsedLine.InfoParams = info
+		parsedLine.InfoParams, err = NewInfoParser(line).ParseInfo()
 	}
 
-	return &parsedLine
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsedLine, nil
+}
+
+// ParseVerboseEntryContents extracts the contents of a VERBOSE log entry,
+// returns nil if it is not an SMC state change or a task launch entry, the other VERBOSE entries are not parsed.
+func ParseVerboseEntryContents(line models.EntryWithLevelAndTimestamp) (*models.ParsedLogEntry, error) {
+	infoParams := models.InfoParams{}
+
+	stateChangeParser := SmcStateChangeParser{line: line}
+	stateChange, err := stateChangeParser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	if stateChange != nil {
+		infoParams.EntryType = models.SmcStateChange
+		infoParams.StateChange = stateChange
+	} else {
+		taskLaunchParser := TaskLaunchParser{line: line}
+		taskLaunch, err := taskLaunchParser.Parse()
+		if err != nil || taskLaunch == nil {
+			return nil, err
+		}
+
+		infoParams.EntryType = models.TaskLaunch
+		infoParams.TaskLaunch = taskLaunch
+	}
+
+	return &models.ParsedLogEntry{
+		Level:      line.Level,
+		Timestamp:  line.Timestamp,
+		InfoParams: &infoParams,
+	}, nil
 }
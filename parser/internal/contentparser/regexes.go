@@ -0,0 +1,113 @@
+package contentparser
+
+import (
+	"regexp"
+
+	"github.com/kozgot/go-log-processing/parser/internal/formats"
+)
+
+// The regular expressions of the entry formats are compiled once, when the package is initialized.
+var (
+	anyLettersBetweenBracketsRegex      = regexp.MustCompile(formats.AnyLettersBetweenBrackets)
+	atFieldRegex                        = regexp.MustCompile(formats.AtRegex)
+	clientIDRegex                       = regexp.MustCompile(formats.ClientIDRegex)
+	connectedRegex                      = regexp.MustCompile(formats.ConnectedRegex)
+	connectOrDisconnectTypeRegex        = regexp.MustCompile(formats.ConnectOrDisconnectTypeRegex)
+	connectToPLCDestAddressRegex        = regexp.MustCompile(formats.ConnectToPLCDestAddressRegex)
+	connectToPLCIfaceRegex              = regexp.MustCompile(formats.ConnectToPLCIfaceRegex)
+	creationTimeRegex                   = regexp.MustCompile(formats.CreationTimeRegex)
+	currentApp1FwRegex                  = regexp.MustCompile(formats.CurrentApp1FwRegex)
+	currentApp2FwRegex                  = regexp.MustCompile(formats.CurrentApp2FwRegex)
+	currentPlcFwRegex                   = regexp.MustCompile(formats.CurrentPlcFwRegex)
+	currentRegex                        = regexp.MustCompile(formats.CurrentRegex)
+	customerSerialNumberRegex           = regexp.MustCompile(formats.CustomerSerialNumberRegex)
+	dataPublishRegex                    = regexp.MustCompile(formats.DataPublishRegex)
+	dateTimeFieldRegex                  = regexp.MustCompile(formats.DateTimeFieldRegex)
+	dcDistroTargetFirmwareVersionRegex  = regexp.MustCompile(formats.DcDistroTargetFirmwareVersionRegex)
+	dcUIDRegex                          = regexp.MustCompile(formats.DcUIDRegex)
+	dlmsErrorRegex                      = regexp.MustCompile(formats.DLMSErrorRegex)
+	dlmsRequestTimeRegex                = regexp.MustCompile(formats.DLMSRequestTimeRegex)
+	dlmsResponseTimeRegex               = regexp.MustCompile(formats.DLMSResponseTimeRegex)
+	errorCodeRegex                      = regexp.MustCompile(formats.ErrorCodeRegex)
+	errorDescRegex                      = regexp.MustCompile(formats.ErrorDescRegex)
+	errorSeverityRegex                  = regexp.MustCompile(formats.ErrorSeverityRegex)
+	errorSourceRegex                    = regexp.MustCompile(formats.ErrorSourceRegex)
+	fileNameFieldRegex                  = regexp.MustCompile(formats.FileNameRegex)
+	frequencyBandChangedRegex           = regexp.MustCompile(formats.FrequencyBandChangedRegex)
+	frequencyBandRollbackDoneRegex      = regexp.MustCompile(formats.FrequencyBandRollbackDoneRegex)
+	globalFtpAddressRegex               = regexp.MustCompile(formats.GlobalFtpAddressRegex)
+	hopCountRegex                       = regexp.MustCompile(formats.HopCountRegex)
+	hourlyEnergyLimitsRegex             = regexp.MustCompile(formats.HourlyEnergyLimitsRegex)
+	incomingMessageSourceRegex          = regexp.MustCompile(formats.IncomingMessageSourceRegex)
+	incomingMessageTypeRegex            = regexp.MustCompile(formats.IncomingMessageTypeRegex)
+	indexCollectionRegex                = regexp.MustCompile(formats.IndexCollectionRegex)
+	indexProfileCaptureObjectsRegex     = regexp.MustCompile(formats.IndexProfileCaptureObjectsRegex)
+	indexProfileCapturePeriodRegex      = regexp.MustCompile(formats.IndexProfileCapturePeriodRegex)
+	inServiceRegex                      = regexp.MustCompile(formats.InServiceRegex)
+	joinStatusResponseRegex             = regexp.MustCompile(formats.JoinStatusResponseRegex)
+	joinTypeRegex                       = regexp.MustCompile(formats.JoinTypeRegex)
+	lastDcStartTimeRegex                = regexp.MustCompile(formats.LastDcStartTimeRegex)
+	lastJoiningDateRegex                = regexp.MustCompile(formats.LastJoiningDateRegex)
+	lastServerCommunicationTimeRegex    = regexp.MustCompile(formats.LastServerCommunicationTimeRegex)
+	lastSuccessfulDlmsResponseDateRegex = regexp.MustCompile(formats.LastSuccessfulDlmsResponseDateRegex)
+	lastSuccessfulRespDateRegex         = regexp.MustCompile(formats.LastSuccessfulRespDateRegex)
+	loadSheddingDailyEnergyBudgetRegex  = regexp.MustCompile(formats.LoadSheddingDailyEnergyBudgetRegex)
+	localHourlyEnergyLimitsRegex        = regexp.MustCompile(formats.LocalHourlyEnergyLimitsRegex)
+	localityRegex                       = regexp.MustCompile(formats.LocalityRegex)
+	localSheddingDailyEnergyBudgetRegex = regexp.MustCompile(formats.LocalSheddingDailyEnergyBudgetRegex)
+	logicalAddressRegex                 = regexp.MustCompile(formats.LogicalAddressRegex)
+	maxActivePowerRegex                 = regexp.MustCompile(formats.MaxActivePowerRegex)
+	messageRegex                        = regexp.MustCompile(formats.MessageRegex)
+	meterModeRegex                      = regexp.MustCompile(formats.MeterModeRegex)
+	minLaunchTimeRegex                  = regexp.MustCompile(formats.MinLaunchTimeRegex)
+	nameRegex                           = regexp.MustCompile(formats.NameRegex)
+	nextHopAddressRegex                 = regexp.MustCompile(formats.NextHopAddressRegex)
+	nextHopRegex                        = regexp.MustCompile(formats.NextHopRegex)
+	outGoingMessageDestRegex            = regexp.MustCompile(formats.OutGoingMessageDestRegex)
+	outGoingMessageTypeRegex            = regexp.MustCompile(formats.OutGoingMessageTypeRegex)
+	phaseRegex                          = regexp.MustCompile(formats.PhaseRegex)
+	physicalAddressRegex                = regexp.MustCompile(formats.PhysicalAddressRegex)
+	podUIDRegex                         = regexp.MustCompile(formats.PodUIDRegex)
+	positionInSmcRegex                  = regexp.MustCompile(formats.PositionInSmcRegex)
+	previousTimeRegex                   = regexp.MustCompile(formats.PreviousTimeRegex)
+	previousValueRegex                  = regexp.MustCompile(formats.PreviousValueRegex)
+	regionRegex                         = regexp.MustCompile(formats.RegionRegex)
+	routeCostRegex                      = regexp.MustCompile(formats.RouteCostRegex)
+	routingAddressRegex                 = regexp.MustCompile(formats.RoutingAddressRegex)
+	routingTableRegex                   = regexp.MustCompile(formats.RoutingTableRegex)
+	serialNumberRegex                   = regexp.MustCompile(formats.SerialNumberRegex)
+	serviceLevelIDRegex                 = regexp.MustCompile(formats.ServiceLevelIDRegex)
+	shortAddressRegex                   = regexp.MustCompile(formats.ShortAddressRegex)
+	smcJoinRegex                        = regexp.MustCompile(formats.SmcJoinRegex)
+	smcStateChangeNewStateRegex         = regexp.MustCompile(formats.SmcStateChangeNewStateRegex)
+	smcStateChangeSmcUIDRegex           = regexp.MustCompile(formats.SmcStateChangeSmcUIDRegex)
+	smcStatusRegex                      = regexp.MustCompile(formats.SmcStatusRegex)
+	smcUIDFieldRegex                    = regexp.MustCompile(formats.SMCUIDRegex)
+	smcUIDRegex                         = regexp.MustCompile(formats.SmcUIDRegex)
+	softwareFirmwareVersionRegex        = regexp.MustCompile(formats.SoftwareFirmwareVersionRegex)
+	startHourDailyCycleRegex            = regexp.MustCompile(formats.StartHourDailyCycleRegex)
+	statisticsSourceIDRegex             = regexp.MustCompile(formats.StatisticsSourceIDRegex)
+	statisticsTypeRegex                 = regexp.MustCompile(formats.StatisticsTypeRegex)
+	statisticsValueRegex                = regexp.MustCompile(formats.StatisticsValueRegex)
+	statusByteRegex                     = regexp.MustCompile(formats.StatusByteRegex)
+	statusMessageRegex                  = regexp.MustCompile(formats.StatusMessageRegex)
+	targetFirmwareVersionRegex          = regexp.MustCompile(formats.TargetFirmwareVersionRegex)
+	taskFailedWarnRegex                 = regexp.MustCompile(formats.TaskFailedWarnRegex)
+	taskThreadRegex                     = regexp.MustCompile(formats.TaskThreadRegex)
+	taskTypeRegex                       = regexp.MustCompile(formats.TaskTypeRegex)
+	timeoutProtocolRegex                = regexp.MustCompile(formats.TimeoutProtocolRegex)
+	timeoutRegex                        = regexp.MustCompile(formats.TimeoutRegex)
+	timeoutURLRegex                     = regexp.MustCompile(formats.TimeoutURLRegex)
+	timeTicksRegex                      = regexp.MustCompile(formats.TimeTicksRegex)
+	timezoneRegex                       = regexp.MustCompile(formats.TimezoneRegex)
+	topicRegex                          = regexp.MustCompile(formats.TopicRegex)
+	totalRegex                          = regexp.MustCompile(formats.TotalRegex)
+	uidRegex                            = regexp.MustCompile(formats.UIDRegex)
+	urlRegex                            = regexp.MustCompile(formats.URLRegex)
+	validTimeRegex                      = regexp.MustCompile(formats.ValidTimeRegex)
+	valueRegex                          = regexp.MustCompile(formats.ValueRegex)
+	warningNameRegex                    = regexp.MustCompile(formats.WarningNameRegex)
+	warningPriorityRegex                = regexp.MustCompile(formats.WarningPriorityRegex)
+	warningRetryRegex                   = regexp.MustCompile(formats.WarningRetryRegex)
+	weakLinkRegex                       = regexp.MustCompile(formats.WeakLinkRegex)
+)
@@ -2,7 +2,6 @@ package contentparser
 
 import (
 	"github.com/kozgot/go-log-processing/parser/internal/common"
-	"github.com/kozgot/go-log-processing/parser/internal/formats"
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
@@ -10,23 +9,40 @@ type RoutingMessageParser struct {
 	line models.EntryWithLevelAndTimestamp
 }
 
-func (r *RoutingMessageParser) Parse() *models.RoutingTableParams {
-	isRoutingTableLine := common.ParseFieldAsString(r.line.Rest, formats.RoutingTableRegex) != ""
+func (r *RoutingMessageParser) Parse() (*models.RoutingTableParams, error) {
+	isRoutingTableLine := common.ParseFieldAsString(r.line.Rest, routingTableRegex) != ""
 	if !isRoutingTableLine {
-		return nil
+		return nil, nil
 	}
 
+	var err error
+
 	routingtableLine := models.RoutingTableParams{}
-	routingtableLine.Address = common.ParseFieldInBracketsAsString(r.line.Rest, formats.RoutingAddressRegex)
-	routingtableLine.NextHopAddress = common.ParseFieldInBracketsAsString(r.line.Rest, formats.NextHopAddressRegex)
-	routingtableLine.RouteCost = common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(r.line.Rest, formats.RouteCostRegex))
-	routingtableLine.ValidTimeMins = common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(r.line.Rest, formats.ValidTimeRegex))
-	routingtableLine.WeakLink = common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(r.line.Rest, formats.WeakLinkRegex))
-	routingtableLine.HopCount = common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(r.line.Rest, formats.HopCountRegex))
-
-	return &routingtableLine
+	routingtableLine.Address = common.ParseFieldInBracketsAsString(r.line.Rest, routingAddressRegex)
+	routingtableLine.NextHopAddress = common.ParseFieldInBracketsAsString(r.line.Rest, nextHopAddressRegex)
+	routingtableLine.RouteCost, err = common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(r.line.Rest, routeCostRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	routingtableLine.ValidTimeMins, err = common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(r.line.Rest, validTimeRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	routingtableLine.WeakLink, err = common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(r.line.Rest, weakLinkRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	routingtableLine.HopCount, err = common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(r.line.Rest, hopCountRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	return &routingtableLine, nil
 }
@@ -14,23 +14,32 @@ type SmcConfigUpdateParser struct {
 }
 
 // Parse parses an SMC config update log entry.
-func (s *SmcConfigUpdateParser) Parse() *models.SmcConfigUpdateParams {
+func (s *SmcConfigUpdateParser) Parse() (*models.SmcConfigUpdateParams, error) {
 	if strings.Contains(s.line.Rest, formats.SmcConfigUpdatePrefix) {
 		smcConfigUpdate := models.SmcConfigUpdateParams{}
+		var err error
 
-		smcConfigUpdate.SmcUID = common.ParseFieldInBracketsAsString(s.line.Rest, formats.SmcUIDRegex)
-		smcConfigUpdate.PhysicalAddress = common.ParseFieldInBracketsAsString(s.line.Rest, formats.PhysicalAddressRegex)
-		smcConfigUpdate.LogicalAddress = common.ParseFieldInBracketsAsString(s.line.Rest, formats.LogicalAddressRegex)
+		smcConfigUpdate.SmcUID = common.ParseFieldInBracketsAsString(s.line.Rest, smcUIDRegex)
+		smcConfigUpdate.PhysicalAddress = common.ParseFieldInBracketsAsString(s.line.Rest, physicalAddressRegex)
+		smcConfigUpdate.LogicalAddress = common.ParseFieldInBracketsAsString(s.line.Rest, logicalAddressRegex)
 
-		smcConfigUpdate.ShortAddress = common.TryParseIntFromString(
-			common.ParseFieldInBracketsAsString(s.line.Rest, formats.ShortAddressRegex))
+		smcConfigUpdate.ShortAddress, err = common.TryParseIntFromString(
+			common.ParseFieldInBracketsAsString(s.line.Rest, shortAddressRegex))
+		if err != nil {
+			return nil, err
+		}
 
-		smcConfigUpdate.LastJoiningDate = common.ParseDateTimeField(s.line.Rest, formats.LastJoiningDateRegex)
-		smcUID := common.ParseFieldInBracketsAsString(s.line.Rest, formats.SmcUIDRegex)
+		smcConfigUpdate.LastJoiningDate, err = common.ParseDateTimeField(
+			s.line.Rest, lastJoiningDateRegex, s.line.Location)
+		if err != nil {
+			return nil, err
+		}
+
+		smcUID := common.ParseFieldInBracketsAsString(s.line.Rest, smcUIDRegex)
 		smcConfigUpdate.SmcUID = smcUID
 
-		return &smcConfigUpdate
+		return &smcConfigUpdate, nil
 	}
 
-	return nil
+	return nil, nil
 }
@@ -1,7 +1,7 @@
 package contentparser
 
 import (
-	"log"
+	"errors"
 	"strings"
 
 	"github.com/kozgot/go-log-processing/parser/internal/common"
@@ -9,15 +9,17 @@ import (
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
+var errMissingJoinDirection = errors.New("there was no direction indicator in join message")
+
 type SmcJoinEntryParser struct {
 	line models.EntryWithLevelAndTimestamp
 }
 
-func (s *SmcJoinEntryParser) Parse() *models.SmcJoinMessageParams {
-	smcJoinstring := common.ParseFieldAsString(s.line.Rest, formats.SmcJoinRegex)
+func (s *SmcJoinEntryParser) Parse() (*models.SmcJoinMessageParams, error) {
+	smcJoinstring := common.ParseFieldAsString(s.line.Rest, smcJoinRegex)
 	isSmcJoinLine := smcJoinstring != ""
 	if !isSmcJoinLine {
-		return nil
+		return nil, nil
 	}
 
 	smcJoinLine := models.SmcJoinMessageParams{}
@@ -31,30 +33,39 @@ func (s *SmcJoinEntryParser) Parse() *models.SmcJoinMessageParams {
 	messageParts := strings.Split(lineRest, formats.InComingArrow)
 	expectedMessagePartsLength := 2
 	if len(messageParts) < expectedMessagePartsLength {
-		log.Fatalf("There was no direction indicator in Join message: %s", s.line.Rest)
+		return nil, errMissingJoinDirection
 	}
 
 	responseString := messageParts[0]
-	response := common.ParseFieldInBracketsAsString(responseString, formats.AnyLettersBetweenBrackets)
+	response := common.ParseFieldInBracketsAsString(responseString, anyLettersBetweenBracketsRegex)
 	smcJoinLine.Response = response
 
-	status := common.ParseFieldAsString(responseString, formats.JoinStatusResponseRegex)
+	status := common.ParseFieldAsString(responseString, joinStatusResponseRegex)
 	smcJoinLine.Ok = status == "OK"
 
 	payloadString := strings.TrimLeft(messageParts[1], " [")
 	payloadString = strings.TrimRight(payloadString, "] ")
 
-	smcJoinLine.JoinType = common.ParseFieldInBracketsAsString(payloadString, formats.JoinTypeRegex)
+	smcJoinLine.JoinType = common.ParseFieldInBracketsAsString(payloadString, joinTypeRegex)
 	smcAddress := models.SmcAddressParams{}
 
-	smcAddress.SmcUID = common.ParseFieldInBracketsAsString(payloadString, formats.SmcUIDRegex)
-	smcAddress.PhysicalAddress = common.ParseFieldInBracketsAsString(payloadString, formats.PhysicalAddressRegex)
-	smcAddress.LogicalAddress = common.ParseFieldInBracketsAsString(payloadString, formats.LogicalAddressRegex)
+	smcAddress.SmcUID = common.ParseFieldInBracketsAsString(payloadString, smcUIDRegex)
+	smcAddress.PhysicalAddress = common.ParseFieldInBracketsAsString(payloadString, physicalAddressRegex)
+	smcAddress.LogicalAddress = common.ParseFieldInBracketsAsString(payloadString, logicalAddressRegex)
+
+	var err error
+	smcAddress.ShortAddress, err = common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(payloadString, shortAddressRegex))
+	if err != nil {
+		return nil, err
+	}
 
-	smcAddress.ShortAddress = common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(payloadString, formats.ShortAddressRegex))
+	smcAddress.LastJoiningDate, err = common.ParseDateTimeField(
+		s.line.Rest, lastJoiningDateRegex, s.line.Location)
+	if err != nil {
+		return nil, err
+	}
 
-	smcAddress.LastJoiningDate = common.ParseDateTimeField(s.line.Rest, formats.LastJoiningDateRegex)
 	smcJoinLine.SmcAddress = smcAddress
-	return &smcJoinLine
+	return &smcJoinLine, nil
 }
@@ -0,0 +1,38 @@
+package contentparser
+
+import (
+	"strings"
+
+	"github.com/kozgot/go-log-processing/parser/internal/common"
+	"github.com/kozgot/go-log-processing/parser/internal/formats"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+type SmcStateChangeParser struct {
+	line models.EntryWithLevelAndTimestamp
+}
+
+func (s *SmcStateChangeParser) Parse() (*models.SmcStateChangeParams, error) {
+	if !strings.Contains(s.line.Rest, formats.SmcStateChangeText) {
+		return nil, nil
+	}
+
+	// the entry looks like this:
+	// SMC[dc18-smc3] changing state, new state[3038] ((null)::-1225668530)
+	smcUID := common.ParseFieldInBracketsAsString(s.line.Rest, smcStateChangeSmcUIDRegex)
+	stateString := common.ParseFieldInBracketsAsString(s.line.Rest, smcStateChangeNewStateRegex)
+	if smcUID == "" || stateString == "" {
+		return nil, nil
+	}
+
+	state, err := common.TryParseInt64FromString(stateString)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SmcStateChangeParams{
+		SmcUID: smcUID,
+		State:  state,
+		Flags:  models.DecodeSmcStateFlags(state),
+	}, nil
+}
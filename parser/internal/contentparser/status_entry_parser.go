@@ -2,7 +2,6 @@ package contentparser
 
 import (
 	"github.com/kozgot/go-log-processing/parser/internal/common"
-	"github.com/kozgot/go-log-processing/parser/internal/formats"
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
@@ -12,12 +11,12 @@ type StatusEntryParser struct {
 
 func (s *StatusEntryParser) Parse() *models.StatusMessageParams {
 	statusLine := models.StatusMessageParams{}
-	statusLine.StatusByte = common.ParseFieldInBracketsAsString(s.line.Rest, formats.StatusByteRegex)
+	statusLine.StatusByte = common.ParseFieldInBracketsAsString(s.line.Rest, statusByteRegex)
 	if statusLine.StatusByte == "" {
 		return nil
 	}
 
-	statusLine.Message = common.ParseFieldAsString(s.line.Rest, formats.StatusMessageRegex)
+	statusLine.Message = common.ParseFieldAsString(s.line.Rest, statusMessageRegex)
 
 	return &statusLine
 }
@@ -0,0 +1,57 @@
+package contentparser
+
+import (
+	"strings"
+
+	"github.com/kozgot/go-log-processing/parser/internal/common"
+	"github.com/kozgot/go-log-processing/parser/internal/formats"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+type TaskLaunchParser struct {
+	line models.EntryWithLevelAndTimestamp
+}
+
+func (t *TaskLaunchParser) Parse() (*models.TaskLaunchParams, error) {
+	if !strings.Contains(t.line.Rest, formats.TaskLaunchPrefix) {
+		return nil, nil
+	}
+
+	// the entry looks like this:
+	// Launch Task name[update_connection_task] type[connect] name[update_connection_task] smc_uid[dc18-smc3] uid[67]
+	// priority[4294967293] retry[1] creation_time[Wed Jun 10 09:18:38 2020] on thread 2968499248 (...)
+	thread := common.ParseFieldAsString(t.line.Rest, taskThreadRegex)
+
+	uid, err := common.TryParseIntFromString(common.ParseFieldInBracketsAsString(t.line.Rest, uidRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	priority, err := common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(t.line.Rest, warningPriorityRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	retry, err := common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(t.line.Rest, warningRetryRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	creation, err := common.ParseDateTimeField(t.line.Rest, creationTimeRegex, t.line.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TaskLaunchParams{
+		Name:     common.ParseFieldInBracketsAsString(t.line.Rest, warningNameRegex),
+		Type:     common.ParseFieldInBracketsAsString(t.line.Rest, taskTypeRegex),
+		SmcUID:   common.ParseFieldInBracketsAsString(t.line.Rest, smcUIDFieldRegex),
+		UID:      uid,
+		Priority: priority,
+		Retry:    retry,
+		Creation: creation,
+		Thread:   strings.TrimPrefix(thread, "on thread "),
+	}, nil
+}
@@ -1,7 +1,6 @@
 package contentparser
 
 import (
-	"regexp"
 	"strings"
 	"time"
 
@@ -20,23 +19,33 @@ func NewWarningParser(line models.EntryWithLevelAndTimestamp) *WarningParser {
 }
 
 // ParseWarning parses an smc join log entry with a WARNING log level.
-func (warningParser *WarningParser) ParseWarning() *models.WarningParams {
+func (warningParser *WarningParser) ParseWarning() (*models.WarningParams, error) {
 	warningParams := models.WarningParams{}
 	smcJoinEntryParser := SmcJoinEntryParser{line: warningParser.line}
-	warningParams.JoinMessageParams = smcJoinEntryParser.Parse()
+	joinMessageParams, err := smcJoinEntryParser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	warningParams.JoinMessageParams = joinMessageParams
 	warningParams.WarningType = models.JoinRejectedWarning
 
-	return &warningParams
+	return &warningParams, nil
 }
 
-// ParseWarn parses WARN level log entries from the dc_main.log file.
-func (warningParser *WarningParser) ParseWarn() *models.WarningParams {
+// ParseWarn parses WARN level log entries from the dc_main.log file, returns nil if the entry is not recognised.
+func (warningParser *WarningParser) ParseWarn() (*models.WarningParams, error) {
 	warningParams := models.WarningParams{}
 
 	if strings.Contains(warningParser.line.Rest, formats.LostConnectionPrefix) {
-		warningParams.LostConnectionParams = warningParser.parseLostConnectionEntry()
+		lostConnectionParams, err := warningParser.parseLostConnectionEntry()
+		if err != nil {
+			return nil, err
+		}
+
+		warningParams.LostConnectionParams = lostConnectionParams
 		warningParams.WarningType = models.ConnectionLostWarning
-		return &warningParams
+		return &warningParams, nil
 	}
 
 	if strings.Contains(warningParser.line.Rest, formats.TimeoutWarnPrefix) {
@@ -47,24 +56,29 @@ func (warningParser *WarningParser) ParseWarn() *models.WarningParams {
 			warningParams.WarningType = models.TimeoutWarning
 		}
 
-		return &warningParams
+		return &warningParams, nil
 	}
 
 	// we only care for for Task failed warnings from here
-	taskFailedWarnRegex, _ := regexp.Compile(formats.TaskFailedWarnRegex)
 	warn := taskFailedWarnRegex.FindString(warningParser.line.Rest)
 	if warn == "" {
-		return nil
+		return nil, nil
 	}
 
 	// parse SMC UID
 	smcUID := warningParser.parseWarningSMCUID()
 
 	// parse UID
-	uid := warningParser.parseWarningUID()
+	uid, err := warningParser.parseWarningUID()
+	if err != nil {
+		return nil, err
+	}
 
 	// parse Priority
-	priority := warningParser.parsePriority()
+	priority, err := warningParser.parsePriority()
+	if err != nil {
+		return nil, err
+	}
 
 	// parse Name
 	name := warningParser.parseWarningName()
@@ -73,17 +87,29 @@ func (warningParser *WarningParser) ParseWarn() *models.WarningParams {
 	fileName := warningParser.parseFileName()
 
 	// parse Retry
-	retry := warningParser.parseRetry()
+	retry, err := warningParser.parseRetry()
+	if err != nil {
+		return nil, err
+	}
 
 	// parse Creation
-	creationTime := warningParser.parseWarningCreationTime()
+	creationTime, err := warningParser.parseWarningCreationTime()
+	if err != nil {
+		return nil, err
+	}
 
 	// parse MinLaunchTime
-	minLaunchTime := warningParser.parseWarningMinLaunchTime()
+	minLaunchTime, err := warningParser.parseWarningMinLaunchTime()
+	if err != nil {
+		return nil, err
+	}
 
 	// parse inner error params
 	errorParser := NewErrorParser(warningParser.line)
-	details := errorParser.ParseError()
+	details, err := errorParser.ParseError()
+	if err != nil {
+		return nil, err
+	}
 
 	warningParams.TaskFailedWarningParams = &models.TaskFailedWarningParams{
 		SmcUID:        smcUID,
@@ -97,14 +123,17 @@ func (warningParser *WarningParser) ParseWarn() *models.WarningParams {
 		Details:       details,
 	}
 
-	return &warningParams
+	return &warningParams, nil
 }
 
-func (warningParser *WarningParser) parseLostConnectionEntry() *models.LostConnectionParams {
-	result := warningParser.parseLostConnectionParams()
+func (warningParser *WarningParser) parseLostConnectionEntry() (*models.LostConnectionParams, error) {
+	result, err := warningParser.parseLostConnectionParams()
+	if err != nil {
+		return nil, err
+	}
 
 	// Get the reason from this: '...lost due to <unknown reason> (mqtt_connector.cc::54)'
-	if strings.Contains(warningParser.line.Rest, "lost due to ") {
+	if result != nil && strings.Contains(warningParser.line.Rest, "lost due to ") {
 		reasonPart := strings.Split(warningParser.line.Rest, "lost due to ")[1]
 
 		// Trim off the file name in the parentheses.
@@ -113,21 +142,33 @@ func (warningParser *WarningParser) parseLostConnectionEntry() *models.LostConne
 		}
 	}
 
-	return result
+	return result, nil
 }
 
 func (
 	warningParser *WarningParser,
-) parseLostConnectionParams() *models.LostConnectionParams {
-	resultType := common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.ConnectOrDisconnectTypeRegex))
-	clientID := common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.ClientIDRegex)
-	connected := common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.ConnectedRegex)) == 1
-	URL := common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.URLRegex)
-	topic := common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.TopicRegex)
-	timeout := common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.TimeoutRegex))
+) parseLostConnectionParams() (*models.LostConnectionParams, error) {
+	resultType, err := common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(warningParser.line.Rest, connectOrDisconnectTypeRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	clientID := common.ParseFieldInBracketsAsString(warningParser.line.Rest, clientIDRegex)
+	connectedFlag, err := common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(warningParser.line.Rest, connectedRegex))
+	if err != nil {
+		return nil, err
+	}
+
+	connected := connectedFlag == 1
+	URL := common.ParseFieldInBracketsAsString(warningParser.line.Rest, urlRegex)
+	topic := common.ParseFieldInBracketsAsString(warningParser.line.Rest, topicRegex)
+	timeout, err := common.TryParseIntFromString(
+		common.ParseFieldInBracketsAsString(warningParser.line.Rest, timeoutRegex))
+	if err != nil {
+		return nil, err
+	}
 
 	if clientID != "" || resultType != 0 || URL != "" || topic != "" || timeout != 0 {
 		result := models.LostConnectionParams{
@@ -137,10 +178,10 @@ func (
 			Topic:     topic,
 			Timeout:   timeout,
 			Connected: connected}
-		return &result
+		return &result, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
 func (warningParser *WarningParser) parseTimeoutEntry() *models.TimeOutParams {
@@ -150,44 +191,43 @@ func (warningParser *WarningParser) parseTimeoutEntry() *models.TimeOutParams {
 	}
 
 	result := models.TimeOutParams{}
-	result.Protocol = common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.TimeoutProtocolRegex)
-	result.URL = common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.TimeoutURLRegex)
+	result.Protocol = common.ParseFieldInBracketsAsString(warningParser.line.Rest, timeoutProtocolRegex)
+	result.URL = common.ParseFieldInBracketsAsString(warningParser.line.Rest, timeoutURLRegex)
 
 	return &result
 }
 
-func (warningParser *WarningParser) parsePriority() int {
+func (warningParser *WarningParser) parsePriority() (int, error) {
 	return common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.WarningPriorityRegex))
+		common.ParseFieldInBracketsAsString(warningParser.line.Rest, warningPriorityRegex))
 }
 
-func (warningParser *WarningParser) parseRetry() int {
+func (warningParser *WarningParser) parseRetry() (int, error) {
 	return common.TryParseIntFromString(
-		common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.WarningRetryRegex))
+		common.ParseFieldInBracketsAsString(warningParser.line.Rest, warningRetryRegex))
 }
 
-func (warningParser *WarningParser) parseWarningUID() int {
-	return common.TryParseIntFromString(common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.UIDRegex))
+func (warningParser *WarningParser) parseWarningUID() (int, error) {
+	return common.TryParseIntFromString(common.ParseFieldInBracketsAsString(warningParser.line.Rest, uidRegex))
 }
 
 func (warningParser *WarningParser) parseWarningName() string {
-	return common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.WarningNameRegex)
+	return common.ParseFieldInBracketsAsString(warningParser.line.Rest, warningNameRegex)
 }
 
 func (warningParser *WarningParser) parseWarningSMCUID() string {
-	return common.ParseFieldInBracketsAsString(warningParser.line.Rest, formats.SMCUIDRegex)
+	return common.ParseFieldInBracketsAsString(warningParser.line.Rest, smcUIDFieldRegex)
 }
 
-func (warningParser *WarningParser) parseWarningCreationTime() time.Time {
-	return common.ParseDateTimeField(warningParser.line.Rest, formats.CreationTimeRegex)
+func (warningParser *WarningParser) parseWarningCreationTime() (time.Time, error) {
+	return common.ParseDateTimeField(warningParser.line.Rest, creationTimeRegex, warningParser.line.Location)
 }
 
-func (warningParser *WarningParser) parseWarningMinLaunchTime() time.Time {
-	return common.ParseDateTimeField(warningParser.line.Rest, formats.MinLaunchTimeRegex)
+func (warningParser *WarningParser) parseWarningMinLaunchTime() (time.Time, error) {
+	return common.ParseDateTimeField(warningParser.line.Rest, minLaunchTimeRegex, warningParser.line.Location)
 }
 
 func (warningParser *WarningParser) parseFileName() string {
-	fileNameFieldRegex, _ := regexp.Compile(formats.FileNameRegex)
 	fileNameField := fileNameFieldRegex.FindString(warningParser.line.Rest)
 
 	if fileNameField != "" {
@@ -0,0 +1,215 @@
+package decompression
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// Format represents the compression or archive format of a log file.
+type Format int64
+
+const (
+	// Plain is the default value of Format, the file is not compressed.
+	Plain   Format = iota
+	Gzip           // dc_main.log.1.gz
+	Zip            // logs.zip
+	Tar            // logs.tar
+	TarGzip        // logs.tar.gz or logs.tgz
+)
+
+// The number of bytes needed to detect every supported format by its magic bytes.
+const peekSize = 512
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte{'P', 'K', 0x03, 0x04}
+	tarMagic  = []byte("ustar")
+)
+
+// The offset of the magic bytes in the header of a tar archive.
+const tarMagicOffset = 257
+
+// LogFileHandler handles a single decompressed logical log file.
+// The reader is only valid until the handler returns. An error returned by the handler stops the iteration.
+type LogFileHandler func(logFileName string, reader io.Reader) error
+
+// ForEachLogFile detects the compression format of the given file by its extension or its magic bytes,
+// and calls the handler for each logical log file it contains, in order.
+// A plain file is handed over as is, a gzip file is decompressed and named without the .gz extension,
+// and each member of a zip or tar archive is handed over as a separate log file,
+// named after the archive and the path of the member, eg.: logs.zip/dc18/dc_main.log.
+// Archives nested in archives are decompressed as well.
+// Returns an error if the file could not be decompressed, the log files handled before the error are kept.
+// The error of the handler is returned as is.
+func ForEachLogFile(reader io.Reader, fileName string, handler LogFileHandler) error {
+	bufferedReader := bufio.NewReaderSize(reader, peekSize)
+
+	switch DetectFormat(bufferedReader, fileName) {
+	case Gzip:
+		gzipReader, err := newGzipReader(bufferedReader, fileName)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+
+		// A compressed tar archive without the usual extension, eg.: logs.gz.
+		return ForEachLogFile(gzipReader, trimExtension(fileName, ".gz"), handler)
+
+	case TarGzip:
+		gzipReader, err := newGzipReader(bufferedReader, fileName)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+
+		return forEachTarMember(gzipReader, fileName, handler)
+
+	case Tar:
+		return forEachTarMember(bufferedReader, fileName, handler)
+
+	case Zip:
+		return forEachZipMember(bufferedReader, fileName, handler)
+
+	default:
+		return handler(fileName, bufferedReader)
+	}
+}
+
+// DetectFormat detects the compression format of a file.
+// The magic bytes are checked first, the extension of the file name is only used if they are inconclusive.
+func DetectFormat(reader *bufio.Reader, fileName string) Format {
+	header, _ := reader.Peek(peekSize)
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		if hasExtension(fileName, ".tar.gz", ".tgz") {
+			return TarGzip
+		}
+		return Gzip
+
+	case bytes.HasPrefix(header, zipMagic):
+		return Zip
+
+	case len(header) >= tarMagicOffset+len(tarMagic) &&
+		bytes.Equal(header[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic):
+		return Tar
+	}
+
+	// Fall back to the extension, eg.: for empty archives.
+	switch {
+	case hasExtension(fileName, ".tar.gz", ".tgz"):
+		return TarGzip
+	case hasExtension(fileName, ".gz"):
+		return Gzip
+	case hasExtension(fileName, ".zip"):
+		return Zip
+	case hasExtension(fileName, ".tar"):
+		return Tar
+	default:
+		return Plain
+	}
+}
+
+func newGzipReader(reader io.Reader, fileName string) (*gzip.Reader, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress gzip file %s: %w", fileName, err)
+	}
+
+	return gzipReader, nil
+}
+
+func forEachTarMember(reader io.Reader, archiveName string, handler LogFileHandler) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not read tar archive %s: %w", archiveName, err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		err = ForEachLogFile(tarReader, memberName(archiveName, header.Name), handler)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// forEachZipMember reads the members of a zip archive.
+// Zip archives can not be read as a stream, so the archive is spooled to a temporary file first.
+func forEachZipMember(reader io.Reader, archiveName string, handler LogFileHandler) error {
+	tempFile, err := ioutil.TempFile("", "log-archive-*.zip")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file for zip archive %s: %w", archiveName, err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	size, err := io.Copy(tempFile, reader)
+	if err != nil {
+		return fmt.Errorf("could not download zip archive %s: %w", archiveName, err)
+	}
+
+	zipReader, err := zip.NewReader(tempFile, size)
+	if err != nil {
+		return fmt.Errorf("could not read zip archive %s: %w", archiveName, err)
+	}
+
+	for _, member := range zipReader.File {
+		if member.FileInfo().IsDir() {
+			continue
+		}
+
+		memberReader, err := member.Open()
+		if err != nil {
+			return fmt.Errorf("could not open zip archive member %s: %w", member.Name, err)
+		}
+
+		err = ForEachLogFile(memberReader, memberName(archiveName, member.Name), handler)
+		memberReader.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func memberName(archiveName string, name string) string {
+	return archiveName + "/" + strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func hasExtension(fileName string, extensions ...string) bool {
+	lowerCaseName := strings.ToLower(fileName)
+	for _, extension := range extensions {
+		if strings.HasSuffix(lowerCaseName, extension) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func trimExtension(fileName string, extension string) string {
+	if hasExtension(fileName, extension) {
+		return fileName[:len(fileName)-len(extension)]
+	}
+
+	return fileName
+}
@@ -0,0 +1,95 @@
+package dedup
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+// Fingerprint identifies the content of an entry written by a DC.
+type Fingerprint [sha256.Size]byte
+
+// Deduplicator suppresses the entries that have already been published from another log file of the same DC,
+// eg.: from a rotated copy of dc_main.log, or from an overlapping export. It is safe to use from multiple goroutines.
+// The entries repeated in a single file are kept, an entry is only a duplicate if another file of the DC
+// contained it at least as many times. The fingerprints are forgotten when they are not seen for the window.
+type Deduplicator struct {
+	window time.Duration
+
+	mutex        sync.Mutex
+	fingerprints map[Fingerprint]*seenEntry
+	lastSweep    time.Time
+}
+
+// seenEntry contains the number of times an entry has been published and seen in each file.
+type seenEntry struct {
+	published   int
+	occurrences map[string]int
+	lastSeen    time.Time
+}
+
+// NewDeduplicator creates a new Deduplicator that remembers the fingerprints for the given window of parsing time.
+func NewDeduplicator(window time.Duration) *Deduplicator {
+	deduplicator := Deduplicator{
+		window:       window,
+		fingerprints: make(map[Fingerprint]*seenEntry),
+		lastSweep:    time.Now(),
+	}
+
+	return &deduplicator
+}
+
+// NewFingerprint creates the fingerprint of an entry from its DC, its timestamp and its content.
+// The source location and the run ID of the entry are not part of its content.
+func NewFingerprint(dc string, entry models.ParsedLogEntry) Fingerprint {
+	entry.Source = models.SourceLocation{}
+	entry.RunID = ""
+	entry.Timestamp = entry.Timestamp.UTC()
+
+	content := dc + "\n" + entry.Timestamp.Format(time.RFC3339Nano) + "\n" + string(entry.Serialize())
+	return sha256.Sum256([]byte(content))
+}
+
+// IsDuplicate records an entry parsed from a log file, and checks if it has already been published
+// from another file of the same DC. The entry must be published if it is not a duplicate.
+func (deduplicator *Deduplicator) IsDuplicate(fileName string, entry models.ParsedLogEntry) bool {
+	fingerprint := NewFingerprint(models.SourceDC(fileName), entry)
+	now := time.Now()
+
+	deduplicator.mutex.Lock()
+	defer deduplicator.mutex.Unlock()
+
+	deduplicator.sweep(now)
+
+	seen, ok := deduplicator.fingerprints[fingerprint]
+	if !ok {
+		seen = &seenEntry{occurrences: make(map[string]int)}
+		deduplicator.fingerprints[fingerprint] = seen
+	}
+
+	seen.lastSeen = now
+	seen.occurrences[fileName]++
+	if seen.occurrences[fileName] <= seen.published {
+		return true
+	}
+
+	seen.published++
+	return false
+}
+
+// sweep forgets the fingerprints that have not been seen for the window, at most once in every window.
+func (deduplicator *Deduplicator) sweep(now time.Time) {
+	if now.Sub(deduplicator.lastSweep) < deduplicator.window {
+		return
+	}
+
+	for fingerprint, seen := range deduplicator.fingerprints {
+		if now.Sub(seen.lastSeen) >= deduplicator.window {
+			delete(deduplicator.fingerprints, fingerprint)
+		}
+	}
+
+	deduplicator.lastSweep = now
+}
@@ -8,7 +8,6 @@ import (
 	"net/url"
 
 	azblob "github.com/Azure/azure-storage-blob-go/azblob"
-	"github.com/kozgot/go-log-processing/parser/internal/utils"
 )
 
 // AzureDownloader contains data needed to list or dowload blobs from azure.
@@ -19,16 +18,22 @@ type AzureDownloader struct {
 }
 
 // NewAzureDownloader creates and returns data a DownloaderData.
-func NewAzureDownloader(accountName string, accountKey string, containerName string) *AzureDownloader {
+// Returns an error if the credentials are invalid.
+func NewAzureDownloader(accountName string, accountKey string, containerName string) (*AzureDownloader, error) {
 	// Create a default request pipeline using your storage account name and account key.
 	credential, err := azblob.NewSharedKeyCredential(accountName, accountKey)
-	utils.FailOnError(err, "Invalid credentials for azure")
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials for azure: %w", err)
+	}
 
 	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
 
 	// From the Azure portal, get your storage account blob service storageAccountURL endpoint.
-	storageAccountURL, _ := url.Parse(
+	storageAccountURL, err := url.Parse(
 		fmt.Sprintf("https://%s.blob.core.windows.net/%s", accountName, containerName))
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure storage account or container: %w", err)
+	}
 
 	containerURL := azblob.NewContainerURL(*storageAccountURL, pipeline)
 
@@ -38,11 +43,11 @@ func NewAzureDownloader(accountName string, accountKey string, containerName str
 		StorageAccountURL: storageAccountURL,
 	}
 
-	return &downloader
+	return &downloader, nil
 }
 
 // ListFileNames lists the blobs in the azure container.
-func (downloader *AzureDownloader) ListFileNames() []string {
+func (downloader *AzureDownloader) ListFileNames() ([]string, error) {
 	fileNames := []string{}
 	ctx := context.Background()
 
@@ -50,7 +55,9 @@ func (downloader *AzureDownloader) ListFileNames() []string {
 	for marker := (azblob.Marker{}); marker.NotDone(); {
 		// Get a result segment starting with the blob indicated by the current Marker.
 		listBlob, err := downloader.ContainerURL.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{})
-		utils.FailOnError(err, "Could not list blobs")
+		if err != nil {
+			return nil, fmt.Errorf("could not list blobs: %w", err)
+		}
 
 		// ListBlobs returns the start of the next segment; we MUST use this to get
 		// the next segment (after processing the current result segment).
@@ -67,11 +74,11 @@ func (downloader *AzureDownloader) ListFileNames() []string {
 		log.Println("  [AZURE DOWNLOADER] No files found in Azure blob storage container.")
 	}
 
-	return fileNames
+	return fileNames, nil
 }
 
 // DownloadFile downloads the blob with the given name from azure.
-func (downloader *AzureDownloader) DownloadFile(fileName string) io.ReadCloser {
+func (downloader *AzureDownloader) DownloadFile(fileName string) (io.ReadCloser, error) {
 	blobURL := downloader.ContainerURL.NewBlockBlobURL(fileName)
 	ctx := context.Background()
 
@@ -84,11 +91,63 @@ func (downloader *AzureDownloader) DownloadFile(fileName string) io.ReadCloser {
 		false,
 		azblob.ClientProvidedKeyOptions{})
 
-	utils.FailOnError(err, "Could not download blob")
+	if err != nil {
+		return nil, fmt.Errorf("could not download blob %s: %w", fileName, err)
+	}
+
+	// Automatic retries are performed if the connection fails
+	maxRetries := 20
+	bodyStream := downloadResponse.Body(azblob.RetryReaderOptions{MaxRetryRequests: maxRetries})
+
+	return bodyStream, nil
+}
+
+// StatFile returns the current properties of the blob with the given name,
+// and false if the blob does not exist.
+func (downloader *AzureDownloader) StatFile(fileName string) (FileStat, bool, error) {
+	blobURL := downloader.ContainerURL.NewBlobURL(fileName)
+	ctx := context.Background()
+
+	properties, err := blobURL.GetProperties(ctx, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	if storageError, ok := err.(azblob.StorageError); ok && storageError.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+		return FileStat{}, false, nil
+	}
+
+	if err != nil {
+		return FileStat{}, false, fmt.Errorf("could not get the properties of blob %s: %w", fileName, err)
+	}
+
+	fileStat := FileStat{
+		Size:         properties.ContentLength(),
+		ETag:         string(properties.ETag()),
+		LastModified: properties.LastModified(),
+		CreationTime: properties.CreationTime(),
+	}
+
+	return fileStat, true, nil
+}
+
+// DownloadFileFrom downloads the blob with the given name from azure, starting at the given offset.
+// This also works for append blobs, which are used for continuously growing log files.
+func (downloader *AzureDownloader) DownloadFileFrom(fileName string, offset int64) (io.ReadCloser, error) {
+	blobURL := downloader.ContainerURL.NewBlobURL(fileName)
+	ctx := context.Background()
+
+	downloadResponse, err := blobURL.Download(
+		ctx,
+		offset,
+		azblob.CountToEnd,
+		azblob.BlobAccessConditions{},
+		false,
+		azblob.ClientProvidedKeyOptions{})
+
+	if err != nil {
+		return nil, fmt.Errorf("could not download blob %s: %w", fileName, err)
+	}
 
 	// Automatic retries are performed if the connection fails
 	maxRetries := 20
 	bodyStream := downloadResponse.Body(azblob.RetryReaderOptions{MaxRetryRequests: maxRetries})
 
-	return bodyStream
+	return bodyStream, nil
 }
@@ -2,10 +2,36 @@ package filedownloader
 
 import (
 	"io"
+	"time"
 )
 
 // FileDownloader interface describes the methods needed to list and download files to process.
+// The errors are returned to the caller, so a missing file or an unavailable storage only fails the current run.
 type FileDownloader interface {
-	ListFileNames() []string
-	DownloadFile(fileName string) io.ReadCloser
+	ListFileNames() ([]string, error)
+	DownloadFile(fileName string) (io.ReadCloser, error)
+}
+
+// RangeDownloader interface describes the methods needed to download files that are still growing,
+// one part at a time.
+type RangeDownloader interface {
+	FileDownloader
+
+	// StatFile returns the current properties of the file, and false if the file does not exist.
+	StatFile(fileName string) (FileStat, bool, error)
+
+	// DownloadFileFrom downloads the file starting at the given byte offset.
+	DownloadFileFrom(fileName string, offset int64) (io.ReadCloser, error)
+}
+
+// FileStat contains the properties of a file that are needed to follow its growth,
+// or to tell if it has changed since it was last parsed.
+type FileStat struct {
+	Size         int64
+	ETag         string
+	LastModified time.Time
+
+	// CreationTime changes when the file is replaced, eg.: by log rotation.
+	// It is the zero time if the file downloader can not tell when the file was created.
+	CreationTime time.Time
 }
@@ -0,0 +1,60 @@
+package filedownloader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// MaxHeadLength is the maximum number of bytes at the start of a file that are fingerprinted.
+const MaxHeadLength = 4096
+
+// FileHead identifies the contents at the start of a file. The parsed part of a file that is only appended to
+// does not change, so a different head means that the file was replaced by another file with the same name,
+// eg.: by log rotation. Not every file downloader can tell the creation time of the files, but they can all read them.
+type FileHead struct {
+	// Length is the number of fingerprinted bytes, it is 0 if the head has not been read.
+	Length int64
+
+	// Fingerprint is the hex encoded SHA-256 hash of the fingerprinted bytes.
+	Fingerprint string
+}
+
+// ReadFileHead fingerprints the first bytes of the file, at most length and MaxHeadLength bytes.
+// The length of the returned head is shorter if the file is shorter.
+func ReadFileHead(rangeDownloader RangeDownloader, fileName string, length int64) (FileHead, error) {
+	if length > MaxHeadLength {
+		length = MaxHeadLength
+	}
+
+	if length <= 0 {
+		return FileHead{}, nil
+	}
+
+	readCloser, err := rangeDownloader.DownloadFileFrom(fileName, 0)
+	if err != nil {
+		return FileHead{}, err
+	}
+	defer readCloser.Close()
+
+	head, err := ioutil.ReadAll(io.LimitReader(readCloser, length))
+	if err != nil {
+		return FileHead{}, fmt.Errorf("could not read the head of file %s: %w", fileName, err)
+	}
+
+	hash := sha256.Sum256(head)
+	return FileHead{Length: int64(len(head)), Fingerprint: hex.EncodeToString(hash[:])}, nil
+}
+
+// HasSameHead checks if the first bytes of the file are the same as when the head was read.
+// It is true if the head has not been read.
+func HasSameHead(rangeDownloader RangeDownloader, fileName string, head FileHead) (bool, error) {
+	if head.Length == 0 {
+		return true, nil
+	}
+
+	currentHead, err := ReadFileHead(rangeDownloader, fileName, head.Length)
+	return currentHead == head, err
+}
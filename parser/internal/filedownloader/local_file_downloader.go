@@ -0,0 +1,162 @@
+package filedownloader
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LocalDownloader contains data needed to list or open log files from a local directory.
+type LocalDownloader struct {
+	RootDirectory   string
+	Recursive       bool
+	IncludePatterns []string
+	ExcludePatterns []string
+}
+
+// NewLocalDownloader creates and returns a LocalDownloader.
+// The include and exclude patterns use the syntax of filepath.Match,
+// and are matched against both the path relative to the root directory and the base name of each file.
+// If no include patterns are given, every file is included. Returns an error if a pattern is invalid.
+func NewLocalDownloader(
+	rootDirectory string,
+	recursive bool,
+	includePatterns []string,
+	excludePatterns []string,
+) (*LocalDownloader, error) {
+	for _, pattern := range append(append([]string{}, includePatterns...), excludePatterns...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid file pattern %s: %w", pattern, err)
+		}
+	}
+
+	downloader := LocalDownloader{
+		RootDirectory:   rootDirectory,
+		Recursive:       recursive,
+		IncludePatterns: includePatterns,
+		ExcludePatterns: excludePatterns,
+	}
+
+	return &downloader, nil
+}
+
+// ListFileNames lists the files in the root directory,
+// the returned names are relative to the root directory and use forward slashes as separators.
+func (downloader *LocalDownloader) ListFileNames() ([]string, error) {
+	fileNames := []string{}
+
+	log.Println("  [LOCAL DOWNLOADER] Listing the files in the directory: " + downloader.RootDirectory)
+	err := filepath.Walk(downloader.RootDirectory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != downloader.RootDirectory && !downloader.Recursive {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(downloader.RootDirectory, path)
+		if err != nil {
+			return err
+		}
+
+		fileName := filepath.ToSlash(relativePath)
+		if !downloader.isIncluded(fileName) {
+			return nil
+		}
+
+		log.Println("  [LOCAL DOWNLOADER]  File name: " + fileName)
+		fileNames = append(fileNames, fileName)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list the files in %s: %w", downloader.RootDirectory, err)
+	}
+
+	if len(fileNames) == 0 {
+		log.Println("  [LOCAL DOWNLOADER] No files found in directory.")
+	}
+
+	sort.Strings(fileNames)
+	return fileNames, nil
+}
+
+// DownloadFile opens the file with the given name relative to the root directory.
+func (downloader *LocalDownloader) DownloadFile(fileName string) (io.ReadCloser, error) {
+	return downloader.open(fileName)
+}
+
+// StatFile returns the current size and modification time of the file
+// with the given name relative to the root directory, and false if the file does not exist.
+func (downloader *LocalDownloader) StatFile(fileName string) (FileStat, bool, error) {
+	info, err := os.Stat(filepath.Join(downloader.RootDirectory, filepath.FromSlash(fileName)))
+	if os.IsNotExist(err) {
+		return FileStat{}, false, nil
+	}
+
+	if err != nil {
+		return FileStat{}, false, fmt.Errorf("could not stat file %s: %w", fileName, err)
+	}
+
+	return FileStat{Size: info.Size(), LastModified: info.ModTime()}, true, nil
+}
+
+// DownloadFileFrom opens the file with the given name relative to the root directory,
+// and seeks to the given offset.
+func (downloader *LocalDownloader) DownloadFileFrom(fileName string, offset int64) (io.ReadCloser, error) {
+	file, err := downloader.open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("could not seek in file %s: %w", fileName, err)
+	}
+
+	return file, nil
+}
+
+func (downloader *LocalDownloader) open(fileName string) (*os.File, error) {
+	file, err := os.Open(filepath.Join(downloader.RootDirectory, filepath.FromSlash(fileName)))
+	if err != nil {
+		return nil, fmt.Errorf("could not open file %s: %w", fileName, err)
+	}
+
+	return file, nil
+}
+
+func (downloader *LocalDownloader) isIncluded(fileName string) bool {
+	if len(downloader.IncludePatterns) > 0 && !matchesAny(downloader.IncludePatterns, fileName) {
+		return false
+	}
+
+	return !matchesAny(downloader.ExcludePatterns, fileName)
+}
+
+func matchesAny(patterns []string, fileName string) bool {
+	baseName := filepath.Base(filepath.FromSlash(fileName))
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, fileName); matched {
+			return true
+		}
+
+		if matched, _ := filepath.Match(pattern, baseName); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,135 @@
+package filedownloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Downloader contains data needed to list or download objects from an S3-compatible object storage.
+type S3Downloader struct {
+	Client     *minio.Client
+	BucketName string
+	Prefix     string
+}
+
+// NewS3Downloader creates and returns an S3Downloader.
+// The endpoint is the host (and optionally the port) of the storage service without a scheme, eg.: minio:9000.
+// If usePathStyle is true, the bucket name is part of the request path instead of the host name,
+// this is needed for MinIO and most other S3-compatible services. Returns an error if the configuration is invalid.
+func NewS3Downloader(
+	endpoint string,
+	accessKeyID string,
+	secretAccessKey string,
+	bucketName string,
+	prefix string,
+	useSSL bool,
+	usePathStyle bool,
+) (*S3Downloader, error) {
+	bucketLookup := minio.BucketLookupAuto
+	if usePathStyle {
+		bucketLookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(accessKeyID, secretAccessKey, ""),
+		Secure:       useSSL,
+		BucketLookup: bucketLookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 configuration: %w", err)
+	}
+
+	downloader := S3Downloader{
+		Client:     client,
+		BucketName: bucketName,
+		Prefix:     prefix,
+	}
+
+	return &downloader, nil
+}
+
+// ListFileNames lists the objects in the bucket under the configured prefix.
+func (downloader *S3Downloader) ListFileNames() ([]string, error) {
+	fileNames := []string{}
+	ctx := context.Background()
+
+	log.Println("  [S3 DOWNLOADER] Listing the objects in the bucket:")
+	objects := downloader.Client.ListObjects(ctx, downloader.BucketName, minio.ListObjectsOptions{
+		Prefix:    downloader.Prefix,
+		Recursive: true,
+	})
+
+	for objectInfo := range objects {
+		if objectInfo.Err != nil {
+			return nil, fmt.Errorf("could not list the objects in bucket %s: %w", downloader.BucketName, objectInfo.Err)
+		}
+
+		log.Println("  [S3 DOWNLOADER]  Object name: " + objectInfo.Key)
+		fileNames = append(fileNames, objectInfo.Key)
+	}
+
+	if len(fileNames) == 0 {
+		log.Println("  [S3 DOWNLOADER] No files found in S3 bucket.")
+	}
+
+	return fileNames, nil
+}
+
+// DownloadFile downloads the object with the given name from the bucket.
+func (downloader *S3Downloader) DownloadFile(fileName string) (io.ReadCloser, error) {
+	ctx := context.Background()
+
+	object, err := downloader.Client.GetObject(ctx, downloader.BucketName, fileName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("could not download object %s: %w", fileName, err)
+	}
+
+	// GetObject does not send a request until the first read, check that the object exists.
+	if _, err = object.Stat(); err != nil {
+		object.Close()
+		return nil, fmt.Errorf("could not download object %s: %w", fileName, err)
+	}
+
+	return object, nil
+}
+
+// StatFile returns the current properties of the object with the given name,
+// and false if the object does not exist.
+func (downloader *S3Downloader) StatFile(fileName string) (FileStat, bool, error) {
+	ctx := context.Background()
+
+	objectInfo, err := downloader.Client.StatObject(ctx, downloader.BucketName, fileName, minio.StatObjectOptions{})
+	if err != nil && minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return FileStat{}, false, nil
+	}
+
+	if err != nil {
+		return FileStat{}, false, fmt.Errorf("could not get the properties of object %s: %w", fileName, err)
+	}
+
+	return FileStat{Size: objectInfo.Size, ETag: objectInfo.ETag, LastModified: objectInfo.LastModified}, true, nil
+}
+
+// DownloadFileFrom downloads the object with the given name from the bucket, starting at the given offset.
+func (downloader *S3Downloader) DownloadFileFrom(fileName string, offset int64) (io.ReadCloser, error) {
+	ctx := context.Background()
+
+	options := minio.GetObjectOptions{}
+	if offset > 0 {
+		if err := options.SetRange(offset, 0); err != nil {
+			return nil, fmt.Errorf("invalid range of object %s: %w", fileName, err)
+		}
+	}
+
+	object, err := downloader.Client.GetObject(ctx, downloader.BucketName, fileName, options)
+	if err != nil {
+		return nil, fmt.Errorf("could not download object %s: %w", fileName, err)
+	}
+
+	return object, nil
+}
@@ -0,0 +1,77 @@
+package fileparser
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/kozgot/go-log-processing/parser/internal/formatregistry"
+	"github.com/kozgot/go-log-processing/parser/internal/formats"
+)
+
+// ContinuationRule decides which lines of a log format continue the previous entry,
+// eg.: the lines of a settings dump or a stack trace, that have no log level or timestamp.
+type ContinuationRule struct {
+	// FileNamePattern selects the log files of the format, it is matched against the base name of the file.
+	FileNamePattern string
+
+	// EntryStart matches the first line of an entry, every other line continues the previous entry.
+	EntryStart *regexp.Regexp
+}
+
+// NewContinuationRule creates a continuation rule for the log files matching the file name pattern.
+// The pattern uses the syntax of filepath.Match.
+// Returns an error if the pattern or the entry start regex is invalid.
+func NewContinuationRule(fileNamePattern string, entryStartRegex string) (ContinuationRule, error) {
+	_, err := filepath.Match(fileNamePattern, "")
+	if err != nil {
+		return ContinuationRule{}, fmt.Errorf("invalid file name pattern %s: %w", fileNamePattern, err)
+	}
+
+	entryStart, err := regexp.Compile(entryStartRegex)
+	if err != nil {
+		return ContinuationRule{}, fmt.Errorf("invalid entry start regex %s: %w", entryStartRegex, err)
+	}
+
+	return ContinuationRule{FileNamePattern: fileNamePattern, EntryStart: entryStart}, nil
+}
+
+// DefaultContinuationRules returns the continuation rules of the dc_main.log and plc_manager.log files.
+func DefaultContinuationRules() []ContinuationRule {
+	return []ContinuationRule{
+		{FileNamePattern: "*dc_main*", EntryStart: regexp.MustCompile(formats.DcMainEntryStartRegex)},
+		{FileNamePattern: "*plc_manager*", EntryStart: regexp.MustCompile(formats.PlcManagerEntryStartRegex)},
+	}
+}
+
+// RegistryContinuationRules returns the continuation rules of the log formats of the registry,
+// or nil if the registry has no log formats. Returns an error if a log format is invalid,
+// the log formats of a registry loaded by formatregistry.LoadRegistry are already validated.
+func RegistryContinuationRules(registry *formatregistry.Registry) ([]ContinuationRule, error) {
+	if registry == nil || len(registry.LogFormats) == 0 {
+		return nil, nil
+	}
+
+	rules := []ContinuationRule{}
+	for _, logFormat := range registry.LogFormats {
+		rule, err := NewContinuationRule(logFormat.FileNamePattern, logFormat.EntryStart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log format %s: %w", logFormat.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// findContinuationRule returns the first rule matching the name of the log file, or nil if there is none.
+func findContinuationRule(rules []ContinuationRule, logFileName string) *ContinuationRule {
+	baseName := filepath.Base(filepath.FromSlash(logFileName))
+	for i := range rules {
+		if matched, _ := filepath.Match(rules[i].FileNamePattern, baseName); matched {
+			return &rules[i]
+		}
+	}
+
+	return nil
+}
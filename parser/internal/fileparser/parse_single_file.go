@@ -2,46 +2,552 @@ package fileparser
 
 import (
 	"bufio"
+	"context"
+	"fmt"
 	"io"
 	"log"
-	"sync"
+	"strings"
 
 	"github.com/kozgot/go-log-processing/parser/internal/contentparser"
+	"github.com/kozgot/go-log-processing/parser/internal/decompression"
+	"github.com/kozgot/go-log-processing/parser/internal/dedup"
+	"github.com/kozgot/go-log-processing/parser/internal/formatregistry"
 	"github.com/kozgot/go-log-processing/parser/internal/loglevelparser"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/quarantine"
 	"github.com/kozgot/go-log-processing/parser/internal/rabbitmq"
 	"github.com/kozgot/go-log-processing/parser/internal/timestampparser"
+	"github.com/kozgot/go-log-processing/parser/internal/timezone"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
-func ParseSingleFile(readCloser io.ReadCloser, logFileName string,
-	wg *sync.WaitGroup,
-	rabbitMQProducer rabbitmq.MessageProducer) {
-	defer wg.Done()
+// FileParser parses log files, and forwards the parsed entries to a rabbitMQ producer.
+type FileParser struct {
+	ctx               context.Context
+	rabbitMQProducer  rabbitmq.MessageProducer
+	progress          *progress.Progress
+	includeRawLines   bool
+	continuationRules []ContinuationRule
+	formatRegistry    *formatregistry.Registry
+	quarantineSink    quarantine.Sink
+	verboseEntries    VerboseEntries
+	timezones         *timezone.Resolver
+	deduplicator      *dedup.Deduplicator
+}
+
+// Position is the position of the start of a line in a log file.
+type Position struct {
+	// Offset is the number of bytes before the line.
+	Offset int64
+
+	// LineNumber is the number of lines before the line.
+	LineNumber int64
+}
+
+// NewFileParser creates a new FileParser.
+// Parsing stops at the next line when the context is cancelled, the progress of the run is recorded in progress.
+func NewFileParser(
+	ctx context.Context,
+	rabbitMQProducer rabbitmq.MessageProducer,
+	runProgress *progress.Progress,
+) *FileParser {
+	fileParser := FileParser{
+		ctx:               ctx,
+		rabbitMQProducer:  rabbitMQProducer,
+		progress:          runProgress,
+		continuationRules: DefaultContinuationRules(),
+		timezones:         timezone.NewResolver(nil, nil),
+	}
+
+	return &fileParser
+}
+
+// SetIncludeRawLines sets whether a copy of the original line is included in the source location of the entries.
+func (fileParser *FileParser) SetIncludeRawLines(includeRawLines bool) {
+	fileParser.includeRawLines = includeRawLines
+}
+
+// SetContinuationRules sets the rules used to join the continuation lines of multi-line entries
+// to the first line of the entry. Log files without a matching rule are parsed line by line.
+func (fileParser *FileParser) SetContinuationRules(rules []ContinuationRule) {
+	fileParser.continuationRules = rules
+}
+
+// SetFormatRegistry sets the declarative entry formats used to parse entries into a generic payload.
+func (fileParser *FileParser) SetFormatRegistry(registry *formatregistry.Registry) {
+	fileParser.formatRegistry = registry
+}
+
+// SetQuarantineSink sets the sink of the lines that could not be parsed, the rejected lines are dropped if it is nil.
+func (fileParser *FileParser) SetQuarantineSink(sink quarantine.Sink) {
+	fileParser.quarantineSink = sink
+}
+
+// SetTimezoneResolver sets the resolver of the timezones of the DCs writing the log files,
+// the timestamps of the entries are converted from the timezone of the DC to UTC.
+func (fileParser *FileParser) SetTimezoneResolver(resolver *timezone.Resolver) {
+	fileParser.timezones = resolver
+}
+
+// SetDeduplicator sets the deduplicator of the entries published from the overlapping log files of a DC,
+// every entry is published if it is nil.
+func (fileParser *FileParser) SetDeduplicator(deduplicator *dedup.Deduplicator) {
+	fileParser.deduplicator = deduplicator
+}
+
+// VerboseEntries selects the VERBOSE entries that are parsed, the other VERBOSE entries are filtered out.
+type VerboseEntries struct {
+	// StateChanges enables parsing the SMC[dc18-smc3] changing state, new state[3038] entries.
+	StateChanges bool
+
+	// TaskLaunches enables parsing the Launch Task name[...] type[...] entries of the task scheduler.
+	TaskLaunches bool
+}
+
+// SetVerboseEntries sets the VERBOSE entries that are parsed.
+func (fileParser *FileParser) SetVerboseEntries(verboseEntries VerboseEntries) {
+	fileParser.verboseEntries = verboseEntries
+}
+
+// ParseSingleFile parses a downloaded file, and forwards the parsed entries to the rabbitMQ producer.
+// Compressed files and archives are decompressed, each member of an archive is parsed as a separate log file.
+// A file that could not be decompressed is recorded as an error of the run, and the run continues.
+// Returns an error if an entry could not be published or a line could not be quarantined,
+// the parsing of the file stops at that entry.
+func (fileParser *FileParser) ParseSingleFile(readCloser io.ReadCloser, logFileName string) error {
+	defer readCloser.Close()
+
+	return fileParser.decompressAndParse(readCloser, logFileName)
+}
+
+// ParseFileFrom parses a file that has already been parsed up to the given position,
+// the reader must start at the offset of the position. Only complete lines are parsed,
+// an incomplete last line is left for the next time the file is parsed.
+// Returns the position of the end of the last parsed line.
+// If the file starts at offset 0 and it is compressed, the whole file is decompressed and parsed,
+// and compressed is true, in this case the returned position is not meaningful.
+// Returns an error like ParseCompleteLines.
+func (fileParser *FileParser) ParseFileFrom(
+	readCloser io.ReadCloser,
+	logFileName string,
+	start Position,
+) (end Position, compressed bool, err error) {
+	defer readCloser.Close()
+
+	reader := bufio.NewReader(readCloser)
+	if start.Offset == 0 && decompression.DetectFormat(reader, logFileName) != decompression.Plain {
+		return Position{}, true, fileParser.decompressAndParse(reader, logFileName)
+	}
+
+	log.Printf("  [PARSER] Parsing log file: %s from offset %d ...", logFileName, start.Offset)
+	end, err = fileParser.ParseCompleteLines(reader, logFileName, start)
+	if err != nil {
+		return end, false, err
+	}
+	log.Printf("  [PARSER] Done parsing log file: %s", logFileName)
+
+	return end, false, nil
+}
+
+// ParseCompleteLines parses the lines read from the reader until the end of the reader,
+// the reader must start at the given position of the file.
+// An incomplete last line, that has no line break at its end, is not parsed.
+// The last entry is parsed at the end of the reader, so continuation lines written later are not joined to it.
+// Returns the position of the end of the last parsed line. If the run is cancelled, the returned position is
+// the start of the first entry that was not parsed, so it can be parsed from there when the run is resumed.
+// Returns an error if an entry could not be published or a line could not be quarantined,
+// in this case the returned position is the start of that entry, so it can be parsed again.
+func (fileParser *FileParser) ParseCompleteLines(
+	reader io.Reader,
+	logFileName string,
+	start Position,
+) (Position, error) {
+	return fileParser.parseLines(reader, logFileName, start, false)
+}
+
+// Cancelled checks if the run has been cancelled.
+func (fileParser *FileParser) Cancelled() bool {
+	return fileParser.ctx.Err() != nil
+}
+
+// decompressAndParse parses the log files of a compressed file or archive.
+// Only the errors of publishing the entries are returned, the decompression errors are recorded in the progress.
+func (fileParser *FileParser) decompressAndParse(reader io.Reader, logFileName string) error {
+	var parseErr error
+	err := decompression.ForEachLogFile(reader, logFileName, func(decompressedName string, decompressed io.Reader) error {
+		parseErr = fileParser.parseLogFile(decompressedName, decompressed)
+		return parseErr
+	})
+
+	if parseErr != nil {
+		return parseErr
+	}
+
+	if err != nil {
+		fileParser.addError("Could not decompress log file " + logFileName + ": " + err.Error())
+	}
+
+	return nil
+}
+
+func (fileParser *FileParser) parseLogFile(logFileName string, reader io.Reader) error {
 	log.Printf("  [PARSER] Parsing log file: %s ...", logFileName)
-	scanner := bufio.NewScanner(readCloser)
-	for scanner.Scan() {
-		line := scanner.Text()
+	_, err := fileParser.parseLines(reader, logFileName, Position{}, true)
+	if err != nil {
+		return err
+	}
+	log.Printf("  [PARSER] Done parsing log file: %s", logFileName)
+
+	return nil
+}
+
+// pendingEntry contains the lines of a multi-line entry, that may be continued by the next line.
+type pendingEntry struct {
+	lines    []string
+	position Position
+}
 
-		// Parse the log level, and filter out irrelevant lines eg.: VERBOSE log level.
-		relevantLine := loglevelparser.ParseLogLevelAndFilter(line)
-		if relevantLine == nil {
-			continue
+// parseLines parses the lines read from the reader, and keeps track of their position in the file.
+// The continuation lines of multi-line entries are joined to the first line of the entry.
+// The last line is only parsed if it is complete or parseLastLine is true.
+// Parsing stops at the first entry that could not be published, the start of the entry is returned with the error.
+// If the run is cancelled while a multi-line entry is pending, the entry is not parsed, and its start is returned.
+func (fileParser *FileParser) parseLines(
+	reader io.Reader,
+	logFileName string,
+	start Position,
+	parseLastLine bool,
+) (Position, error) {
+	position := start
+	rule := findContinuationRule(fileParser.continuationRules, logFileName)
+	clock := timezone.NewClock(fileParser.timezones.Location(logFileName))
+	var pending *pendingEntry
+
+	bufferedReader := bufio.NewReader(reader)
+	for fileParser.ctx.Err() == nil {
+		line, err := bufferedReader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			fileParser.addError("Could not read log file " + logFileName + ": " + err.Error())
+			break
 		}
 
-		// Parse the timestamp of the log entry.
-		lineWithTimestamp := timestampparser.ParseTimestamp(*relevantLine)
-		if lineWithTimestamp == nil {
-			continue
+		if line == "" || (err == io.EOF && !parseLastLine) {
+			break
 		}
 
-		// Parse the remaining contents of the log entry depending on the log level.
-		finalParsedLine := contentparser.ParseEntryContents(*lineWithTimestamp)
-		if finalParsedLine == nil {
-			continue
+		fileParser.progress.LineRead(logFileName)
+		text := strings.TrimRight(line, "\r\n")
+
+		switch {
+		case rule == nil:
+			err := fileParser.parseAndPublish([]string{text}, logFileName, position, clock)
+			if err != nil {
+				return position, err
+			}
+
+		case pending != nil && !rule.EntryStart.MatchString(text):
+			pending.lines = append(pending.lines, text)
+
+		default:
+			err := fileParser.parsePendingEntry(pending, logFileName, clock)
+			if err != nil {
+				return pending.position, err
+			}
+			pending = &pendingEntry{lines: []string{text}, position: position}
 		}
 
-		rabbitMQProducer.PublishEntry(*finalParsedLine)
+		position.Offset += int64(len(line))
+		position.LineNumber++
+
+		if err == io.EOF {
+			break
+		}
 	}
 
-	readCloser.Close()
-	log.Printf("  [PARSER] Done parsing log file: %s", logFileName)
+	// The pending entry may be continued by the lines that were not read, so it is parsed again from its start.
+	if fileParser.ctx.Err() != nil && pending != nil {
+		return pending.position, nil
+	}
+
+	err := fileParser.parsePendingEntry(pending, logFileName, clock)
+	if err != nil {
+		return pending.position, err
+	}
+
+	return position, nil
+}
+
+func (fileParser *FileParser) parsePendingEntry(
+	pending *pendingEntry,
+	logFileName string,
+	clock *timezone.Clock,
+) error {
+	if pending == nil {
+		return nil
+	}
+
+	return fileParser.parseAndPublish(pending.lines, logFileName, pending.position, clock)
+}
+
+// parseAndPublish parses an entry consisting of one or more lines, and publishes it.
+// The continuation lines are joined to the first line with a single space before parsing.
+// The local timestamps of the entry are converted to UTC by the clock of the file.
+// Returns an error if the entry could not be published, or the rejected line could not be quarantined.
+func (fileParser *FileParser) parseAndPublish(
+	lines []string,
+	logFileName string,
+	position Position,
+	clock *timezone.Clock,
+) error {
+	parts := []string{lines[0]}
+	for _, continuation := range lines[1:] {
+		continuation = strings.TrimSpace(continuation)
+		if continuation != "" {
+			parts = append(parts, continuation)
+		}
+	}
+
+	source := models.SourceLocation{
+		FileName:   logFileName,
+		LineNumber: position.LineNumber + 1,
+		ByteOffset: position.Offset,
+		RawLine:    strings.Join(lines, "\n"),
+	}
+
+	line := strings.Join(parts, " ")
+	parsedEntry, reason, err := ParseLineInTimezone(line, fileParser.formatRegistry, clock)
+	if parsedEntry == nil && reason == models.FilteredLogLevel {
+		parsedEntry, err = parseVerboseLine(line, fileParser.verboseEntries, clock)
+		if err != nil {
+			reason = models.MalformedEntry
+		}
+	}
+
+	if parsedEntry == nil {
+		// A bad line does not stop the parsing of the file, it is counted and quarantined.
+		if err != nil {
+			log.Printf("  [PARSER] Could not parse line %d of %s: %s", source.LineNumber, logFileName, err)
+		}
+
+		fileParser.progress.LineRejected(logFileName, reason)
+		return fileParser.quarantineLine(reason, source, err)
+	}
+
+	parsedEntry.Source = source
+	if !fileParser.includeRawLines {
+		parsedEntry.Source.RawLine = ""
+	}
+
+	parsedEntry.RunID = fileParser.progress.RunID()
+
+	fileParser.recordSettingsTimezone(*parsedEntry, logFileName, clock)
+
+	if fileParser.deduplicator != nil && fileParser.deduplicator.IsDuplicate(logFileName, *parsedEntry) {
+		fileParser.progress.DuplicateSuppressed(logFileName)
+		return nil
+	}
+
+	err = fileParser.rabbitMQProducer.PublishEntry(*parsedEntry)
+	if err != nil {
+		return fmt.Errorf("could not publish the entry of line %d of %s: %w", source.LineNumber, logFileName, err)
+	}
+
+	fileParser.progress.EntryPublished(*parsedEntry)
+	return nil
+}
+
+// recordSettingsTimezone switches the clock of the file to the timezone of a settings entry,
+// unless the timezone of the file is configured.
+func (fileParser *FileParser) recordSettingsTimezone(
+	parsedEntry models.ParsedLogEntry,
+	logFileName string,
+	clock *timezone.Clock,
+) {
+	if parsedEntry.InfoParams == nil || parsedEntry.InfoParams.DCMessage == nil ||
+		parsedEntry.InfoParams.DCMessage.MessageType != models.Settings ||
+		parsedEntry.InfoParams.DCMessage.Payload == nil ||
+		parsedEntry.InfoParams.DCMessage.Payload.SettingsPayload == nil {
+		return
+	}
+
+	settingsTimezone := parsedEntry.InfoParams.DCMessage.Payload.SettingsPayload.Timezone
+	if settingsTimezone == "" || !fileParser.timezones.SettingsTimezoneSeen(logFileName, settingsTimezone) {
+		return
+	}
+
+	clock.SetLocation(fileParser.timezones.Location(logFileName))
+}
+
+// quarantineLine sends a rejected line to the quarantine sink, if the reason of the rejection is relevant.
+// The error of parsing the line is recorded with the line, it may be nil.
+// Returns an error if the line could not be sent to the sink.
+func (fileParser *FileParser) quarantineLine(
+	reason models.RejectReason,
+	source models.SourceLocation,
+	err error,
+) error {
+	if !models.IsQuarantined(reason) || fileParser.quarantineSink == nil {
+		return nil
+	}
+
+	rejectedLine := models.RejectedLine{
+		Reason:       reason,
+		ReasonString: models.RejectReasonToString(reason),
+		Source:       source,
+	}
+	if err != nil {
+		rejectedLine.Error = err.Error()
+	}
+
+	return fileParser.quarantineSink.Quarantine(rejectedLine)
+}
+
+func (fileParser *FileParser) addError(message string) {
+	log.Printf("  [PARSER] %s", message)
+	fileParser.progress.AddError(message)
+}
+
+// ParseLine parses a single line of a log file, returns nil if the line is irrelevant or could not be parsed.
+func ParseLine(line string) *models.ParsedLogEntry {
+	parsedEntry, _, _ := ParseLineWithRegistry(line, nil)
+	return parsedEntry
+}
+
+// ParseLineWithRegistry parses a single line of a log file using the built-in parsers
+// and the entry formats of the registry, the registry may be nil.
+// Returns nil and the reason of the rejection if the line is irrelevant or could not be parsed,
+// and the error of parsing the line if its timestamp or a field of the entry is malformed.
+func ParseLineWithRegistry(
+	line string,
+	registry *formatregistry.Registry,
+) (*models.ParsedLogEntry, models.RejectReason, error) {
+	return ParseLineInTimezone(line, registry, nil)
+}
+
+// ParseLineInTimezone parses a single line of a log file like ParseLineWithRegistry,
+// the local timestamps of the line are converted to UTC by the clock. If the clock is nil, the timestamps are in UTC.
+func ParseLineInTimezone(
+	line string,
+	registry *formatregistry.Registry,
+	clock *timezone.Clock,
+) (*models.ParsedLogEntry, models.RejectReason, error) {
+	if strings.TrimSpace(line) == "" {
+		return nil, models.EmptyLine, nil
+	}
+
+	// Parse the log level, and filter out irrelevant lines eg.: VERBOSE log level.
+	relevantLine := loglevelparser.ParseLogLevelAndFilter(line)
+	if relevantLine == nil {
+		if loglevelparser.HasIgnoredLogLevel(line) {
+			return nil, models.FilteredLogLevel, nil
+		}
+
+		return nil, models.MissingLogLevel, nil
+	}
+
+	// Parse the timestamp of the log entry.
+	lineWithTimestamp, err := timestampparser.ParseTimestamp(*relevantLine)
+	if err != nil {
+		return nil, models.MalformedTimestamp, err
+	}
+
+	if lineWithTimestamp == nil {
+		return nil, models.MissingTimestamp, nil
+	}
+	toUTC(lineWithTimestamp, clock)
+
+	// The entry formats of the registry take precedence over the built-in parsers.
+	if parsedEntry := registry.Parse(*lineWithTimestamp, false); parsedEntry != nil {
+		return parsedEntry, models.NotRejected, nil
+	}
+
+	// Parse the remaining contents of the log entry depending on the log level.
+	parsedEntry, err := contentparser.ParseEntryContents(*lineWithTimestamp)
+	if err != nil {
+		return nil, models.MalformedEntry, err
+	}
+
+	if isRecognised(parsedEntry) {
+		return parsedEntry, models.NotRejected, nil
+	}
+
+	// Entries that are not recognised by the built-in parsers may be parsed by a fallback entry format.
+	if fallbackEntry := registry.Parse(*lineWithTimestamp, true); fallbackEntry != nil {
+		return fallbackEntry, models.NotRejected, nil
+	}
+
+	if parsedEntry == nil {
+		// Only WARN level entries are rejected by the content parsers.
+		return nil, models.UnrecognisedWarn, nil
+	}
+
+	return parsedEntry, models.NotRejected, nil
+}
+
+// ParseVerboseLine parses a VERBOSE entry if it is selected by verboseEntries,
+// returns nil if the line is not a selected VERBOSE entry, and an error if the selected entry is malformed.
+func ParseVerboseLine(line string, verboseEntries VerboseEntries) (*models.ParsedLogEntry, error) {
+	return parseVerboseLine(line, verboseEntries, nil)
+}
+
+func parseVerboseLine(
+	line string,
+	verboseEntries VerboseEntries,
+	clock *timezone.Clock,
+) (*models.ParsedLogEntry, error) {
+	if !verboseEntries.StateChanges && !verboseEntries.TaskLaunches {
+		return nil, nil
+	}
+
+	verboseLine := loglevelparser.ParseVerboseLogLevel(line)
+	if verboseLine == nil {
+		return nil, nil
+	}
+
+	lineWithTimestamp, err := timestampparser.ParseTimestamp(*verboseLine)
+	if err != nil || lineWithTimestamp == nil {
+		return nil, err
+	}
+	toUTC(lineWithTimestamp, clock)
+
+	parsedEntry, err := contentparser.ParseVerboseEntryContents(*lineWithTimestamp)
+	switch {
+	case err != nil || parsedEntry == nil:
+		return nil, err
+	case parsedEntry.InfoParams.EntryType == models.SmcStateChange && !verboseEntries.StateChanges:
+		return nil, nil
+	case parsedEntry.InfoParams.EntryType == models.TaskLaunch && !verboseEntries.TaskLaunches:
+		return nil, nil
+	default:
+		return parsedEntry, nil
+	}
+}
+
+// toUTC converts the local timestamp of the line to UTC, and sets the timezone of the dates in the rest of the line.
+func toUTC(lineWithTimestamp *models.EntryWithLevelAndTimestamp, clock *timezone.Clock) {
+	if clock == nil {
+		return
+	}
+
+	lineWithTimestamp.Timestamp = clock.ToUTC(lineWithTimestamp.Timestamp)
+	lineWithTimestamp.Location = clock.Location()
+}
+
+// isRecognised checks if the built-in parsers have recognised the type of the entry,
+// the dc messages of an unknown type are not recognised either.
+func isRecognised(parsedEntry *models.ParsedLogEntry) bool {
+	if parsedEntry == nil {
+		return false
+	}
+
+	infoParams := parsedEntry.InfoParams
+	if infoParams != nil && infoParams.EntryType == models.UnknownInfoType {
+		return false
+	}
+
+	if infoParams != nil && infoParams.DCMessage != nil && infoParams.DCMessage.MessageType == models.UnknownDCMessage {
+		return false
+	}
+
+	return true
 }
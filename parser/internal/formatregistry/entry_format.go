@@ -0,0 +1,134 @@
+package formatregistry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/common"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+// Field extractors.
+const (
+	// Bracketed extracts the value of a key[value] field as a string.
+	Bracketed = "bracketed"
+	// Parenthesised extracts the value of a key(value) field as a string.
+	Parenthesised = "parenthesised"
+	// Date extracts the value of a key[Wed Jun 10 09:18:39 2020] field as a timestamp.
+	Date = "date"
+	// Int extracts the value of a key[123] field as an integer.
+	Int = "int"
+)
+
+var errInvalidFormat = errors.New("invalid entry format")
+
+// EntryFormat describes the entries parsed into a generic payload.
+type EntryFormat struct {
+	Name string `yaml:"name"`
+
+	// Level is the log level of the entries, entries of every level match if it is empty.
+	Level string `yaml:"level"`
+
+	// Match is a regular expression matched against the entry after its log level and timestamp,
+	// every entry of the level matches if it is empty.
+	Match string `yaml:"match"`
+
+	// EntryType is the target entry type of the matching entries.
+	EntryType string `yaml:"entryType"`
+
+	// Fallback formats are only used for entries that the built-in parsers do not recognise,
+	// other formats take precedence over the built-in parsers.
+	Fallback bool `yaml:"fallback"`
+
+	Fields []FieldFormat `yaml:"fields"`
+
+	matcher *regexp.Regexp
+}
+
+// FieldFormat describes a field extracted from the matching entries.
+type FieldFormat struct {
+	Name string `yaml:"name"`
+
+	// Key is the name of the field in the log entry, eg.: smc_uid in smc_uid[dc18-smc3].
+	// If it is empty, the first value surrounded by brackets or parentheses is extracted.
+	Key string `yaml:"key"`
+
+	// Extractor is one of bracketed, parenthesised, date or int.
+	Extractor string `yaml:"extractor"`
+
+	regex *regexp.Regexp
+}
+
+func (entryFormat *EntryFormat) compile() error {
+	if entryFormat.Name == "" || entryFormat.EntryType == "" {
+		return fmt.Errorf("%w: entry formats must have a name and an entry type", errInvalidFormat)
+	}
+
+	var err error
+	entryFormat.matcher, err = regexp.Compile(entryFormat.Match)
+	if err != nil {
+		return fmt.Errorf("%w: invalid matcher in entry format %s: %s", errInvalidFormat, entryFormat.Name, err)
+	}
+
+	for i := range entryFormat.Fields {
+		field := &entryFormat.Fields[i]
+		// The key must not be preceded by other characters of a name, eg.: uid must not match smc_uid.
+		key := ""
+		if field.Key != "" {
+			key = `(?:^|[^\w])` + regexp.QuoteMeta(field.Key)
+		}
+
+		switch field.Extractor {
+		case Bracketed, Date, Int:
+			field.regex = regexp.MustCompile(key + `\[([^\]]*)\]`)
+		case Parenthesised:
+			field.regex = regexp.MustCompile(key + `\(([^\)]*)\)`)
+		default:
+			return fmt.Errorf("%w: unknown extractor in entry format %s: %s",
+				errInvalidFormat, entryFormat.Name, field.Extractor)
+		}
+	}
+
+	return nil
+}
+
+func (entryFormat *EntryFormat) matches(line models.EntryWithLevelAndTimestamp) bool {
+	if entryFormat.Level != "" && entryFormat.Level != line.Level {
+		return false
+	}
+
+	return entryFormat.matcher.MatchString(line.Rest)
+}
+
+// extractFields extracts the fields of the entry format,
+// fields that are not present in the entry or could not be parsed are left out.
+// The dates are local times of the location.
+func (entryFormat *EntryFormat) extractFields(rest string, location *time.Location) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for _, field := range entryFormat.Fields {
+		match := field.regex.FindStringSubmatch(rest)
+		if match == nil {
+			continue
+		}
+
+		value := strings.TrimSpace(match[1])
+		switch field.Extractor {
+		case Date:
+			if date, err := common.ParseDateTime(value, location); err == nil && common.IsValidDate(date) {
+				fields[field.Name] = date
+			}
+		case Int:
+			if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+				fields[field.Name] = number
+			}
+		default:
+			fields[field.Name] = value
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,111 @@
+package formatregistry
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"regexp"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"gopkg.in/yaml.v2"
+)
+
+// Registry contains the declarative log and entry formats loaded from a format file.
+type Registry struct {
+	// LogFormats describe how the entries of a log file are split into lines.
+	LogFormats []LogFormat `yaml:"logFormats"`
+
+	// EntryFormats describe how the contents of the entries are parsed.
+	EntryFormats []*EntryFormat `yaml:"entryFormats"`
+}
+
+// LogFormat describes the lines of the log files matching a file name pattern.
+type LogFormat struct {
+	Name string `yaml:"name"`
+
+	// FileNamePattern selects the log files of the format, it is matched against the base name of the file.
+	FileNamePattern string `yaml:"fileNamePattern"`
+
+	// EntryStart matches the first line of an entry, every other line continues the previous entry.
+	EntryStart string `yaml:"entryStart"`
+}
+
+// validate checks the file name pattern and the entry start regex of the log format.
+func (logFormat LogFormat) validate() error {
+	if _, err := filepath.Match(logFormat.FileNamePattern, ""); err != nil || logFormat.FileNamePattern == "" {
+		return fmt.Errorf("%w: invalid file name pattern in log format %s: %q",
+			errInvalidFormat, logFormat.Name, logFormat.FileNamePattern)
+	}
+
+	if _, err := regexp.Compile(logFormat.EntryStart); err != nil {
+		return fmt.Errorf("%w: invalid entry start in log format %s: %s", errInvalidFormat, logFormat.Name, err)
+	}
+
+	return nil
+}
+
+// LoadRegistry loads the format registry from a YAML or JSON file.
+func LoadRegistry(path string) (*Registry, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read format registry file %s: %w", path, err)
+	}
+
+	return ParseRegistry(bytes)
+}
+
+// ParseRegistry parses the format registry from YAML or JSON, validates the log formats and compiles the entry formats.
+// Returns an error if the registry or one of its log or entry formats is invalid.
+func ParseRegistry(bytes []byte) (*Registry, error) {
+	registry := Registry{}
+	err := yaml.UnmarshalStrict(bytes, &registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format registry: %w", err)
+	}
+
+	for _, logFormat := range registry.LogFormats {
+		if err := logFormat.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, entryFormat := range registry.EntryFormats {
+		if err := entryFormat.compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("  [PARSER] Loaded %d log formats and %d entry formats",
+		len(registry.LogFormats),
+		len(registry.EntryFormats))
+
+	return &registry, nil
+}
+
+// Parse parses the entry with the first matching entry format.
+// If fallback is false, only the formats that take precedence over the built-in parsers are used,
+// otherwise only the fallback formats are used. Returns nil if no entry format matches the entry.
+func (registry *Registry) Parse(line models.EntryWithLevelAndTimestamp, fallback bool) *models.ParsedLogEntry {
+	if registry == nil {
+		return nil
+	}
+
+	for _, entryFormat := range registry.EntryFormats {
+		if entryFormat.Fallback != fallback || !entryFormat.matches(line) {
+			continue
+		}
+
+		return &models.ParsedLogEntry{
+			Timestamp: line.Timestamp,
+			Level:     line.Level,
+			GenericParams: &models.GenericParams{
+				FormatName: entryFormat.Name,
+				EntryType:  entryFormat.EntryType,
+				Fields:     entryFormat.extractFields(line.Rest, line.Location),
+			},
+		}
+	}
+
+	return nil
+}
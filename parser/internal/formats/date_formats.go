@@ -13,3 +13,11 @@ const DateFormatRegexShort = "([12]\\d{3}-(0[1-9]|1[0-2])-(0[1-9]|[12]\\d|3[01])
 // and colon that might sourround the timestamp in a line of a log file.
 // This is used to trim off the already parsed parts from the line.
 const DateSurroundingRegex = "\\[*( )*\\]*(:)*"
+
+// DcMainEntryStartRegex matches the start of the first line of an entry in the dc_main.log file,
+// eg.: 'Wed Jun 10 14:56:19 2020 INFO'.
+const DcMainEntryStartRegex = DateFormatRegex
+
+// PlcManagerEntryStartRegex matches the start of the first line of an entry in the plc_manager.log file,
+// eg.: '[ 2020-06-10-09:18:38 ]INFO'.
+const PlcManagerEntryStartRegex = "^\\[ *" + DateFormatRegexShort
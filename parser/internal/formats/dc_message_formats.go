@@ -4,7 +4,7 @@ package formats
 // eg.: <--[last index]--(DB) pod_uid[1658] time[1591776000] value[9130] (smart_meter_cabinet_facade.cc::315)
 
 // IncomingMessageTypeRegex is used to identify the type of the incoming dc message, which is between the [].
-//  eg.: <--[last index]--(DB)
+// eg.: <--[last index]--(DB)
 const IncomingMessageTypeRegex = InComingArrow + AnythingBetweenBracketsRegex
 
 // OutGoingMessageTypeRegex is used to identify the type of the outgoing dc message, which is between the [].
@@ -19,6 +19,13 @@ const IncomingMessageSourceRegex = "--" + AnythingBetweenParenthesesRegex
 // eg.: --[settings]-->(DB).
 const OutGoingMessageDestRegex = OutGoingArrow + AnythingBetweenParenthesesRegex
 
+// IncomingMessageMarker and OutGoingMessageMarker are contained by the incoming and outgoing dc messages,
+// they are used to select the parser of the entry.
+const (
+	IncomingMessageMarker = "--("
+	OutGoingMessageMarker = OutGoingArrow + "("
+)
+
 // PodUIDRegex matches the pod id in a dc message.
 const PodUIDRegex = "pod_uid" + LongNumberBetweenBracketsRegex
 
@@ -2,3 +2,6 @@ package formats
 
 // LogLevelsRegex represents the regular expression that matches the log level in a line of the dc_main.log file.
 const LogLevelsRegex = "(ERROR|WARNING|WARN|INFO)"
+
+// IgnoredLogLevelsRegex matches the log levels of the lines that are intentionally filtered out.
+const IgnoredLogLevelsRegex = "(VERBOSE|DEBUG)"
@@ -3,6 +3,9 @@ package formats
 // RoutingTableRegex matches a log line that contains data regarding the Rounting Table.
 const RoutingTableRegex = "(Routing Table: )"
 
+// RoutingTableMarker is contained by every routing table entry, it is used to select the parser of the entry.
+const RoutingTableMarker = "Routing Table: "
+
 // RoutingAddressRegex matches the Addr[...] field.
 const RoutingAddressRegex = "Addr" + AnyLettersBetweenBrackets
 
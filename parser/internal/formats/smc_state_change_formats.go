@@ -0,0 +1,17 @@
+package formats
+
+// This file contains regular expressions needed to
+// parse VERBOSE log entries that look like this (after the timestamp and log level):
+// SMC[dc18-smc3] changing state, new state[3038] ((null)::-1225668530)
+
+// VerboseLogLevel is the log level of the SMC state change entries.
+const VerboseLogLevel = "VERBOSE"
+
+// SmcStateChangeText is the text of the SMC state change entries between the SMC UID and the new state.
+const SmcStateChangeText = " changing state, new state"
+
+// SmcStateChangeSmcUIDRegex matches the SMC[dc18-smc3] field of an SMC state change entry.
+const SmcStateChangeSmcUIDRegex = "SMC" + AnyLettersBetweenBrackets
+
+// SmcStateChangeNewStateRegex matches the new state[3038] field of an SMC state change entry.
+const SmcStateChangeNewStateRegex = "new state" + LongNumberBetweenBracketsRegex
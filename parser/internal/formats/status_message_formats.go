@@ -9,3 +9,6 @@ const StatusMessageRegex = AnyStringUntilFirstSpaceRegex
 
 // StatusByteRegex  represents the regular expression that matches the status byte field of a log entry.
 const StatusByteRegex = "status_byte" + AnyLettersBetweenBrackets
+
+// StatusByteMarker is contained by every status message entry, it is used to select the parser of the entry.
+const StatusByteMarker = "status_byte["
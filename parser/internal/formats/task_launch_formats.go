@@ -0,0 +1,16 @@
+package formats
+
+// This file contains regular expressions needed to
+// parse VERBOSE log entries that start like this (after the timestamp and log level):
+// Launch Task name[update_connection_task] type[connect] name[update_connection_task] smc_uid[dc18-smc3] uid[67]
+// priority[4294967293] retry[1] creation_time[Wed Jun 10 09:18:38 2020] on thread 2968499248 (...)
+// The name, smc_uid, uid, priority, retry and creation_time fields are the same as in the task failed warnings.
+
+// TaskLaunchPrefix is the prefix of task launch entries.
+const TaskLaunchPrefix = "Launch Task "
+
+// TaskTypeRegex matches the type[connect] field of a task launch entry.
+const TaskTypeRegex = "type" + AnyLettersBetweenBrackets
+
+// TaskThreadRegex matches the on thread 2968499248 part of a task launch entry.
+const TaskThreadRegex = "on thread [0-9]+"
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/report"
+)
+
+// Status represents the state of a parser job.
+type Status string
+
+const (
+	// Queued jobs are waiting for the running job to finish.
+	Queued Status = "queued"
+	// Running jobs are parsing log files.
+	Running Status = "running"
+	// Succeeded jobs have parsed every selected file.
+	Succeeded Status = "succeeded"
+	// Failed jobs have stopped because of an unexpected error.
+	Failed Status = "failed"
+	// Cancelled jobs have been stopped by a cancel request.
+	Cancelled Status = "cancelled"
+)
+
+// Request contains the parameters of a parser job.
+type Request struct {
+	// Files contains the names of the files to parse, every file is parsed if it is empty.
+	Files []string `json:"Files"`
+
+	// Reprocess makes the parser ignore the stored checkpoints, and parse every file from the beginning.
+	Reprocess bool `json:"Reprocess"`
+}
+
+// Job contains the state of a parser job, it is returned by the job API in JSON format.
+// The start and finish times are left out until the job starts and finishes.
+type Job struct {
+	ID         string            `json:"ID"`
+	Status     Status            `json:"Status"`
+	Request    Request           `json:"Request"`
+	CreatedAt  time.Time         `json:"CreatedAt"`
+	StartedAt  *time.Time        `json:"StartedAt,omitempty"`
+	FinishedAt *time.Time        `json:"FinishedAt,omitempty"`
+	Progress   progress.Snapshot `json:"Progress"`
+
+	// Report contains the statistics of the parsed files, it is complete when the job has finished.
+	Report report.Report `json:"Report"`
+}
@@ -0,0 +1,245 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+)
+
+// ConcurrencyPolicy decides what happens to a job that is submitted while another job is running.
+type ConcurrencyPolicy string
+
+const (
+	// RejectConcurrentJobs rejects new jobs while a job is running.
+	RejectConcurrentJobs ConcurrencyPolicy = "reject"
+	// QueueConcurrentJobs queues new jobs, and runs them one by one.
+	QueueConcurrentJobs ConcurrencyPolicy = "queue"
+)
+
+// DefaultMaxFinishedJobs is the number of finished jobs kept by default, the oldest ones are evicted.
+const DefaultMaxFinishedJobs = 100
+
+// ErrJobRunning is returned when a job is submitted while another job is running,
+// and the concurrency policy rejects concurrent jobs.
+var ErrJobRunning = errors.New("another job is already running")
+
+// RunFunc runs a parser job, it should return early when the context is cancelled.
+// The job fails if it returns an error, eg.: if the log files could not be downloaded.
+type RunFunc func(ctx context.Context, request Request, runProgress *progress.Progress) error
+
+// managedJob contains a job and the data needed to run and cancel it.
+type managedJob struct {
+	job      Job
+	progress *progress.Progress
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// Manager runs parser jobs in the background, one at a time.
+// Only the last maxFinishedJobs finished jobs are kept, the older ones can not be queried anymore.
+type Manager struct {
+	mutex           sync.Mutex
+	jobs            map[string]*managedJob
+	queue           []*managedJob
+	running         *managedJob
+	finished        []*managedJob
+	maxFinishedJobs int
+	policy          ConcurrencyPolicy
+	run             RunFunc
+}
+
+// NewManager creates a new job manager that runs the jobs using the given function.
+func NewManager(policy ConcurrencyPolicy, run RunFunc) *Manager {
+	manager := Manager{
+		jobs:            make(map[string]*managedJob),
+		queue:           []*managedJob{},
+		maxFinishedJobs: DefaultMaxFinishedJobs,
+		policy:          policy,
+		run:             run,
+	}
+
+	return &manager
+}
+
+// SetMaxFinishedJobs sets the number of finished jobs kept, the oldest ones are evicted when a job finishes.
+func (manager *Manager) SetMaxFinishedJobs(maxFinishedJobs int) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.maxFinishedJobs = maxFinishedJobs
+}
+
+// ParseConcurrencyPolicy parses a concurrency policy from its string representation.
+func ParseConcurrencyPolicy(policyString string) (ConcurrencyPolicy, error) {
+	switch ConcurrencyPolicy(policyString) {
+	case RejectConcurrentJobs:
+		return RejectConcurrentJobs, nil
+	case QueueConcurrentJobs:
+		return QueueConcurrentJobs, nil
+	default:
+		return "", fmt.Errorf("unknown concurrency policy: %s", policyString)
+	}
+}
+
+// Submit creates a new job, and starts it or queues it according to the concurrency policy.
+func (manager *Manager) Submit(request Request) (Job, error) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if manager.running != nil && manager.policy == RejectConcurrentJobs {
+		return Job{}, ErrJobRunning
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	newJob := managedJob{
+		job: Job{
+			ID:        uuid.New().String(),
+			Status:    Queued,
+			Request:   request,
+			CreatedAt: time.Now().UTC(),
+		},
+		progress: progress.NewProgress(),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	// The ID of the job identifies the data of the run downstream, eg.: in the ES documents.
+	newJob.progress.SetRunID(newJob.job.ID)
+
+	manager.jobs[newJob.job.ID] = &newJob
+	manager.queue = append(manager.queue, &newJob)
+	log.Printf("  [JOBS] Submitted job %s", newJob.job.ID)
+
+	manager.startNextJob()
+
+	return newJob.snapshot(), nil
+}
+
+// Get returns the current state of the job with the given ID, and false if there is no such job.
+func (manager *Manager) Get(id string) (Job, bool) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	existingJob, ok := manager.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+
+	return existingJob.snapshot(), true
+}
+
+// Cancel cancels the job with the given ID, and returns false if there is no such job.
+// A queued job is removed from the queue, a running job stops at the next line it parses.
+func (manager *Manager) Cancel(id string) (Job, bool) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	existingJob, ok := manager.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+
+	switch existingJob.job.Status {
+	case Queued:
+		manager.removeFromQueue(existingJob)
+		existingJob.cancel()
+		manager.finishJob(existingJob, Cancelled)
+
+	case Running:
+		// The status is updated when the job returns.
+		existingJob.cancel()
+	}
+
+	log.Printf("  [JOBS] Cancelled job %s", id)
+	return existingJob.snapshot(), true
+}
+
+// startNextJob starts the next queued job if no job is running, the mutex must be held by the caller.
+func (manager *Manager) startNextJob() {
+	if manager.running != nil || len(manager.queue) == 0 {
+		return
+	}
+
+	nextJob := manager.queue[0]
+	manager.queue = manager.queue[1:]
+	manager.running = nextJob
+
+	startedAt := time.Now().UTC()
+	nextJob.job.StartedAt = &startedAt
+	nextJob.job.Status = Running
+
+	go manager.runJob(nextJob)
+}
+
+func (manager *Manager) runJob(runningJob *managedJob) {
+	log.Printf("  [JOBS] Started job %s", runningJob.job.ID)
+	status := Succeeded
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			runningJob.progress.AddError(fmt.Sprint(recovered))
+			status = Failed
+		}
+
+		manager.mutex.Lock()
+		defer manager.mutex.Unlock()
+
+		if runningJob.ctx.Err() != nil && status == Succeeded {
+			status = Cancelled
+		}
+
+		runningJob.cancel()
+		manager.finishJob(runningJob, status)
+		log.Printf("  [JOBS] Job %s finished with status: %s", runningJob.job.ID, status)
+
+		manager.running = nil
+		manager.startNextJob()
+	}()
+
+	err := manager.run(runningJob.ctx, runningJob.job.Request, runningJob.progress)
+	if err != nil {
+		log.Printf("  [JOBS] Job %s failed: %s", runningJob.job.ID, err)
+		runningJob.progress.AddError(err.Error())
+		status = Failed
+	}
+}
+
+func (manager *Manager) removeFromQueue(queuedJob *managedJob) {
+	for i, job := range manager.queue {
+		if job == queuedJob {
+			manager.queue = append(manager.queue[:i], manager.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// finishJob records the final status of a job, and evicts the oldest finished jobs if there are too many of them.
+// The mutex must be held by the caller.
+func (manager *Manager) finishJob(finishedJob *managedJob, status Status) {
+	finishedJob.finish(status)
+	manager.finished = append(manager.finished, finishedJob)
+
+	for len(manager.finished) > manager.maxFinishedJobs {
+		delete(manager.jobs, manager.finished[0].job.ID)
+		manager.finished[0] = nil
+		manager.finished = manager.finished[1:]
+	}
+}
+
+func (managed *managedJob) finish(status Status) {
+	finishedAt := time.Now().UTC()
+	managed.job.FinishedAt = &finishedAt
+	managed.job.Status = status
+}
+
+func (managed *managedJob) snapshot() Job {
+	result := managed.job
+	result.Progress = managed.progress.Snapshot()
+	result.Report = managed.progress.Report()
+	return result
+}
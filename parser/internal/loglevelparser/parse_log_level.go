@@ -8,9 +8,14 @@ import (
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
+// The regular expressions are compiled once, they are used for every line of the log files.
+var (
+	levelRegex        = regexp.MustCompile(formats.LogLevelsRegex)
+	ignoredLevelRegex = regexp.MustCompile(formats.IgnoredLogLevelsRegex)
+)
+
 // ParseLogLevelAndFilter decides if a line is relevant in the input file.
 func ParseLogLevelAndFilter(line string) *models.EntryWithLogLevel {
-	levelRegex, _ := regexp.Compile(formats.LogLevelsRegex)
 	logLevel := levelRegex.FindString(line)
 	if logLevel != "" {
 		restOfLine := strings.Replace(line, logLevel, "", 1)
@@ -20,3 +25,18 @@ func ParseLogLevelAndFilter(line string) *models.EntryWithLogLevel {
 	// could not parse log level
 	return nil
 }
+
+// HasIgnoredLogLevel checks if the line has a log level that is intentionally filtered out, eg.: VERBOSE.
+func HasIgnoredLogLevel(line string) bool {
+	return ignoredLevelRegex.MatchString(line)
+}
+
+// ParseVerboseLogLevel parses the log level of a VERBOSE line, returns nil if the line has a different log level.
+func ParseVerboseLogLevel(line string) *models.EntryWithLogLevel {
+	if !strings.Contains(line, formats.VerboseLogLevel) {
+		return nil
+	}
+
+	restOfLine := strings.Replace(line, formats.VerboseLogLevel, "", 1)
+	return &models.EntryWithLogLevel{Level: formats.VerboseLogLevel, Rest: restOfLine}
+}
@@ -0,0 +1,257 @@
+package logparser
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+)
+
+// FollowConfig contains the settings of the follow mode.
+type FollowConfig struct {
+	// FileNames contains the names of the log files to follow, eg.: dc_main.log and plc_manager.log.
+	FileNames []string
+
+	// PollInterval is the time to wait between checking the files for new lines.
+	PollInterval time.Duration
+
+	// FromBeginning makes the follower parse the existing contents of the files first,
+	// otherwise only the lines appended after the start of the follow mode are parsed.
+	FromBeginning bool
+}
+
+// followedFile contains the state of a single followed log file.
+type followedFile struct {
+	name     string
+	position fileparser.Position
+	stat     filedownloader.FileStat
+	exists   bool
+
+	// head is the fingerprint of the start of the parsed part of the file, it is used to detect rotation.
+	head filedownloader.FileHead
+
+	// skipExisting is true until the lines existing at the start of the follow mode have been skipped.
+	skipExisting bool
+}
+
+// FollowLogfiles keeps parsing the lines appended to the given log files,
+// and forwards them to the provided rabbitMQ producer, until the stop channel is closed.
+// The statistics report of the followed lines is published when the follow mode stops.
+// Truncated and rotated files are parsed again from the beginning.
+// The lines that could not be published are parsed again at the next poll.
+// Returns an error if the start or the end of the run, or the report could not be published.
+// Returns ErrRangeDownloaderRequired if the file downloader of the parser does not implement
+// the filedownloader.RangeDownloader interface.
+func (logparser *LogParser) FollowLogfiles(config FollowConfig, stop <-chan struct{}) error {
+	rangeDownloader, ok := logparser.fileDownloader.(filedownloader.RangeDownloader)
+	if !ok {
+		return ErrRangeDownloaderRequired
+	}
+
+	runProgress := progress.NewProgress()
+	runID, err := logparser.startRun(config.FileNames, runProgress)
+	if err != nil {
+		return err
+	}
+
+	fileParser := logparser.newFileParser(
+		context.Background(), logparser.rabbitMqProducer, runProgress, logparser.newDeduplicator())
+
+	var wg sync.WaitGroup
+	for _, fileName := range config.FileNames {
+		wg.Add(1)
+		go logparser.followFile(rangeDownloader, fileParser, fileName, config, stop, &wg)
+	}
+	wg.Wait()
+
+	log.Printf("  [PARSER] Stopped following log files")
+	runReport, reportErr := logparser.finishReport(runProgress)
+	err = logparser.endRun(runID, config.FileNames, runReport)
+	if err != nil {
+		return err
+	}
+
+	return reportErr
+}
+
+func (logparser *LogParser) followFile(
+	rangeDownloader filedownloader.RangeDownloader,
+	fileParser *fileparser.FileParser,
+	fileName string,
+	config FollowConfig,
+	stop <-chan struct{},
+	wg *sync.WaitGroup,
+) {
+	defer wg.Done()
+	log.Printf("  [PARSER] Following log file: %s ...", fileName)
+
+	file := followedFile{name: fileName, skipExisting: !config.FromBeginning}
+
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// A file that can not be read or published is tried again at the next poll.
+		if err := readAppendedLines(rangeDownloader, fileParser, &file); err != nil {
+			log.Printf("  [PARSER] Could not read followed log file %s: %s", fileName, err)
+		}
+
+		select {
+		case <-stop:
+			log.Printf("  [PARSER] Stopped following log file: %s", fileName)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// readAppendedLines parses the complete lines appended to the file since the last read.
+// An incomplete last line is left in the file, and is parsed when the rest of it is written.
+func readAppendedLines(
+	rangeDownloader filedownloader.RangeDownloader,
+	fileParser *fileparser.FileParser,
+	file *followedFile,
+) error {
+	stat, exists, err := rangeDownloader.StatFile(file.name)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if file.exists {
+			log.Printf("  [PARSER] Followed log file disappeared: %s", file.name)
+		}
+
+		// The file is probably being rotated, the new file is parsed from the beginning.
+		file.exists = false
+		file.skipExisting = false
+		file.position = fileparser.Position{}
+		file.head = filedownloader.FileHead{}
+		return nil
+	}
+
+	if file.skipExisting {
+		return skipExistingLines(rangeDownloader, file, stat)
+	}
+
+	rotated, err := isRotated(rangeDownloader, file, stat)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case file.exists && rotated:
+		log.Printf("  [PARSER] Followed log file was rotated: %s", file.name)
+		file.position = fileparser.Position{}
+		file.head = filedownloader.FileHead{}
+
+	case stat.Size < file.position.Offset:
+		log.Printf("  [PARSER] Followed log file was truncated: %s", file.name)
+		file.position = fileparser.Position{}
+		file.head = filedownloader.FileHead{}
+	}
+
+	file.stat = stat
+	file.exists = true
+
+	if stat.Size == file.position.Offset {
+		return nil
+	}
+
+	readCloser, err := rangeDownloader.DownloadFileFrom(file.name, file.position.Offset)
+	if err != nil {
+		return err
+	}
+	defer readCloser.Close()
+
+	file.position, err = fileParser.ParseCompleteLines(readCloser, file.name, file.position)
+	if err != nil {
+		return err
+	}
+
+	// The head grows with the parsed part of the file, until it reaches its maximum length.
+	if file.head.Length < filedownloader.MaxHeadLength && file.position.Offset > file.head.Length {
+		file.head, err = filedownloader.ReadFileHead(rangeDownloader, file.name, file.position.Offset)
+	}
+
+	return err
+}
+
+// isRotated checks if the followed file was replaced by a new file with the same name since the last read.
+// The creation time of the file is compared if the file downloader knows it, otherwise the head of the file,
+// which is only read again if the file has changed.
+func isRotated(
+	rangeDownloader filedownloader.RangeDownloader,
+	file *followedFile,
+	stat filedownloader.FileStat,
+) (bool, error) {
+	if isReplaced(file.stat, stat) {
+		return true, nil
+	}
+
+	if stat.Size == file.stat.Size && stat.ETag == file.stat.ETag && stat.LastModified.Equal(file.stat.LastModified) {
+		return false, nil
+	}
+
+	sameHead, err := filedownloader.HasSameHead(rangeDownloader, file.name, file.head)
+	return !sameHead, err
+}
+
+// skipExistingLines skips the lines existing at the start of the follow mode, the position of the file is set to
+// the end of the last complete line. The existing lines are counted so that the line numbers of the appended lines
+// are correct.
+func skipExistingLines(
+	rangeDownloader filedownloader.RangeDownloader,
+	file *followedFile,
+	stat filedownloader.FileStat,
+) error {
+	readCloser, err := rangeDownloader.DownloadFileFrom(file.name, 0)
+	if err != nil {
+		return err
+	}
+	defer readCloser.Close()
+
+	position := fileparser.Position{}
+	reader := bufio.NewReader(io.LimitReader(readCloser, stat.Size))
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			// An incomplete last line is parsed when the rest of it is written.
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		position.Offset += int64(len(line))
+		position.LineNumber++
+	}
+
+	head, err := filedownloader.ReadFileHead(rangeDownloader, file.name, position.Offset)
+	if err != nil {
+		return err
+	}
+
+	file.position = position
+	file.head = head
+	file.stat = stat
+	file.exists = true
+	file.skipExisting = false
+	return nil
+}
+
+// isReplaced checks if the file was replaced by a new file with the same name, eg.: by log rotation.
+func isReplaced(previous filedownloader.FileStat, current filedownloader.FileStat) bool {
+	if previous.CreationTime.IsZero() || current.CreationTime.IsZero() {
+		return false
+	}
+
+	return !previous.CreationTime.Equal(current.CreationTime)
+}
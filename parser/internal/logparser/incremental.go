@@ -0,0 +1,119 @@
+package logparser
+
+import (
+	"log"
+
+	"github.com/kozgot/go-log-processing/parser/internal/checkpoint"
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+)
+
+// parseFileIncrementally parses the part of the file that has not been parsed in a previous run,
+// and saves the new checkpoint of the file. The checkpoint is not saved if the file could not be parsed.
+func (logparser *LogParser) parseFileIncrementally(fileParser *fileparser.FileParser, fileName string) error {
+	rangeDownloader := logparser.fileDownloader.(filedownloader.RangeDownloader)
+	stat, exists, err := rangeDownloader.StatFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		log.Printf("  [PARSER] Log file disappeared: %s", fileName)
+		return nil
+	}
+
+	start := fileparser.Position{}
+	previousCheckpoint, ok := logparser.checkpointStore.Get(fileName)
+	if ok && !logparser.reprocessAll {
+		if isUnchanged(previousCheckpoint, stat) {
+			log.Printf("  [PARSER] Skipping unchanged log file: %s", fileName)
+			return nil
+		}
+
+		appended, err := isAppended(rangeDownloader, previousCheckpoint, stat)
+		if err != nil {
+			return err
+		}
+
+		if appended {
+			start = fileparser.Position{Offset: previousCheckpoint.Offset, LineNumber: previousCheckpoint.LineNumber}
+		} else {
+			log.Printf("  [PARSER] Log file was replaced, parsing it from the beginning: %s", fileName)
+		}
+	}
+
+	readCloser, err := rangeDownloader.DownloadFileFrom(fileName, start.Offset)
+	if err != nil {
+		return err
+	}
+
+	end, compressed, err := fileParser.ParseFileFrom(readCloser, fileName, start)
+	if err != nil {
+		return err
+	}
+
+	if compressed {
+		if fileParser.Cancelled() {
+			// A partially parsed compressed file has to be parsed again from the beginning.
+			return nil
+		}
+
+		end = fileparser.Position{Offset: stat.Size}
+	}
+
+	head := filedownloader.FileHead{}
+	if !compressed {
+		head, err = filedownloader.ReadFileHead(rangeDownloader, fileName, end.Offset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return logparser.checkpointStore.Save(checkpoint.Checkpoint{
+		FileName:     fileName,
+		Size:         stat.Size,
+		ETag:         stat.ETag,
+		LastModified: stat.LastModified,
+		CreationTime: stat.CreationTime,
+		Offset:       end.Offset,
+		LineNumber:   end.LineNumber,
+		Compressed:   compressed,
+		Head:         head,
+	})
+}
+
+// isUnchanged checks if the file has not changed since the checkpoint was saved,
+// and it was parsed to its end, not only up to the point where a run was cancelled.
+func isUnchanged(previousCheckpoint checkpoint.Checkpoint, stat filedownloader.FileStat) bool {
+	parsedToEnd := previousCheckpoint.Compressed || previousCheckpoint.Offset == previousCheckpoint.Size
+
+	return parsedToEnd && previousCheckpoint.Size == stat.Size &&
+		previousCheckpoint.ETag == stat.ETag &&
+		previousCheckpoint.LastModified.Equal(stat.LastModified) &&
+		previousCheckpoint.CreationTime.Equal(stat.CreationTime)
+}
+
+// isAppended checks if the file has only grown since the checkpoint was saved,
+// so that it can be parsed from the stored offset. A file replaced by another file with the same name
+// has a different creation time or head, or it was modified before the checkpoint was saved.
+// Compressed files can not be parsed from an offset, they are parsed from the beginning when they change.
+func isAppended(
+	rangeDownloader filedownloader.RangeDownloader,
+	previousCheckpoint checkpoint.Checkpoint,
+	stat filedownloader.FileStat,
+) (bool, error) {
+	if previousCheckpoint.Compressed {
+		return false, nil
+	}
+
+	replaced := isReplaced(
+		filedownloader.FileStat{CreationTime: previousCheckpoint.CreationTime},
+		stat)
+
+	if replaced || stat.Size < previousCheckpoint.Size || stat.Size < previousCheckpoint.Offset ||
+		stat.LastModified.Before(previousCheckpoint.LastModified) {
+		return false, nil
+	}
+
+	return filedownloader.HasSameHead(rangeDownloader, previousCheckpoint.FileName, previousCheckpoint.Head)
+}
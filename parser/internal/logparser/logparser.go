@@ -1,18 +1,54 @@
 package logparser
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/kozgot/go-log-processing/parser/internal/checkpoint"
+	"github.com/kozgot/go-log-processing/parser/internal/dedup"
 	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
 	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/formatregistry"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/quarantine"
 	"github.com/kozgot/go-log-processing/parser/internal/rabbitmq"
+	"github.com/kozgot/go-log-processing/parser/internal/report"
+	"github.com/kozgot/go-log-processing/parser/internal/timezone"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
+// DefaultWorkerCount is the number of files parsed concurrently if it is not set explicitly.
+const DefaultWorkerCount = 4
+
+// ErrRangeDownloaderRequired is returned if the incremental parsing or the follow mode is used
+// with a file downloader that does not implement the filedownloader.RangeDownloader interface.
+var ErrRangeDownloaderRequired = errors.New("the file downloader does not support downloading a part of a file")
+
 // LogParser encapsulates parser data and logic.
 type LogParser struct {
 	fileDownloader   filedownloader.FileDownloader
 	rabbitMqProducer rabbitmq.MessageProducer
+	checkpointStore  checkpoint.Store
+	reprocessAll     bool
+	workerCount      int
+	includeRawLines  bool
+	verboseEntries   fileparser.VerboseEntries
+
+	// continuationRules is nil if the default rules of the file parser are used.
+	continuationRules []fileparser.ContinuationRule
+
+	formatRegistry  *formatregistry.Registry
+	quarantineSink  quarantine.Sink
+	reportPublisher report.Publisher
+	timezones       *timezone.Resolver
+
+	// deduplicationWindow is 0 if the duplicate entries are not suppressed.
+	deduplicationWindow time.Duration
 }
 
 // NewLogParser creates a new LogParser.
@@ -20,28 +56,355 @@ func NewLogParser(fileDownloader filedownloader.FileDownloader, rabbitMqProducer
 	logparser := LogParser{
 		fileDownloader:   fileDownloader,
 		rabbitMqProducer: rabbitMqProducer,
+		workerCount:      DefaultWorkerCount,
+		timezones:        timezone.NewResolver(nil, nil),
 	}
 
 	return &logparser
 }
 
+// NewIncrementalLogParser creates a new LogParser that records the parsed parts of the log files in
+// the given checkpoint store, and skips them in later runs. Unchanged files are skipped,
+// and the lines appended to a file since the last run are parsed from the stored offset.
+// If reprocessAll is true, the stored checkpoints are ignored, and every file is parsed from the beginning.
+// Returns ErrRangeDownloaderRequired if the file downloader does not implement
+// the filedownloader.RangeDownloader interface.
+func NewIncrementalLogParser(
+	fileDownloader filedownloader.FileDownloader,
+	rabbitMqProducer rabbitmq.MessageProducer,
+	checkpointStore checkpoint.Store,
+	reprocessAll bool,
+) (*LogParser, error) {
+	if _, ok := fileDownloader.(filedownloader.RangeDownloader); !ok {
+		return nil, ErrRangeDownloaderRequired
+	}
+
+	logparser := LogParser{
+		fileDownloader:   fileDownloader,
+		rabbitMqProducer: rabbitMqProducer,
+		checkpointStore:  checkpointStore,
+		reprocessAll:     reprocessAll,
+		workerCount:      DefaultWorkerCount,
+		timezones:        timezone.NewResolver(nil, nil),
+	}
+
+	return &logparser, nil
+}
+
+// SetWorkerCount sets the maximum number of files that are downloaded and parsed concurrently.
+// Returns an error if the worker count is less than 1.
+func (logparser *LogParser) SetWorkerCount(workerCount int) error {
+	if workerCount < 1 {
+		return fmt.Errorf("invalid worker count: %d", workerCount)
+	}
+
+	logparser.workerCount = workerCount
+	return nil
+}
+
+// SetIncludeRawLines sets whether a copy of the original line is included in the source location of the entries.
+func (logparser *LogParser) SetIncludeRawLines(includeRawLines bool) {
+	logparser.includeRawLines = includeRawLines
+}
+
+// SetVerboseEntries sets the VERBOSE entries that are parsed, the other VERBOSE entries are filtered out.
+func (logparser *LogParser) SetVerboseEntries(verboseEntries fileparser.VerboseEntries) {
+	logparser.verboseEntries = verboseEntries
+}
+
+// SetContinuationRules sets the rules used to join the continuation lines of multi-line entries.
+func (logparser *LogParser) SetContinuationRules(rules []fileparser.ContinuationRule) {
+	logparser.continuationRules = rules
+}
+
+// SetFormatRegistry sets the declarative entry formats used to parse entries into a generic payload.
+func (logparser *LogParser) SetFormatRegistry(registry *formatregistry.Registry) {
+	logparser.formatRegistry = registry
+}
+
+// SetQuarantineSink sets the sink of the lines that could not be parsed.
+// The sink must be opened and closed by the caller.
+func (logparser *LogParser) SetQuarantineSink(sink quarantine.Sink) {
+	logparser.quarantineSink = sink
+}
+
+// SetReportPublisher sets the publisher of the statistics report, that is published at the end of every run.
+func (logparser *LogParser) SetReportPublisher(publisher report.Publisher) {
+	logparser.reportPublisher = publisher
+}
+
+// SetTimezoneResolver sets the resolver of the timezones of the DCs writing the log files.
+// By default, the timezones are only resolved from the settings entries of the files.
+func (logparser *LogParser) SetTimezoneResolver(resolver *timezone.Resolver) {
+	logparser.timezones = resolver
+}
+
+// SetDeduplicationWindow enables suppressing the entries that have already been published from another log file
+// of the same DC in the run, eg.: from a rotated copy. The fingerprints of the entries are remembered for the window.
+// Duplicates are not suppressed if the window is 0, which is the default.
+func (logparser *LogParser) SetDeduplicationWindow(window time.Duration) {
+	logparser.deduplicationWindow = window
+}
+
+// newDeduplicator creates the deduplicator of a run, or returns nil if the duplicates are not suppressed.
+func (logparser *LogParser) newDeduplicator() *dedup.Deduplicator {
+	if logparser.deduplicationWindow <= 0 {
+		return nil
+	}
+
+	return dedup.NewDeduplicator(logparser.deduplicationWindow)
+}
+
+// newFileParser creates a file parser with the settings of the log parser,
+// the file parsers of a run share the deduplicator of the run.
+func (logparser *LogParser) newFileParser(
+	ctx context.Context,
+	producer rabbitmq.MessageProducer,
+	runProgress *progress.Progress,
+	deduplicator *dedup.Deduplicator,
+) *fileparser.FileParser {
+	fileParser := fileparser.NewFileParser(ctx, producer, runProgress)
+	fileParser.SetIncludeRawLines(logparser.includeRawLines)
+	fileParser.SetVerboseEntries(logparser.verboseEntries)
+	fileParser.SetFormatRegistry(logparser.formatRegistry)
+	fileParser.SetQuarantineSink(logparser.quarantineSink)
+	fileParser.SetTimezoneResolver(logparser.timezones)
+	fileParser.SetDeduplicator(deduplicator)
+	if logparser.continuationRules != nil {
+		fileParser.SetContinuationRules(logparser.continuationRules)
+	}
+
+	return fileParser
+}
+
 // ParseLogfiles downloads log files from the given filedownloader, parses the log entries
-// and forwards them to the provided rabbitMQ producer.
-func (logparser *LogParser) ParseLogfiles() {
-	azureFileNames := logparser.fileDownloader.ListFileNames()
+// and forwards them to the provided rabbitMQ producer. Returns the statistics report of the run,
+// and an error if the files could not be listed, or some of them could not be downloaded.
+func (logparser *LogParser) ParseLogfiles() (report.Report, error) {
+	return logparser.ParseSelectedLogfiles(context.Background(), nil, progress.NewProgress())
+}
 
-	var wg sync.WaitGroup
-	for _, fileName := range azureFileNames {
-		readCloser := logparser.fileDownloader.DownloadFile(fileName)
+// ParseSelectedLogfiles downloads the selected log files from the given filedownloader, parses the log entries
+// and forwards them to the provided rabbitMQ producer. If the selection is empty, every file is parsed.
+// The run stops early when the context is cancelled, the progress of the run is recorded in runProgress.
+// Returns the statistics report of the run. The run is not started if the files could not be listed
+// or the start of the run could not be published. A file that could not be downloaded or published
+// is recorded in the progress, and the other files are parsed. An error is returned in every case,
+// and if the end of the run or the report could not be published.
+func (logparser *LogParser) ParseSelectedLogfiles(
+	ctx context.Context,
+	selectedFileNames []string,
+	runProgress *progress.Progress,
+) (report.Report, error) {
+	runProgress.RunStarted()
+	fileNames, err := logparser.selectFileNames(selectedFileNames, runProgress)
+	if err != nil {
+		runProgress.RunFinished()
+		return runProgress.Report(), err
+	}
+	runProgress.SetFilesTotal(len(fileNames))
+	runID, err := logparser.startRun(fileNames, runProgress)
+	if err != nil {
+		runProgress.RunFinished()
+		return runProgress.Report(), err
+	}
+	deduplicator := logparser.newDeduplicator()
 
+	// The files are handed out to the workers one by one, so a file is only downloaded when a worker is free.
+	fileNameChannel := make(chan string)
+
+	var wg sync.WaitGroup
+	for i := 0; i < logparser.workerCount && i < len(fileNames); i++ {
 		wg.Add(1)
-		go fileparser.ParseSingleFile(readCloser, fileName, &wg, logparser.rabbitMqProducer)
+		go logparser.runWorker(ctx, fileNameChannel, runProgress, deduplicator, &wg)
 	}
-	wg.Wait()
 
-	// Send a message indicating that we have reached the end of the log files.
-	logparser.rabbitMqProducer.PublishStringMessage("END")
-	log.Printf("  [PARSER] Sent END to Postprocessing service ...")
+	for _, fileName := range fileNames {
+		if ctx.Err() != nil {
+			log.Printf("  [PARSER] Parsing cancelled")
+			break
+		}
+
+		fileNameChannel <- fileName
+	}
+	close(fileNameChannel)
+	wg.Wait()
 
 	log.Printf("  [PARSER] Finished parsing all files")
+
+	// The end of the run is sent even if the report could not be published, so the postprocessor finishes the run.
+	runReport, reportErr := logparser.finishReport(runProgress)
+	err = logparser.endRun(runID, fileNames, runReport)
+	if err == nil {
+		err = reportErr
+	}
+
+	if err != nil {
+		return runReport, err
+	}
+
+	if filesFailed := runProgress.Snapshot().FilesFailed; filesFailed > 0 {
+		return runReport, fmt.Errorf("could not parse %d of %d files", filesFailed, len(fileNames))
+	}
+
+	return runReport, nil
+}
+
+// startRun sends the start of a new run to the postprocessor, and returns the ID of the run.
+// A new run ID is generated, unless it is set in the progress of the run, eg.: to the ID of the job.
+func (logparser *LogParser) startRun(fileNames []string, runProgress *progress.Progress) (string, error) {
+	runID := runProgress.RunID()
+	if runID == "" {
+		runID = uuid.New().String()
+		runProgress.SetRunID(runID)
+	}
+
+	err := logparser.rabbitMqProducer.PublishRunControl(models.NewRunStart(runID, fileNames))
+	if err != nil {
+		return "", fmt.Errorf("could not publish the start of run %s: %w", runID, err)
+	}
+
+	log.Printf("  [PARSER] Started run %s", runID)
+	return runID, nil
+}
+
+// endRun sends the end of the run to the postprocessor, with the number of entries published from each file,
+// so the postprocessor can wait for every entry of the run before processing the consumption data.
+func (logparser *LogParser) endRun(runID string, fileNames []string, runReport report.Report) error {
+	entryCounts := make(map[string]int)
+	for _, fileReport := range runReport.Files {
+		entryCounts[fileReport.FileName] = fileReport.EntriesParsed
+	}
+
+	runEnd := models.NewRunEnd(runID, fileNames, entryCounts)
+	err := logparser.rabbitMqProducer.PublishRunControl(runEnd)
+	if err != nil {
+		return fmt.Errorf("could not publish the end of run %s: %w", runID, err)
+	}
+
+	log.Printf("  [PARSER] Sent the end of run %s with %d entries to Postprocessing service ...",
+		runID, runEnd.TotalEntries)
+	return nil
+}
+
+// finishReport records the end of the run, and publishes the statistics report of the run.
+// The report is returned even if it could not be published.
+func (logparser *LogParser) finishReport(runProgress *progress.Progress) (report.Report, error) {
+	runProgress.RunFinished()
+	runReport := runProgress.Report()
+	log.Printf(
+		"  [PARSER] Read %d lines, parsed %d entries, suppressed %d duplicates, "+
+			"filtered %d lines, quarantined %d lines (%d malformed)",
+		runReport.Totals.LinesRead,
+		runReport.Totals.EntriesParsed,
+		runReport.Totals.DuplicatesSuppressed,
+		runReport.Totals.LinesFiltered,
+		runReport.Totals.LinesQuarantined,
+		runReport.Totals.LinesMalformed)
+
+	if logparser.reportPublisher != nil {
+		err := logparser.reportPublisher.Publish(runReport)
+		if err != nil {
+			return runReport, fmt.Errorf("could not publish the parse report: %w", err)
+		}
+	}
+
+	return runReport, nil
+}
+
+// runWorker parses the files received on the file name channel one after the other.
+// Each worker publishes the entries on its own channel if the producer supports it,
+// the files of a worker fail if its channel could not be opened.
+func (logparser *LogParser) runWorker(
+	ctx context.Context,
+	fileNameChannel <-chan string,
+	runProgress *progress.Progress,
+	deduplicator *dedup.Deduplicator,
+	wg *sync.WaitGroup,
+) {
+	defer wg.Done()
+
+	producer := logparser.rabbitMqProducer
+	if factory, ok := producer.(rabbitmq.ChannelProducerFactory); ok {
+		var err error
+		producer, err = factory.NewChannelProducer()
+		if err != nil {
+			log.Printf("  [PARSER] Could not create the producer of a worker: %s", err)
+			for fileName := range fileNameChannel {
+				runProgress.FileFailed(fileName, err)
+			}
+			return
+		}
+		defer producer.CloseChannelAndConnection()
+	}
+
+	fileParser := logparser.newFileParser(ctx, producer, runProgress, deduplicator)
+	for fileName := range fileNameChannel {
+		logparser.parseFile(fileParser, fileName, runProgress)
+	}
+}
+
+func (logparser *LogParser) parseFile(
+	fileParser *fileparser.FileParser,
+	fileName string,
+	runProgress *progress.Progress,
+) {
+	var err error
+	if logparser.checkpointStore != nil {
+		err = logparser.parseFileIncrementally(fileParser, fileName)
+	} else {
+		err = logparser.parseSingleFile(fileParser, fileName)
+	}
+
+	if err != nil {
+		log.Printf("  [PARSER] Could not parse log file %s: %s", fileName, err)
+		runProgress.FileFailed(fileName, err)
+		return
+	}
+
+	if !fileParser.Cancelled() {
+		runProgress.FileDone()
+	}
+}
+
+func (logparser *LogParser) parseSingleFile(fileParser *fileparser.FileParser, fileName string) error {
+	readCloser, err := logparser.fileDownloader.DownloadFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	return fileParser.ParseSingleFile(readCloser, fileName)
+}
+
+// selectFileNames returns the names of the selected files that are available in the file downloader.
+func (logparser *LogParser) selectFileNames(
+	selectedFileNames []string,
+	runProgress *progress.Progress,
+) ([]string, error) {
+	availableFileNames, err := logparser.fileDownloader.ListFileNames()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(selectedFileNames) == 0 {
+		return availableFileNames, nil
+	}
+
+	available := make(map[string]bool)
+	for _, fileName := range availableFileNames {
+		available[fileName] = true
+	}
+
+	fileNames := []string{}
+	for _, fileName := range selectedFileNames {
+		if !available[fileName] {
+			log.Printf("  [PARSER] Selected file not found: %s", fileName)
+			runProgress.AddError("Selected file not found: " + fileName)
+			continue
+		}
+
+		fileNames = append(fileNames, fileName)
+	}
+
+	return fileNames, nil
 }
@@ -0,0 +1,157 @@
+package ndjson
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+var errInvalidFilter = errors.New("invalid entry filter")
+
+// Filter selects the parsed entries that are written.
+type Filter struct {
+	// Levels are the log levels of the written entries, entries of every level are written if it is empty.
+	Levels []string
+
+	// From is the earliest timestamp of the written entries, there is no lower bound if it is zero.
+	From time.Time
+
+	// To is the end of the time range of the written entries, entries at To are not written.
+	// There is no upper bound if it is zero.
+	To time.Time
+}
+
+// ParseFilter creates a filter from a comma separated list of log levels,
+// and the bounds of the time range in RFC 3339 format, any of them may be empty.
+func ParseFilter(levels string, from string, to string) (Filter, error) {
+	filter := Filter{}
+	for _, level := range strings.Split(levels, ",") {
+		level = strings.TrimSpace(level)
+		if level != "" {
+			filter.Levels = append(filter.Levels, strings.ToUpper(level))
+		}
+	}
+
+	var err error
+	if from != "" {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return Filter{}, err
+		}
+	}
+
+	if to != "" {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return Filter{}, err
+		}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return Filter{}, errInvalidFilter
+	}
+
+	return filter, nil
+}
+
+// Matches checks if the entry is selected by the filter.
+func (filter Filter) Matches(entry models.ParsedLogEntry) bool {
+	if !filter.From.IsZero() && entry.Timestamp.Before(filter.From) {
+		return false
+	}
+
+	if !filter.To.IsZero() && !entry.Timestamp.Before(filter.To) {
+		return false
+	}
+
+	if len(filter.Levels) == 0 {
+		return true
+	}
+
+	for _, level := range filter.Levels {
+		if strings.EqualFold(level, entry.Level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EntryWriter writes the parsed entries selected by its filter, one JSON document per line.
+// It implements the MessageProducer interface, so it can replace the rabbitMQ producer of the file parser.
+type EntryWriter struct {
+	writer         *bufio.Writer
+	filter         Filter
+	mutex          sync.Mutex
+	entriesWritten int
+}
+
+// NewEntryWriter creates a new EntryWriter that writes to the given writer.
+func NewEntryWriter(writer io.Writer, filter Filter) *EntryWriter {
+	entryWriter := EntryWriter{writer: bufio.NewWriter(writer), filter: filter}
+	return &entryWriter
+}
+
+// PublishRunControl ignores the run control messages, only the parsed entries are written.
+func (entryWriter *EntryWriter) PublishRunControl(control models.RunControl) error {
+	return nil
+}
+
+// PublishEntry writes the entry if it is selected by the filter.
+func (entryWriter *EntryWriter) PublishEntry(line models.ParsedLogEntry) error {
+	if !entryWriter.filter.Matches(line) {
+		return nil
+	}
+
+	entryWriter.mutex.Lock()
+	defer entryWriter.mutex.Unlock()
+
+	_, err := entryWriter.writer.Write(append(line.Serialize(), '\n'))
+	if err != nil {
+		return fmt.Errorf("could not write parsed entry: %w", err)
+	}
+
+	entryWriter.entriesWritten++
+	return nil
+}
+
+// OpenChannelAndConnection does nothing, the writer is ready to use when it is created.
+func (entryWriter *EntryWriter) OpenChannelAndConnection() error {
+	return nil
+}
+
+// CloseChannelAndConnection flushes the buffered entries, the underlying writer is not closed.
+// Call Flush instead to handle the error of writing the buffered entries.
+func (entryWriter *EntryWriter) CloseChannelAndConnection() {
+	err := entryWriter.Flush()
+	if err != nil {
+		log.Printf("  [PARSER] %s", err)
+	}
+}
+
+// Flush writes the buffered entries to the underlying writer.
+func (entryWriter *EntryWriter) Flush() error {
+	entryWriter.mutex.Lock()
+	defer entryWriter.mutex.Unlock()
+
+	err := entryWriter.writer.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush parsed entries: %w", err)
+	}
+
+	return nil
+}
+
+// EntriesWritten returns the number of entries written so far.
+func (entryWriter *EntryWriter) EntriesWritten() int {
+	entryWriter.mutex.Lock()
+	defer entryWriter.mutex.Unlock()
+	return entryWriter.entriesWritten
+}
@@ -0,0 +1,187 @@
+package progress
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/report"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+// Progress tracks the progress of a parser run, it is safe to update it from multiple goroutines.
+type Progress struct {
+	mutex    sync.Mutex
+	snapshot Snapshot
+
+	// runID identifies the run, it is sent with every published entry.
+	runID string
+
+	// The statistics of the parsed log files by file name.
+	files      map[string]*report.Statistics
+	startedAt  time.Time
+	finishedAt *time.Time
+}
+
+// Snapshot contains the state of a parser run at a given moment, it is returned by the job API in JSON format.
+type Snapshot struct {
+	FilesTotal           int      `json:"FilesTotal"`
+	FilesDone            int      `json:"FilesDone"`
+	FilesFailed          int      `json:"FilesFailed"`
+	LinesRead            int      `json:"LinesRead"`
+	EntriesPublished     int      `json:"EntriesPublished"`
+	LinesQuarantined     int      `json:"LinesQuarantined"`
+	DuplicatesSuppressed int      `json:"DuplicatesSuppressed"`
+	Errors               []string `json:"Errors"`
+}
+
+// NewProgress creates a new Progress.
+func NewProgress() *Progress {
+	return &Progress{
+		snapshot:  Snapshot{Errors: []string{}},
+		files:     make(map[string]*report.Statistics),
+		startedAt: time.Now().UTC(),
+	}
+}
+
+// SetRunID sets the ID of the run.
+func (progress *Progress) SetRunID(runID string) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.runID = runID
+}
+
+// RunID returns the ID of the run, it is empty until the run is started.
+func (progress *Progress) RunID() string {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	return progress.runID
+}
+
+// SetFilesTotal sets the number of files to parse in the run.
+func (progress *Progress) SetFilesTotal(filesTotal int) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.snapshot.FilesTotal = filesTotal
+}
+
+// FileDone increments the number of parsed files.
+func (progress *Progress) FileDone() {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.snapshot.FilesDone++
+}
+
+// FileFailed records a file that could not be parsed, eg.: because it could not be downloaded.
+func (progress *Progress) FileFailed(fileName string, err error) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.snapshot.FilesFailed++
+	progress.snapshot.Errors = append(progress.snapshot.Errors, "Could not parse "+fileName+": "+err.Error())
+}
+
+// RunStarted records the start of the run, if the progress was created before the run started.
+func (progress *Progress) RunStarted() {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.startedAt = time.Now().UTC()
+}
+
+// RunFinished records the end of the run.
+func (progress *Progress) RunFinished() {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	finishedAt := time.Now().UTC()
+	progress.finishedAt = &finishedAt
+}
+
+// LineRead increments the number of read lines.
+func (progress *Progress) LineRead(fileName string) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.snapshot.LinesRead++
+	progress.fileStatistics(fileName).AddLineRead()
+}
+
+// EntryPublished increments the number of published entries, and records the entry in the statistics of its file.
+func (progress *Progress) EntryPublished(entry models.ParsedLogEntry) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.snapshot.EntriesPublished++
+	progress.fileStatistics(entry.Source.FileName).AddEntry(entry)
+}
+
+// DuplicateSuppressed increments the number of duplicate entries that were not published.
+func (progress *Progress) DuplicateSuppressed(fileName string) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.snapshot.DuplicatesSuppressed++
+	progress.fileStatistics(fileName).AddDuplicate()
+}
+
+// LineRejected records a line that has been rejected by the parser,
+// and increments the number of quarantined lines if the line is quarantined.
+func (progress *Progress) LineRejected(fileName string, reason models.RejectReason) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	if models.IsQuarantined(reason) {
+		progress.snapshot.LinesQuarantined++
+	}
+
+	progress.fileStatistics(fileName).AddRejectedLine(reason)
+}
+
+func (progress *Progress) fileStatistics(fileName string) *report.Statistics {
+	statistics, ok := progress.files[fileName]
+	if !ok {
+		newStatistics := report.NewStatistics()
+		statistics = &newStatistics
+		progress.files[fileName] = statistics
+	}
+
+	return statistics
+}
+
+// AddError records an error that did not stop the run.
+func (progress *Progress) AddError(message string) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.snapshot.Errors = append(progress.snapshot.Errors, message)
+}
+
+// Snapshot returns a copy of the current state of the run.
+func (progress *Progress) Snapshot() Snapshot {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+
+	snapshot := progress.snapshot
+	snapshot.Errors = append([]string{}, progress.snapshot.Errors...)
+	return snapshot
+}
+
+// Report returns the statistics of the run in total and by log file, the files are sorted by name.
+func (progress *Progress) Report() report.Report {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+
+	result := report.Report{
+		RunID:      progress.runID,
+		StartedAt:  progress.startedAt,
+		FinishedAt: progress.finishedAt,
+		Totals:     report.NewStatistics(),
+		Files:      []report.FileReport{},
+	}
+
+	for fileName, statistics := range progress.files {
+		fileReport := report.FileReport{FileName: fileName, Statistics: report.NewStatistics()}
+		fileReport.Add(*statistics)
+		result.Files = append(result.Files, fileReport)
+		result.Totals.Add(*statistics)
+	}
+
+	sort.Slice(result.Files, func(i, j int) bool {
+		return result.Files[i].FileName < result.Files[j].FileName
+	})
+
+	return result
+}
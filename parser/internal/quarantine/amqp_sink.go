@@ -0,0 +1,127 @@
+package quarantine
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/streadway/amqp"
+)
+
+// AmqpSink publishes the rejected lines to a dead-letter routing key.
+type AmqpSink struct {
+	connection   *amqp.Connection
+	channel      *amqp.Channel
+	mutex        sync.Mutex
+	routingKey   string
+	exchangeName string
+	rabbitMqURL  string
+}
+
+// NewAmqpSink creates a new AmqpSink that publishes to the given exchange with the dead-letter routing key.
+// A durable queue with the same name as the routing key is declared and bound to it,
+// so the rejected lines are kept until they are inspected.
+func NewAmqpSink(routingKey string, exchangeName string, rabbitMqURL string) *AmqpSink {
+	sink := AmqpSink{routingKey: routingKey, exchangeName: exchangeName, rabbitMqURL: rabbitMqURL}
+	return &sink
+}
+
+// Open opens a connection and a channel, and declares the dead-letter queue.
+// The connection is closed if the queue could not be declared.
+func (sink *AmqpSink) Open() error {
+	var err error
+	sink.connection, err = amqp.Dial(sink.rabbitMqURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	err = sink.declareQueue()
+	if err != nil {
+		sink.connection.Close()
+		return err
+	}
+
+	log.Println("  [QUARANTINE] Publishing rejected lines to routing key: " + sink.routingKey)
+	return nil
+}
+
+// declareQueue opens the channel of the sink, and declares the dead-letter queue.
+func (sink *AmqpSink) declareQueue() error {
+	var err error
+	sink.channel, err = sink.connection.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open a channel: %w", err)
+	}
+
+	err = sink.channel.ExchangeDeclare(
+		sink.exchangeName, // name
+		"direct",          // type
+		true,              // durable
+		false,             // auto-deleted
+		false,             // internal
+		false,             // no-wait
+		nil,               // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare an exchange: %w", err)
+	}
+
+	_, err = sink.channel.QueueDeclare(
+		sink.routingKey, // name
+		true,            // durable
+		false,           // delete when unused
+		false,           // exclusive
+		false,           // no-wait
+		nil,             // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare the quarantine queue: %w", err)
+	}
+
+	err = sink.channel.QueueBind(
+		sink.routingKey,   // queue name
+		sink.routingKey,   // routing key
+		sink.exchangeName, // exchange
+		false,
+		nil)
+	if err != nil {
+		return fmt.Errorf("failed to bind the quarantine queue: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the channel and the connection.
+func (sink *AmqpSink) Close() error {
+	sink.channel.Close()
+	err := sink.connection.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close the connection: %w", err)
+	}
+
+	log.Println("  [QUARANTINE] Closed channel and connection")
+	return nil
+}
+
+// Quarantine publishes the rejected line to the dead-letter routing key.
+func (sink *AmqpSink) Quarantine(line models.RejectedLine) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	err := sink.channel.Publish(
+		sink.exchangeName, // exchange
+		sink.routingKey,   // routing key
+		false,             // mandatory
+		false,             // immediate
+		amqp.Publishing{
+			DeliveryMode: amqp.Persistent,
+			ContentType:  "application/json",
+			Body:         line.Serialize(),
+		})
+	if err != nil {
+		return fmt.Errorf("failed to publish a rejected line: %w", err)
+	}
+
+	return nil
+}
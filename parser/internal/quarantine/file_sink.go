@@ -0,0 +1,65 @@
+package quarantine
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+// FileSink appends the rejected lines to a file, one JSON document per line.
+type FileSink struct {
+	path  string
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// NewFileSink creates a new FileSink that writes to the file at the given path.
+func NewFileSink(path string) *FileSink {
+	sink := FileSink{path: path}
+	return &sink
+}
+
+// Open opens the quarantine file, the rejected lines are appended to the existing contents.
+func (sink *FileSink) Open() error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	var err error
+	sink.file, err = os.OpenFile(sink.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open quarantine file: %w", err)
+	}
+
+	log.Println("  [QUARANTINE] Opened quarantine file: " + sink.path)
+	return nil
+}
+
+// Close closes the quarantine file.
+func (sink *FileSink) Close() error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	err := sink.file.Close()
+	if err != nil {
+		return fmt.Errorf("could not close quarantine file: %w", err)
+	}
+
+	log.Println("  [QUARANTINE] Closed quarantine file: " + sink.path)
+	return nil
+}
+
+// Quarantine appends the rejected line to the quarantine file.
+func (sink *FileSink) Quarantine(line models.RejectedLine) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	_, err := sink.file.Write(append(line.Serialize(), '\n'))
+	if err != nil {
+		return fmt.Errorf("could not write quarantine file: %w", err)
+	}
+
+	return nil
+}
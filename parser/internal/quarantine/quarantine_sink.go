@@ -0,0 +1,11 @@
+package quarantine
+
+import "github.com/kozgot/go-log-processing/parser/pkg/models"
+
+// Sink interface describes the methods needed to quarantine the rejected lines of the log files.
+// Implementations must be safe to use from multiple goroutines.
+type Sink interface {
+	Quarantine(line models.RejectedLine) error
+	Open() error
+	Close() error
+}
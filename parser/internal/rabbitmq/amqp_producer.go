@@ -1,38 +1,121 @@
 package rabbitmq
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"sync"
+	"time"
 
-	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/confirm"
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 	"github.com/streadway/amqp"
 )
 
+const (
+	// publishWindowSize is the number of messages published before waiting for their confirmations.
+	publishWindowSize = 256
+
+	// confirmTimeout is the time to wait for the confirmations of a window before reconnecting.
+	confirmTimeout = 30 * time.Second
+
+	// maxReconnectAttempts is the number of reconnect attempts before giving up, the delay doubles after every attempt.
+	maxReconnectAttempts  = 10
+	initialReconnectDelay = time.Second
+	maxReconnectDelay     = 30 * time.Second
+)
+
+var (
+	errChannelClosed  = errors.New("channel closed while waiting for confirmations")
+	errConfirmTimeout = errors.New("timed out waiting for confirmations")
+)
+
 // AmqpProducer implements the MessageProducer interface.
+// The messages are published in confirm mode: at most publishWindowSize messages are published
+// before waiting for the confirmations of the broker, and the nacked messages are published again.
+// If publishing fails, the producer reconnects, declares the exchange again,
+// and publishes the messages that were not confirmed yet, so messages may be delivered twice, but are not lost.
 type AmqpProducer struct {
-	connection   *amqp.Connection
-	channel      *amqp.Channel
-	routingKey   string
-	exchangeName string
-	rabbitMqURL  string
+	connection    *amqp.Connection
+	channel       *amqp.Channel
+	confirmations chan amqp.Confirmation
+	window        *confirm.Window
+	mutex         sync.Mutex
+	routingKey    string
+	exchangeName  string
+	rabbitMqURL   string
+
+	// producerID identifies this parser instance in the envelopes of the published messages.
+	producerID string
+
+	// sharedConnection is true for producers created by NewChannelProducer,
+	// the connection of these producers is closed by the producer that opened it.
+	sharedConnection bool
 }
 
 // NewAmqpProducer creates a new AmqpProducer instance with the given parameters.
 func NewAmqpProducer(routingKey string, exchangeName string, rabbitMqURL string) *AmqpProducer {
-	result := AmqpProducer{routingKey: routingKey, exchangeName: exchangeName, rabbitMqURL: rabbitMqURL}
+	result := AmqpProducer{
+		window:       confirm.NewWindow(publishWindowSize),
+		routingKey:   routingKey,
+		exchangeName: exchangeName,
+		rabbitMqURL:  rabbitMqURL,
+		producerID:   models.NewProducerID("parser"),
+	}
 	return &result
 }
 
 // OpenChannelAndConnection opens a channel and a connection.
-func (producer *AmqpProducer) OpenChannelAndConnection() {
+func (producer *AmqpProducer) OpenChannelAndConnection() error {
+	err := producer.connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	return nil
+}
+
+// NewChannelProducer creates a producer that publishes on a new channel of the connection of this producer.
+func (producer *AmqpProducer) NewChannelProducer() (MessageProducer, error) {
+	result := AmqpProducer{
+		connection:       producer.connection,
+		window:           confirm.NewWindow(publishWindowSize),
+		routingKey:       producer.routingKey,
+		exchangeName:     producer.exchangeName,
+		rabbitMqURL:      producer.rabbitMqURL,
+		producerID:       producer.producerID,
+		sharedConnection: true,
+	}
+
+	err := result.openChannel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+
+	return &result, nil
+}
+
+// connect opens a new connection and a channel on it.
+func (producer *AmqpProducer) connect() error {
 	var err error
 	producer.connection, err = amqp.Dial(producer.rabbitMqURL)
-	utils.FailOnError(err, "Failed to connect to RabbitMQ")
+	if err != nil {
+		return err
+	}
 	log.Println("  [RABBITMQ PRODUCER] Created connection")
 
+	return producer.openChannel()
+}
+
+// openChannel opens a channel in confirm mode, and declares the exchange.
+func (producer *AmqpProducer) openChannel() error {
+	var err error
+
 	// create the channel
 	producer.channel, err = producer.connection.Channel()
-	utils.FailOnError(err, "Failed to open a channel")
+	if err != nil {
+		return err
+	}
 	log.Println("  [RABBITMQ PRODUCER] Created channel")
 
 	err = producer.channel.ExchangeDeclare(
@@ -44,32 +127,215 @@ func (producer *AmqpProducer) OpenChannelAndConnection() {
 		false,                 // no-wait
 		nil,                   // arguments
 	)
-	utils.FailOnError(err, "Failed to declare an exchange")
+	if err != nil {
+		return err
+	}
+
+	err = producer.channel.Confirm(false)
+	if err != nil {
+		return err
+	}
+
+	// The buffer holds the confirmations of a whole window, so the connection is never blocked by the producer.
+	producer.confirmations = producer.channel.NotifyPublish(make(chan amqp.Confirmation, publishWindowSize))
+	producer.window.ResetDeliveryTags()
+	return nil
 }
 
-// CloseChannelAndConnection closes the channel and connection received in params.
+// CloseChannelAndConnection waits for the confirmations of the published messages,
+// and closes the channel and connection received in params.
+// Producers created by NewChannelProducer only close their channel.
 func (producer *AmqpProducer) CloseChannelAndConnection() {
+	producer.mutex.Lock()
+	defer producer.mutex.Unlock()
+
+	err := producer.waitForConfirmations()
+	if err != nil {
+		log.Printf("  [RABBITMQ PRODUCER] Closing with %d unconfirmed messages: %s", producer.window.Len(), err)
+	}
+
+	if producer.sharedConnection {
+		producer.channel.Close()
+		log.Println("  [RABBITMQ PRODUCER] Closed channel")
+		return
+	}
+
 	producer.connection.Close()
 	log.Println("  [RABBITMQ PRODUCER] Closed connection")
 	producer.channel.Close()
 	log.Println("  [RABBITMQ PRODUCER] Closed channel")
 }
 
-// PublishStringMessage sends a string message to the message queue.
-func (producer *AmqpProducer) PublishStringMessage(indexName string) {
-	bytes := []byte(indexName)
-	producer.publish(bytes)
+// PublishRunControl sends a run control message to the message queue.
+// It returns when the broker has confirmed the message, and every message published before it.
+func (producer *AmqpProducer) PublishRunControl(control models.RunControl) error {
+	envelope := models.NewRunControlEnvelope(producer.producerID, control)
+
+	producer.mutex.Lock()
+	defer producer.mutex.Unlock()
+
+	err := producer.publish(envelope.Serialize())
+	if err != nil {
+		return err
+	}
+
+	return producer.waitForConfirmations()
 }
 
 // PublishEntry sends the parsed log lines to the message queue.
-func (producer *AmqpProducer) PublishEntry(line models.ParsedLogEntry) {
-	producer.publish(line.Serialize())
+func (producer *AmqpProducer) PublishEntry(line models.ParsedLogEntry) error {
+	envelope := models.NewEnvelope(producer.producerID, line.RunID, line.Serialize())
+
+	producer.mutex.Lock()
+	defer producer.mutex.Unlock()
+
+	return producer.publish(envelope.Serialize())
+}
+
+// publish publishes a message, and waits for the confirmations if the window is full.
+func (producer *AmqpProducer) publish(data []byte) error {
+	producer.window.Add(data)
+	err := producer.publishOnChannel(data)
+	if err != nil {
+		err = producer.reconnectAndRepublish(err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if producer.window.IsFull() {
+		return producer.waitForConfirmations()
+	}
+
+	return nil
+}
+
+// waitForConfirmations waits until the broker confirms every message of the window,
+// the messages nacked by the broker are published again.
+func (producer *AmqpProducer) waitForConfirmations() error {
+	for producer.window.Len() > 0 {
+		err := producer.collectConfirmations()
+		if err != nil {
+			err = producer.reconnectAndRepublish(err)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		nackedMessages := producer.window.TakeUnconfirmed()
+		if len(nackedMessages) > 0 {
+			log.Printf("  [RABBITMQ PRODUCER] Publishing %d nacked messages again", len(nackedMessages))
+		}
+
+		err = producer.republish(nackedMessages)
+		if err != nil {
+			err = producer.reconnectAndRepublish(err)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectConfirmations reads the confirmations of the broker until every message of the window is settled.
+func (producer *AmqpProducer) collectConfirmations() error {
+	timeout := time.After(confirmTimeout)
+	for !producer.window.IsSettled() {
+		select {
+		case confirmation, ok := <-producer.confirmations:
+			if !ok {
+				return errChannelClosed
+			}
+			producer.window.Confirm(confirmation)
+		case <-timeout:
+			return errConfirmTimeout
+		}
+	}
+
+	return nil
+}
+
+// reconnectAndRepublish opens a new channel, or a new connection if the connection is closed,
+// and publishes the unconfirmed messages of the window again.
+// Returns an error after maxReconnectAttempts, the unconfirmed messages are kept in the window.
+func (producer *AmqpProducer) reconnectAndRepublish(cause error) error {
+	log.Printf("  [RABBITMQ PRODUCER] Publishing failed, reconnecting: %s", cause)
+
+	delay := initialReconnectDelay
+	for attempt := 1; ; attempt++ {
+		unconfirmedMessages := producer.window.TakeUnconfirmed()
+
+		err := producer.reconnect()
+		if err != nil {
+			// Keep the messages in the window for the next attempt.
+			for _, message := range unconfirmedMessages {
+				producer.window.Add(message)
+			}
+		} else {
+			err = producer.republish(unconfirmedMessages)
+			if err == nil {
+				log.Printf("  [RABBITMQ PRODUCER] Reconnected, published %d unconfirmed messages again",
+					len(unconfirmedMessages))
+				return nil
+			}
+		}
+
+		if attempt == maxReconnectAttempts {
+			return fmt.Errorf("failed to reconnect to RabbitMQ after %d attempts: %w", attempt, err)
+		}
+
+		log.Printf("  [RABBITMQ PRODUCER] Reconnect attempt %d failed: %s", attempt, err)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// reconnect replaces the channel of the producer, and the connection too if it is closed.
+// A producer with a shared connection opens its own connection, if the shared connection is closed.
+func (producer *AmqpProducer) reconnect() error {
+	if producer.channel != nil {
+		producer.channel.Close()
+	}
+
+	if producer.connection != nil && !producer.connection.IsClosed() {
+		return producer.openChannel()
+	}
+
+	if producer.sharedConnection {
+		log.Println("  [RABBITMQ PRODUCER] Shared connection closed, opening a new connection")
+		producer.sharedConnection = false
+	}
+
+	return producer.connect()
+}
+
+// republish adds the messages to the window, and publishes them on the current channel.
+// The messages are added to the window before publishing, so none of them are lost if publishing fails.
+func (producer *AmqpProducer) republish(messages [][]byte) error {
+	for _, message := range messages {
+		producer.window.Add(message)
+	}
+
+	for _, message := range messages {
+		err := producer.publishOnChannel(message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (producer *AmqpProducer) publish(data []byte) {
+func (producer *AmqpProducer) publishOnChannel(data []byte) error {
 	body := data
 
-	err := producer.channel.Publish(
+	return producer.channel.Publish(
 		producer.exchangeName, // exchange
 		producer.routingKey,   // routing key
 		false,                 // mandatory
@@ -79,5 +345,4 @@ func (producer *AmqpProducer) publish(data []byte) {
 			ContentType:  "application/json",
 			Body:         body,
 		})
-	utils.FailOnError(err, "Failed to publish a message")
 }
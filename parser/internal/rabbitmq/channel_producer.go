@@ -0,0 +1,80 @@
+package rabbitmq
+
+import (
+	"sync/atomic"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/streadway/amqp"
+)
+
+// ChannelProducer implements the MessageProducer interface, it sends the messages as deliveries
+// to an in-memory channel instead of RabbitMQ. The messages are wrapped in the same envelopes
+// as the messages of the AmqpProducer, so the consumers handle them the same way.
+type ChannelProducer struct {
+	deliveries  chan<- amqp.Delivery
+	deliveryTag uint64
+	producerID  string
+}
+
+// NewChannelProducer creates a new ChannelProducer that sends the deliveries to the given channel.
+// The channel is not closed by the producer, it is shared by the runs of the parser.
+func NewChannelProducer(deliveries chan<- amqp.Delivery) *ChannelProducer {
+	producer := ChannelProducer{deliveries: deliveries, producerID: models.NewProducerID("parser")}
+	return &producer
+}
+
+// OpenChannelAndConnection does nothing, the channel is ready to use when the producer is created.
+func (producer *ChannelProducer) OpenChannelAndConnection() error {
+	return nil
+}
+
+// CloseChannelAndConnection does nothing, the channel stays open for the next run.
+func (producer *ChannelProducer) CloseChannelAndConnection() {
+	// NOOP
+}
+
+// PublishRunControl sends a run control message to the channel.
+func (producer *ChannelProducer) PublishRunControl(control models.RunControl) error {
+	envelope := models.NewRunControlEnvelope(producer.producerID, control)
+	producer.send(envelope.Serialize())
+	return nil
+}
+
+// PublishEntry sends the parsed log lines to the channel.
+func (producer *ChannelProducer) PublishEntry(line models.ParsedLogEntry) error {
+	envelope := models.NewEnvelope(producer.producerID, line.RunID, line.Serialize())
+	producer.send(envelope.Serialize())
+	return nil
+}
+
+// send blocks until the consumer has room for the message, so a slow consumer slows down the parser.
+func (producer *ChannelProducer) send(body []byte) {
+	deliveryTag := atomic.AddUint64(&producer.deliveryTag, 1)
+	producer.deliveries <- NewChannelDelivery(body, deliveryTag)
+}
+
+// NewChannelDelivery creates a delivery of an in-memory channel, that can be acknowledged like an AMQP delivery.
+func NewChannelDelivery(body []byte, deliveryTag uint64) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: channelAcknowledger{},
+		DeliveryTag:  deliveryTag,
+		ContentType:  "application/json",
+		Body:         body,
+	}
+}
+
+// channelAcknowledger implements the Acknowledger interface of amqp for the in-memory deliveries,
+// there is nothing to acknowledge, because the deliveries are not kept after they are received.
+type channelAcknowledger struct{}
+
+func (channelAcknowledger) Ack(tag uint64, multiple bool) error {
+	return nil
+}
+
+func (channelAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	return nil
+}
+
+func (channelAcknowledger) Reject(tag uint64, requeue bool) error {
+	return nil
+}
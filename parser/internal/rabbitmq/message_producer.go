@@ -3,9 +3,18 @@ package rabbitmq
 import "github.com/kozgot/go-log-processing/parser/pkg/models"
 
 // MessageProducer encapsulates methods used to communicate with rabbitMQ server.
+// The publish methods return an error if the message could not be delivered, eg.: the broker is unreachable.
 type MessageProducer interface {
-	PublishStringMessage(indexName string)
-	PublishEntry(line models.ParsedLogEntry)
-	OpenChannelAndConnection()
+	PublishRunControl(control models.RunControl) error
+	PublishEntry(line models.ParsedLogEntry) error
+	OpenChannelAndConnection() error
 	CloseChannelAndConnection()
 }
+
+// ChannelProducerFactory is implemented by message producers that can create additional producers
+// publishing on their own channel, so that concurrent workers do not have to share a single channel.
+type ChannelProducerFactory interface {
+	// NewChannelProducer creates a producer with a newly opened channel on the existing connection.
+	// Closing the returned producer only closes its channel.
+	NewChannelProducer() (MessageProducer, error)
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/streadway/amqp"
+)
+
+// AmqpPublisher publishes the reports to a routing key of the processed data exchange,
+// the elasticuploader service uploads them to a dedicated index.
+type AmqpPublisher struct {
+	routingKey   string
+	exchangeName string
+	rabbitMqURL  string
+}
+
+// NewAmqpPublisher creates a new AmqpPublisher that publishes to the given exchange with the given routing key.
+func NewAmqpPublisher(routingKey string, exchangeName string, rabbitMqURL string) *AmqpPublisher {
+	publisher := AmqpPublisher{routingKey: routingKey, exchangeName: exchangeName, rabbitMqURL: rabbitMqURL}
+	return &publisher
+}
+
+// Publish publishes the report. A connection is only opened for the time of publishing,
+// because a report is published once at the end of a run.
+func (publisher *AmqpPublisher) Publish(report Report) error {
+	connection, err := amqp.Dial(publisher.rabbitMqURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	defer connection.Close()
+
+	channel, err := connection.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open a channel: %w", err)
+	}
+	defer channel.Close()
+
+	err = channel.ExchangeDeclare(
+		publisher.exchangeName, // name
+		"direct",               // type
+		true,                   // durable
+		false,                  // auto-deleted
+		false,                  // internal
+		false,                  // no-wait
+		nil,                    // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare an exchange: %w", err)
+	}
+
+	err = channel.Publish(
+		publisher.exchangeName, // exchange
+		publisher.routingKey,   // routing key
+		false,                  // mandatory
+		false,                  // immediate
+		amqp.Publishing{
+			DeliveryMode: amqp.Persistent,
+			ContentType:  "application/json",
+			Body:         report.Serialize(),
+		})
+	if err != nil {
+		return fmt.Errorf("failed to publish the parse report: %w", err)
+	}
+
+	log.Println("  [PARSER] Published parse report to routing key: " + publisher.routingKey)
+	return nil
+}
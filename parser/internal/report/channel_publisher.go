@@ -0,0 +1,27 @@
+package report
+
+import (
+	"log"
+
+	"github.com/kozgot/go-log-processing/parser/internal/rabbitmq"
+	"github.com/streadway/amqp"
+)
+
+// ChannelPublisher sends the reports as deliveries to an in-memory channel instead of RabbitMQ,
+// it is used in the pipeline mode, where the uploader runs in the same process.
+type ChannelPublisher struct {
+	deliveries chan<- amqp.Delivery
+}
+
+// NewChannelPublisher creates a new ChannelPublisher that sends the reports to the given channel.
+func NewChannelPublisher(deliveries chan<- amqp.Delivery) *ChannelPublisher {
+	publisher := ChannelPublisher{deliveries: deliveries}
+	return &publisher
+}
+
+// Publish sends the report to the channel.
+func (publisher *ChannelPublisher) Publish(report Report) error {
+	publisher.deliveries <- rabbitmq.NewChannelDelivery(report.Serialize(), 0)
+	log.Println("  [PARSER] Sent parse report to the in-process uploader")
+	return nil
+}
@@ -0,0 +1,6 @@
+package report
+
+// Publisher interface describes the methods needed to publish the report of a parser run.
+type Publisher interface {
+	Publish(report Report) error
+}
@@ -0,0 +1,137 @@
+package report
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+// Statistics contains the number of read lines and parsed entries of one or more log files.
+type Statistics struct {
+	LinesRead        int
+	LinesFiltered    int // lines with an irrelevant log level, eg.: VERBOSE
+	LinesQuarantined int
+	LinesMalformed   int // quarantined lines with a field that could not be parsed, eg.: a number or a date
+	EntriesParsed    int
+
+	// DuplicatesSuppressed is the number of entries that were not published,
+	// because they had already been published from another log file of the same DC.
+	DuplicatesSuppressed int
+
+	// EntriesByLevel contains the number of parsed entries by log level.
+	EntriesByLevel map[string]int
+
+	// InfoEntries contains the number of parsed INFO entries by InfoEntryType.
+	InfoEntries map[string]int
+
+	// DCMessages contains the number of parsed dc messages by DCMessageType.
+	DCMessages map[string]int
+
+	// UnknownInfoEntries is the number of INFO entries that fell back to UnknownInfoType.
+	UnknownInfoEntries int
+
+	// UnknownDCMessages is the number of dc messages that fell back to UnknownDCMessage.
+	UnknownDCMessages int
+}
+
+// FileReport contains the statistics of a single log file.
+type FileReport struct {
+	FileName string
+	Statistics
+}
+
+// Report contains the statistics of a parser run, in total and by log file.
+type Report struct {
+	RunID      string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Totals     Statistics
+	Files      []FileReport
+}
+
+// NewStatistics creates a new empty Statistics.
+func NewStatistics() Statistics {
+	return Statistics{
+		EntriesByLevel: make(map[string]int),
+		InfoEntries:    make(map[string]int),
+		DCMessages:     make(map[string]int),
+	}
+}
+
+// AddLineRead increments the number of read lines.
+func (statistics *Statistics) AddLineRead() {
+	statistics.LinesRead++
+}
+
+// AddRejectedLine records a line that has been rejected by the parser for the given reason.
+func (statistics *Statistics) AddRejectedLine(reason models.RejectReason) {
+	switch {
+	case reason == models.FilteredLogLevel:
+		statistics.LinesFiltered++
+	case models.IsQuarantined(reason):
+		statistics.LinesQuarantined++
+		if models.IsMalformed(reason) {
+			statistics.LinesMalformed++
+		}
+	}
+}
+
+// AddDuplicate increments the number of suppressed duplicate entries.
+func (statistics *Statistics) AddDuplicate() {
+	statistics.DuplicatesSuppressed++
+}
+
+// AddEntry records a parsed entry by its log level and type.
+func (statistics *Statistics) AddEntry(entry models.ParsedLogEntry) {
+	statistics.EntriesParsed++
+	statistics.EntriesByLevel[entry.Level]++
+
+	if entry.InfoParams == nil {
+		return
+	}
+
+	statistics.InfoEntries[models.InfoEntryTypeToString(entry.InfoParams.EntryType)]++
+	if entry.InfoParams.EntryType == models.UnknownInfoType {
+		statistics.UnknownInfoEntries++
+	}
+
+	if entry.InfoParams.DCMessage != nil {
+		messageType := entry.InfoParams.DCMessage.MessageType
+		statistics.DCMessages[models.DCMessageTypeToString(messageType)]++
+		if messageType == models.UnknownDCMessage {
+			statistics.UnknownDCMessages++
+		}
+	}
+}
+
+// Add adds the counts of other to the statistics.
+func (statistics *Statistics) Add(other Statistics) {
+	statistics.LinesRead += other.LinesRead
+	statistics.LinesFiltered += other.LinesFiltered
+	statistics.LinesQuarantined += other.LinesQuarantined
+	statistics.LinesMalformed += other.LinesMalformed
+	statistics.EntriesParsed += other.EntriesParsed
+	statistics.DuplicatesSuppressed += other.DuplicatesSuppressed
+	statistics.UnknownInfoEntries += other.UnknownInfoEntries
+	statistics.UnknownDCMessages += other.UnknownDCMessages
+
+	addCounts(statistics.EntriesByLevel, other.EntriesByLevel)
+	addCounts(statistics.InfoEntries, other.InfoEntries)
+	addCounts(statistics.DCMessages, other.DCMessages)
+}
+
+func addCounts(counts map[string]int, other map[string]int) {
+	for key, count := range other {
+		counts[key] += count
+	}
+}
+
+// Serialize serializes the report to JSON format.
+func (report Report) Serialize() []byte {
+	bytes, err := json.Marshal(report)
+	utils.FailOnError(err, "Can't serialize parse report")
+
+	return bytes
+}
@@ -6,42 +6,49 @@ import (
 	"time"
 
 	"github.com/kozgot/go-log-processing/parser/internal/formats"
-	"github.com/kozgot/go-log-processing/parser/internal/utils"
 	"github.com/kozgot/go-log-processing/parser/pkg/models"
 )
 
-// ParseTimestamp returns a date parsed from the input (a line of the currently processed log file).
-func ParseTimestamp(line models.EntryWithLogLevel) *models.EntryWithLevelAndTimestamp {
-	dateRegex, _ := regexp.Compile(formats.DateFormatRegex)
-	dateRegexshort, _ := regexp.Compile(formats.DateFormatRegexShort)
+// The regular expressions are compiled once, they are used for every line of the log files.
+var (
+	dateRegex            = regexp.MustCompile(formats.DateFormatRegex)
+	dateRegexshort       = regexp.MustCompile(formats.DateFormatRegexShort)
+	dateSurroundingRegex = regexp.MustCompile(formats.DateSurroundingRegex)
+)
 
+// ParseTimestamp returns a date parsed from the input (a line of the currently processed log file).
+// Returns nil if the line has no timestamp, and an error if its timestamp is not a valid date.
+func ParseTimestamp(line models.EntryWithLogLevel) (*models.EntryWithLevelAndTimestamp, error) {
 	dateString := dateRegex.FindString(line.Rest)
 	if dateString != "" {
 		date, err := time.ParseInLocation(formats.DateLayoutString, dateString, time.UTC)
-		utils.FailOnError(err, "Could not parse long date format")
+		if err != nil {
+			return nil, err
+		}
 
 		restOfLine := removeParsedParts(line.Rest, dateString)
-		return &models.EntryWithLevelAndTimestamp{Timestamp: date, Rest: restOfLine, Level: line.Level}
+		return &models.EntryWithLevelAndTimestamp{Timestamp: date, Rest: restOfLine, Level: line.Level}, nil
 	}
 
 	dateString = dateRegexshort.FindString(line.Rest)
 	if dateString != "" {
 		date, err := time.ParseInLocation(formats.DateLayoutStringShort, dateString, time.UTC)
-		utils.FailOnError(err, "Could not parse short date format")
+		if err != nil {
+			return nil, err
+		}
 
 		restOfLine := removeParsedParts(line.Rest, dateString)
-		return &models.EntryWithLevelAndTimestamp{Timestamp: date, Rest: restOfLine, Level: line.Level}
+		return &models.EntryWithLevelAndTimestamp{Timestamp: date, Rest: restOfLine, Level: line.Level}, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
 func removeParsedParts(line string, parsedPart string) (rest string) {
-	surroundingRegex, _ := regexp.Compile(formats.DateSurroundingRegex)
 	restOfLine := strings.Replace(line, parsedPart, "", 1)
 
 	// remove all tailing leftover square brackets, whitespaces and colons if present
-	sourroundingString := surroundingRegex.FindString(restOfLine)
+	sourroundingString := dateSurroundingRegex.FindString(restOfLine)
 	if sourroundingString != "" {
 		restOfLine = strings.TrimLeft(restOfLine, sourroundingString)
 	}
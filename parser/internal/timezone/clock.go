@@ -0,0 +1,98 @@
+package timezone
+
+import "time"
+
+// Clock converts the local timestamps of the entries of a log file to UTC.
+// The timestamps in the hour repeated at the end of daylight saving time are ambiguous,
+// they are resolved using the order of the entries: the earliest time that is not before
+// the previous entry is selected.
+type Clock struct {
+	location *time.Location
+	previous time.Time
+}
+
+// NewClock creates a new Clock for the log files written in the given timezone.
+func NewClock(location *time.Location) *Clock {
+	return &Clock{location: location}
+}
+
+// Location returns the timezone of the clock, or UTC if the clock is nil.
+func (clock *Clock) Location() *time.Location {
+	if clock == nil || clock.location == nil {
+		return time.UTC
+	}
+
+	return clock.location
+}
+
+// SetLocation changes the timezone of the following timestamps.
+func (clock *Clock) SetLocation(location *time.Location) {
+	clock.location = location
+}
+
+// ToUTC converts a timestamp of an entry from local time to UTC.
+// The local time is given by the wall clock of the timestamp, its location is ignored.
+// If the clock is nil, the timestamp is returned unchanged.
+func (clock *Clock) ToUTC(local time.Time) time.Time {
+	if clock == nil {
+		return local
+	}
+
+	candidates := candidateTimes(local, clock.Location())
+	result := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if result.Before(clock.previous) {
+			result = candidate
+		}
+	}
+
+	clock.previous = result
+	return result
+}
+
+// ToUTC converts a local time in the given timezone to UTC.
+// The local time is given by the wall clock of the time, its location is ignored.
+// Ambiguous local times are resolved to the earlier time.
+func ToUTC(local time.Time, location *time.Location) time.Time {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return candidateTimes(local, location)[0]
+}
+
+// candidateTimes returns the UTC times with the same wall clock in the location in increasing order.
+// There are two of them in the hour repeated at the end of daylight saving time, and one otherwise.
+// Local times skipped at the start of daylight saving time are moved forward, like time.Date does.
+func candidateTimes(local time.Time, location *time.Location) []time.Time {
+	year, month, day := local.Date()
+	hour, minute, second := local.Clock()
+	converted := time.Date(year, month, day, hour, minute, second, local.Nanosecond(), location)
+
+	// The offsets used a day before and after the time are the offsets that may apply to it.
+	wallClock := time.Date(year, month, day, hour, minute, second, local.Nanosecond(), time.UTC)
+	candidates := []time.Time{}
+	for _, neighbour := range []time.Time{converted.AddDate(0, 0, -1), converted.AddDate(0, 0, 1)} {
+		_, offset := neighbour.In(location).Zone()
+		candidate := wallClock.Add(-time.Duration(offset) * time.Second)
+		if candidate.In(location).Format(wallClockLayout) != wallClock.Format(wallClockLayout) {
+			continue
+		}
+
+		if len(candidates) == 0 || !candidates[0].Equal(candidate) {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return []time.Time{converted.UTC()}
+	}
+
+	if len(candidates) == 2 && candidates[1].Before(candidates[0]) {
+		candidates[0], candidates[1] = candidates[1], candidates[0]
+	}
+
+	return candidates
+}
+
+const wallClockLayout = "2006-01-02 15:04:05.999999999"
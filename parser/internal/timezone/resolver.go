@@ -0,0 +1,129 @@
+package timezone
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	// The timezone database is embedded, so the timezones can be loaded in containers without one.
+	_ "time/tzdata"
+)
+
+// FileLocation is the timezone of the DC writing the log files matching the pattern.
+type FileLocation struct {
+	// Pattern selects the log files of the DC, it is matched against the name and the base name of the file,
+	// using the syntax of filepath.Match.
+	Pattern string
+
+	Location *time.Location
+}
+
+// Resolver resolves the timezone of the DCs writing the log files.
+// The timezone of a file is the location of the first matching file pattern,
+// or the timezone seen in the settings entries of the file, or the default location.
+type Resolver struct {
+	defaultLocation *time.Location
+	fileLocations   []FileLocation
+
+	mutex sync.Mutex
+
+	// settingsLocations contains the timezones seen in the settings entries, by file name.
+	settingsLocations map[string]*time.Location
+}
+
+// NewResolver creates a new Resolver. If the default location is nil, UTC is used.
+func NewResolver(defaultLocation *time.Location, fileLocations []FileLocation) *Resolver {
+	if defaultLocation == nil {
+		defaultLocation = time.UTC
+	}
+
+	resolver := Resolver{
+		defaultLocation:   defaultLocation,
+		fileLocations:     fileLocations,
+		settingsLocations: make(map[string]*time.Location),
+	}
+
+	return &resolver
+}
+
+// ParseFileLocations parses a comma separated list of pattern=timezone pairs,
+// eg.: dc18/*=Indian/Antananarivo,dc19/*=Europe/Budapest.
+func ParseFileLocations(config string) ([]FileLocation, error) {
+	fileLocations := []FileLocation{}
+	for _, pair := range strings.Split(config, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("missing timezone in %q", pair)
+		}
+
+		pattern := strings.TrimSpace(parts[0])
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid file name pattern %q: %w", pattern, err)
+		}
+
+		location, err := time.LoadLocation(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+
+		fileLocations = append(fileLocations, FileLocation{Pattern: pattern, Location: location})
+	}
+
+	return fileLocations, nil
+}
+
+// Location returns the timezone of the DC writing the given log file.
+func (resolver *Resolver) Location(fileName string) *time.Location {
+	if location := resolver.configuredLocation(fileName); location != nil {
+		return location
+	}
+
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+
+	if location, ok := resolver.settingsLocations[fileName]; ok {
+		return location
+	}
+
+	return resolver.defaultLocation
+}
+
+// SettingsTimezoneSeen records the timezone of a settings entry of the given log file,
+// it is used for the rest of the file, unless the timezone of the file is configured.
+// Returns false if the timezone is unknown.
+func (resolver *Resolver) SettingsTimezoneSeen(fileName string, timezone string) bool {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Printf("  [PARSER] Unknown timezone in %s: %s", fileName, timezone)
+		return false
+	}
+
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+
+	resolver.settingsLocations[fileName] = location
+	return true
+}
+
+func (resolver *Resolver) configuredLocation(fileName string) *time.Location {
+	baseName := filepath.Base(filepath.FromSlash(fileName))
+	for _, fileLocation := range resolver.fileLocations {
+		if matched, _ := filepath.Match(fileLocation.Pattern, fileName); matched {
+			return fileLocation.Location
+		}
+
+		if matched, _ := filepath.Match(fileLocation.Pattern, baseName); matched {
+			return fileLocation.Location
+		}
+	}
+
+	return nil
+}
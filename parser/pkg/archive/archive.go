@@ -0,0 +1,20 @@
+// Package archive recognizes the archives of log files by the extension of their names.
+package archive
+
+import "strings"
+
+// extensions are the extensions of the archives, the compressed single files (.gz) are not archives.
+var extensions = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+// IsArchive checks if a file name has the extension of an archive,
+// the members of an archive are parsed as log files named <archive name>/<member name>.
+func IsArchive(fileName string) bool {
+	lowerCaseName := strings.ToLower(fileName)
+	for _, extension := range extensions {
+		if strings.HasSuffix(lowerCaseName, extension) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+// Package confirm keeps track of the messages published in confirm mode until the broker confirms them.
+package confirm
+
+import "github.com/streadway/amqp"
+
+// Window keeps the messages published on a channel in confirm mode until the broker confirms them.
+// The delivery tags of the messages are assigned in the order they are added to the window,
+// the same way as the broker numbers the publishings of the channel.
+type Window struct {
+	size int
+
+	// firstDeliveryTag is the delivery tag of the first message of the window.
+	firstDeliveryTag uint64
+	messages         [][]byte
+	acked            []bool
+	settled          []bool
+	settledCount     int
+}
+
+// NewWindow creates a new Window that holds at most size messages before waiting for confirmations.
+func NewWindow(size int) *Window {
+	window := Window{size: size, firstDeliveryTag: 1}
+	return &window
+}
+
+// Add adds a message to the window, and returns its delivery tag.
+// Messages are added before publishing them, so they are published again if the publishing fails.
+func (window *Window) Add(message []byte) uint64 {
+	window.messages = append(window.messages, message)
+	window.acked = append(window.acked, false)
+	window.settled = append(window.settled, false)
+	return window.firstDeliveryTag + uint64(len(window.messages)-1)
+}
+
+// Len returns the number of messages in the window.
+func (window *Window) Len() int {
+	return len(window.messages)
+}
+
+// IsFull checks if the window holds enough messages to wait for their confirmations.
+func (window *Window) IsFull() bool {
+	return len(window.messages) >= window.size
+}
+
+// Confirm records the ack or nack of the broker, confirmations of messages outside the window are ignored.
+func (window *Window) Confirm(confirmation amqp.Confirmation) {
+	if confirmation.DeliveryTag < window.firstDeliveryTag {
+		return
+	}
+
+	index := confirmation.DeliveryTag - window.firstDeliveryTag
+	if index >= uint64(len(window.messages)) || window.settled[index] {
+		return
+	}
+
+	window.acked[index] = confirmation.Ack
+	window.settled[index] = true
+	window.settledCount++
+}
+
+// IsSettled checks if the broker acked or nacked every message of the window.
+func (window *Window) IsSettled() bool {
+	return window.settledCount >= len(window.messages)
+}
+
+// TakeUnconfirmed empties the window, and returns the messages that were not acked by the broker in publishing order.
+// The delivery tags of the messages added later continue after the removed messages.
+func (window *Window) TakeUnconfirmed() [][]byte {
+	unconfirmed := [][]byte{}
+	for i, message := range window.messages {
+		if !window.acked[i] {
+			unconfirmed = append(unconfirmed, message)
+		}
+	}
+
+	window.firstDeliveryTag += uint64(len(window.messages))
+	window.messages = nil
+	window.acked = nil
+	window.settled = nil
+	window.settledCount = 0
+	return unconfirmed
+}
+
+// ResetDeliveryTags restarts the delivery tags from 1, it is called when a new channel is opened.
+// Only an empty window can be reset.
+func (window *Window) ResetDeliveryTags() {
+	window.firstDeliveryTag = 1
+}
@@ -22,6 +22,7 @@ const (
 	Statistics                            // --[statistics]-->(SVI)
 	ReadIndexLowProfiles                  // --[read index low profiles]-->(SMC)
 	ReadIndexProfiles                     // <--[read index profiles]--(SMC)
+	LastIndex                             // <--[last index]--(DB)
 )
 
 // ParseDCmessageTypeFromString parses the dc message type from a string representation.
@@ -75,7 +76,54 @@ func ParseDCmessageTypeFromString(messageTypeString string) DCMessageType {
 	case "consumption":
 		return Consumption
 
+	case "last index":
+		return LastIndex
+
 	default:
 		return UnknownDCMessage
 	}
 }
+
+// DCMessageTypeToString returns the name of a dc message type.
+func DCMessageTypeToString(messageType DCMessageType) string {
+	switch messageType {
+	case UnknownDCMessage:
+		return "UnknownDCMessage"
+	case IndexReceived:
+		return "IndexReceived"
+	case Consumption:
+		return "Consumption"
+	case MessageSentToSVI:
+		return "MessageSentToSVI"
+	case PodConfig:
+		return "PodConfig"
+	case SmcAddress:
+		return "SmcAddress"
+	case SmcConfig:
+		return "SmcConfig"
+	case ServiceLevel:
+		return "ServiceLevel"
+	case Settings:
+		return "Settings"
+	case DLMSLogs:
+		return "DLMSLogs"
+	case NewSmc:
+		return "NewSmc"
+	case IndexLowProfileGeneric:
+		return "IndexLowProfileGeneric"
+	case IndexHighProfileGeneric:
+		return "IndexHighProfileGeneric"
+	case Connect:
+		return "Connect"
+	case Statistics:
+		return "Statistics"
+	case ReadIndexLowProfiles:
+		return "ReadIndexLowProfiles"
+	case ReadIndexProfiles:
+		return "ReadIndexProfiles"
+	case LastIndex:
+		return "LastIndex"
+	default:
+		return "None"
+	}
+}
@@ -7,4 +7,8 @@ type EntryWithLevelAndTimestamp struct {
 	Timestamp time.Time
 	Level     string
 	Rest      string
+
+	// Location is the timezone of the DC, the dates in the rest of the line are local times of this timezone.
+	// If it is nil, the dates are in UTC.
+	Location *time.Location
 }
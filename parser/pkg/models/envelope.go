@@ -0,0 +1,177 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+)
+
+// LegacySchemaVersion is the schema version of the messages published before the envelope was introduced:
+// a bare ParsedLogEntry document, or a string message, eg.: END.
+const LegacySchemaVersion = 0
+
+// StringMessageSchemaVersion is the schema version of the envelopes published before the run control messages
+// were introduced: the payload is a ParsedLogEntry document, or a JSON string signalling the end of a run, eg.: END.
+const StringMessageSchemaVersion = 1
+
+// CurrentSchemaVersion is the schema version of the envelopes published by the parser.
+const CurrentSchemaVersion = 2
+
+// ErrUnsupportedSchemaVersion is returned for envelopes of a newer schema version than the consumer knows.
+var ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+
+var (
+	errMissingPayload = errors.New("envelope has no payload")
+	errNotRunControl  = errors.New("envelope does not carry a run control message")
+)
+
+// PayloadType represents the type of the payload of an envelope.
+type PayloadType int64
+
+const (
+	// EntryPayload is the default value of PayloadType, the payload is a ParsedLogEntry document.
+	EntryPayload      PayloadType = iota
+	RunControlPayload             // the payload is a RunControl document
+)
+
+// PayloadTypeToString returns the name of a payload type.
+func PayloadTypeToString(payloadType PayloadType) string {
+	switch payloadType {
+	case EntryPayload:
+		return "EntryPayload"
+	case RunControlPayload:
+		return "RunControlPayload"
+	default:
+		return "None"
+	}
+}
+
+// Envelope wraps every message published to the log entries exchange.
+// The type of the payload is given by PayloadType, the payload of a legacy string message is a JSON string.
+// The postprocessor wraps its data units in an envelope with the same layout.
+type Envelope struct {
+	SchemaVersion int
+
+	// ProducerID identifies the service instance that published the message.
+	ProducerID string
+
+	// RunID identifies the parser run the message belongs to, it is empty if the producer does not know the run.
+	RunID string `json:",omitempty"`
+
+	// Timestamp is the time the message was published.
+	Timestamp time.Time
+
+	PayloadType PayloadType
+	Payload     json.RawMessage
+}
+
+// NewEnvelope creates an envelope of the current schema version for a ParsedLogEntry document.
+func NewEnvelope(producerID string, runID string, payload []byte) Envelope {
+	return Envelope{
+		SchemaVersion: CurrentSchemaVersion,
+		ProducerID:    producerID,
+		RunID:         runID,
+		Timestamp:     time.Now().UTC(),
+		Payload:       payload,
+	}
+}
+
+// NewRunControlEnvelope creates an envelope of the current schema version for a run control message.
+func NewRunControlEnvelope(producerID string, control RunControl) Envelope {
+	envelope := NewEnvelope(producerID, control.RunID, control.Serialize())
+	envelope.PayloadType = RunControlPayload
+	return envelope
+}
+
+// Serialize serializes an envelope.
+func (e *Envelope) Serialize() []byte {
+	bytes, err := json.Marshal(e)
+	utils.FailOnError(err, "Can't serialize envelope")
+	return bytes
+}
+
+// StringMessage returns the string message carried by a legacy envelope,
+// the second return value is false if the payload is not a string.
+func (e *Envelope) StringMessage() (string, bool) {
+	message := ""
+	if err := json.Unmarshal(e.Payload, &message); err != nil {
+		return "", false
+	}
+
+	return message, true
+}
+
+// RunControl deserializes the run control message carried by the envelope.
+func (e *Envelope) RunControl() (RunControl, error) {
+	control := RunControl{}
+	if e.PayloadType != RunControlPayload {
+		return control, fmt.Errorf("%w: %s", errNotRunControl, PayloadTypeToString(e.PayloadType))
+	}
+
+	err := json.Unmarshal(e.Payload, &control)
+	return control, err
+}
+
+// OpenEnvelope deserializes the envelope of a received message.
+// Legacy messages without an envelope and envelopes of the string message schema version
+// are upgraded to the current schema version,
+// envelopes of a newer schema version are rejected with ErrUnsupportedSchemaVersion.
+func OpenEnvelope(body []byte) (*Envelope, error) {
+	trimmedBody := bytes.TrimSpace(body)
+
+	// Legacy string messages are not JSON documents.
+	if !bytes.HasPrefix(trimmedBody, []byte("{")) {
+		payload, err := json.Marshal(string(body))
+		if err != nil {
+			return nil, err
+		}
+
+		envelope := Envelope{SchemaVersion: CurrentSchemaVersion, Payload: payload}
+		return &envelope, nil
+	}
+
+	versionedMessage := struct{ SchemaVersion *int }{}
+	if err := json.Unmarshal(trimmedBody, &versionedMessage); err != nil {
+		return nil, err
+	}
+
+	// Legacy entries are bare ParsedLogEntry documents.
+	if versionedMessage.SchemaVersion == nil || *versionedMessage.SchemaVersion == LegacySchemaVersion {
+		envelope := Envelope{SchemaVersion: CurrentSchemaVersion, Payload: trimmedBody}
+		return &envelope, nil
+	}
+
+	schemaVersion := *versionedMessage.SchemaVersion
+	if schemaVersion != CurrentSchemaVersion && schemaVersion != StringMessageSchemaVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSchemaVersion, schemaVersion)
+	}
+
+	envelope := Envelope{}
+	if err := json.Unmarshal(trimmedBody, &envelope); err != nil {
+		return nil, err
+	}
+
+	if len(envelope.Payload) == 0 {
+		return nil, errMissingPayload
+	}
+
+	// The envelopes of the string message schema version only carry entries and string messages.
+	envelope.SchemaVersion = CurrentSchemaVersion
+
+	return &envelope, nil
+}
+
+// NewProducerID creates the ID of a service instance from the name of the service and the host name.
+func NewProducerID(serviceName string) string {
+	hostName, err := os.Hostname()
+	if err != nil {
+		return serviceName
+	}
+
+	return serviceName + "@" + hostName
+}
@@ -15,4 +15,38 @@ const (
 	ConnectionReleased                // Successfully Released DLMS connection
 	InitDLMSConnection                // Initialize DLMS connection
 	InternalDiagnostics               // SMC internal diagnostics
+	SmcStateChange                    // VERBOSE SMC[dc18-smc3] changing state, new state[3038]
+	TaskLaunch                        // VERBOSE Launch Task name[update_connection_task] type[connect]...
 )
+
+// InfoEntryTypeToString returns the name of an info entry type.
+func InfoEntryTypeToString(entryType InfoEntryType) string {
+	switch entryType {
+	case UnknownInfoType:
+		return "UnknownInfoType"
+	case Routing:
+		return "Routing"
+	case NetworkStatus:
+		return "NetworkStatus"
+	case SMCJoin:
+		return "SMCJoin"
+	case ConnectionAttempt:
+		return "ConnectionAttempt"
+	case SmcConfigUpdate:
+		return "SmcConfigUpdate"
+	case DCMessage:
+		return "DCMessage"
+	case ConnectionReleased:
+		return "ConnectionReleased"
+	case InitDLMSConnection:
+		return "InitDLMSConnection"
+	case InternalDiagnostics:
+		return "InternalDiagnostics"
+	case SmcStateChange:
+		return "SmcStateChange"
+	case TaskLaunch:
+		return "TaskLaunch"
+	default:
+		return "None"
+	}
+}
@@ -14,6 +14,11 @@ type ParsedLogEntry struct {
 	ErrorParams   *ErrorParams
 	WarningParams *WarningParams
 	InfoParams    *InfoParams
+	GenericParams *GenericParams
+	Source        SourceLocation
+
+	// RunID identifies the parser run the entry was parsed in, it is empty for the entries of older parsers.
+	RunID string `json:",omitempty"`
 }
 
 // Serialize serialzes a parsed log enrty.
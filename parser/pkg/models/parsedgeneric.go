@@ -0,0 +1,14 @@
+package models
+
+// GenericParams contains the fields of a log entry parsed by a declarative entry format,
+// these entries do not have a dedicated parser.
+type GenericParams struct {
+	// FormatName is the name of the entry format that matched the entry.
+	FormatName string
+
+	// EntryType is the target entry type given in the entry format.
+	EntryType string
+
+	// Fields contains the extracted fields by name, the values are strings, integers or timestamps.
+	Fields map[string]interface{}
+}
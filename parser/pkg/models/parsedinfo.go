@@ -16,6 +16,8 @@ type InfoParams struct {
 	ConnectionReleased      *ConnectionReleasedParams
 	InitConnection          *InitConnectionParams
 	InternalDiagnosticsData *InternalDiagnosticsData
+	StateChange             *SmcStateChangeParams `json:",omitempty"` // only parsed if VERBOSE state changes are enabled
+	TaskLaunch              *TaskLaunchParams     `json:",omitempty"` // only parsed if VERBOSE task launches are enabled
 }
 
 // InternalDiagnosticsData contains a parsed internal diagnostics log entry.
@@ -24,6 +26,28 @@ type InternalDiagnosticsData struct {
 	LastSuccessfulDlmsResponseDate time.Time
 }
 
+// SmcStateChangeParams contains a parsed VERBOSE SMC state change log entry.
+// SMC[dc18-smc3] changing state, new state[3038] ((null)::-1225668530).
+type SmcStateChangeParams struct {
+	SmcUID string
+	State  int64    // the state bitmask of the SMC
+	Flags  []string // the names of the flags set in the state bitmask
+}
+
+// TaskLaunchParams contains a parsed VERBOSE task launch log entry of the task scheduler.
+// Launch Task name[update_connection_task] type[connect] name[update_connection_task] smc_uid[dc18-smc3] uid[67]
+// priority[4294967293] retry[1] creation_time[Wed Jun 10 09:18:38 2020] on thread 2968499248 (...).
+type TaskLaunchParams struct {
+	Name     string
+	Type     string
+	SmcUID   string
+	UID      int // the same as the UID of the task failed warnings of the task
+	Priority int
+	Retry    int
+	Creation time.Time
+	Thread   string
+}
+
 // InitConnectionParams contains a parsed initialize dlms connection log entry.
 type InitConnectionParams struct {
 	URL string
@@ -43,8 +67,8 @@ type ConnectionReleasedParams struct {
 
 // SmcConfigUpdateParams contains a parsed SMC config update log entry.
 // Update SMC configuration in DB smc_uid[dc18-smc32] physical_address[EEBEDDFFFE6210AD]
-//    logical_address[FE80::4021:FF:FE00:000a:61616] short_address[10]
-//    last_joining_date[Wed Jun 10 09:20:14 2020]! (distribution_controller_plc_interface.cc::68).
+// logical_address[FE80::4021:FF:FE00:000a:61616] short_address[10]
+// last_joining_date[Wed Jun 10 09:20:14 2020]! (distribution_controller_plc_interface.cc::68).
 type SmcConfigUpdateParams struct {
 	PhysicalAddress string
 	LogicalAddress  string
@@ -0,0 +1,50 @@
+package models
+
+// RejectReason represents the stage of the parsing that rejected a line of a log file.
+type RejectReason int64
+
+const (
+	// NotRejected is the default value of RejectReason.
+	NotRejected        RejectReason = iota
+	EmptyLine                       // the line is empty, it is not quarantined
+	FilteredLogLevel                // the log level of the line is irrelevant eg.: VERBOSE, it is not quarantined
+	MissingLogLevel                 // the line has no log level
+	MissingTimestamp                // the line has no timestamp
+	UnrecognisedWarn                // the WARN level entry is not recognised by the warning parser
+	MalformedEntry                  // a field of the entry could not be parsed, eg.: a number or a date
+	MalformedTimestamp              // the line has a timestamp, but it could not be parsed, eg.: an invalid date
+)
+
+// RejectReasonToString returns the reason code of a reject reason.
+func RejectReasonToString(reason RejectReason) string {
+	switch reason {
+	case NotRejected:
+		return "NotRejected"
+	case EmptyLine:
+		return "EmptyLine"
+	case FilteredLogLevel:
+		return "FilteredLogLevel"
+	case MissingLogLevel:
+		return "MissingLogLevel"
+	case MissingTimestamp:
+		return "MissingTimestamp"
+	case UnrecognisedWarn:
+		return "UnrecognisedWarn"
+	case MalformedEntry:
+		return "MalformedEntry"
+	case MalformedTimestamp:
+		return "MalformedTimestamp"
+	default:
+		return "None"
+	}
+}
+
+// IsQuarantined checks if the lines rejected for the given reason should be quarantined.
+func IsQuarantined(reason RejectReason) bool {
+	return reason != NotRejected && reason != EmptyLine && reason != FilteredLogLevel
+}
+
+// IsMalformed checks if the line was rejected, because a part of it could not be parsed.
+func IsMalformed(reason RejectReason) bool {
+	return reason == MalformedEntry || reason == MalformedTimestamp
+}
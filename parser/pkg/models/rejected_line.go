@@ -0,0 +1,26 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+)
+
+// RejectedLine contains a line of a log file that could not be parsed into an entry.
+type RejectedLine struct {
+	Reason       RejectReason
+	ReasonString string
+
+	// Error is the error of parsing the line, it is only set for malformed entries and timestamps.
+	Error string `json:",omitempty"`
+
+	// Source identifies the rejected line, the raw line is always filled.
+	Source SourceLocation
+}
+
+// Serialize serializes a rejected line.
+func (r *RejectedLine) Serialize() []byte {
+	bytes, err := json.Marshal(r)
+	utils.FailOnError(err, "Can't serialize rejected line")
+	return bytes
+}
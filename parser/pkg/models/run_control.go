@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+)
+
+// RunControlType represents the type of a run control message.
+type RunControlType int64
+
+const (
+	// RunStart is sent before the first entry of a parser run.
+	RunStart RunControlType = iota
+	RunEnd                  // sent after the last entry of a parser run, it contains the number of published entries
+)
+
+// RunControlTypeToString returns the name of a run control type.
+func RunControlTypeToString(controlType RunControlType) string {
+	switch controlType {
+	case RunStart:
+		return "RunStart"
+	case RunEnd:
+		return "RunEnd"
+	default:
+		return "None"
+	}
+}
+
+// RunControl is published by the parser at the start and at the end of every run,
+// the postprocessor finishes the processing of a run when it has received every entry promised in the RunEnd message.
+type RunControl struct {
+	ControlType RunControlType
+	RunID       string
+
+	// FileNames contains the names of the log files parsed in the run.
+	FileNames []string
+
+	// EntryCounts contains the number of published entries by file name, it is only set in RunEnd messages.
+	EntryCounts map[string]int `json:",omitempty"`
+
+	// TotalEntries is the number of entries published in the run, it is only set in RunEnd messages.
+	TotalEntries int
+}
+
+// NewRunStart creates the run control message sent before the first entry of a run.
+func NewRunStart(runID string, fileNames []string) RunControl {
+	return RunControl{ControlType: RunStart, RunID: runID, FileNames: fileNames}
+}
+
+// NewRunEnd creates the run control message sent after the last entry of a run.
+func NewRunEnd(runID string, fileNames []string, entryCounts map[string]int) RunControl {
+	totalEntries := 0
+	for _, count := range entryCounts {
+		totalEntries += count
+	}
+
+	return RunControl{
+		ControlType:  RunEnd,
+		RunID:        runID,
+		FileNames:    fileNames,
+		EntryCounts:  entryCounts,
+		TotalEntries: totalEntries,
+	}
+}
+
+// Serialize serializes a run control message.
+func (r *RunControl) Serialize() []byte {
+	bytes, err := json.Marshal(r)
+	utils.FailOnError(err, "Can't serialize run control message")
+	return bytes
+}
@@ -0,0 +1,74 @@
+package models
+
+import "strconv"
+
+// SmcStateFlag represents a bit of the state bitmask logged in the VERBOSE SMC state change entries.
+type SmcStateFlag int64
+
+const (
+	// SmcJoinedFlag is the lowest bit of the state bitmask.
+	SmcJoinedFlag SmcStateFlag = 1 << iota
+	SmcAddressKnownFlag
+	SmcConfigurationReadFlag
+	SmcPodsConfiguredFlag
+	SmcConnectionRequestedFlag
+	SmcConnectedFlag
+	SmcServiceLevelSetFlag
+	SmcIndexReadFlag
+	SmcConsumptionReadFlag
+	SmcSettingsSentFlag
+	SmcTimeSynchronisedFlag
+	SmcProfileReadFlag
+)
+
+// SmcStateFlagToString returns the name of a flag of the SMC state bitmask,
+// the bits without a name are named after their position, eg.: Bit12.
+func SmcStateFlagToString(flag SmcStateFlag) string {
+	switch flag {
+	case SmcJoinedFlag:
+		return "Joined"
+	case SmcAddressKnownFlag:
+		return "AddressKnown"
+	case SmcConfigurationReadFlag:
+		return "ConfigurationRead"
+	case SmcPodsConfiguredFlag:
+		return "PodsConfigured"
+	case SmcConnectionRequestedFlag:
+		return "ConnectionRequested"
+	case SmcConnectedFlag:
+		return "Connected"
+	case SmcServiceLevelSetFlag:
+		return "ServiceLevelSet"
+	case SmcIndexReadFlag:
+		return "IndexRead"
+	case SmcConsumptionReadFlag:
+		return "ConsumptionRead"
+	case SmcSettingsSentFlag:
+		return "SettingsSent"
+	case SmcTimeSynchronisedFlag:
+		return "TimeSynchronised"
+	case SmcProfileReadFlag:
+		return "ProfileRead"
+	default:
+		for bit := 0; bit < 63; bit++ {
+			if flag == 1<<bit {
+				return "Bit" + strconv.Itoa(bit)
+			}
+		}
+
+		return "None"
+	}
+}
+
+// DecodeSmcStateFlags returns the names of the flags set in an SMC state bitmask, from the lowest bit.
+func DecodeSmcStateFlags(state int64) []string {
+	flags := []string{}
+	for bit := 0; bit < 63; bit++ {
+		flag := SmcStateFlag(1) << bit
+		if state&int64(flag) != 0 {
+			flags = append(flags, SmcStateFlagToString(flag))
+		}
+	}
+
+	return flags
+}
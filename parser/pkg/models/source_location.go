@@ -0,0 +1,37 @@
+package models
+
+import (
+	"path"
+	"strings"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/archive"
+)
+
+// SourceLocation identifies the log line a parsed entry was created from.
+type SourceLocation struct {
+	// FileName is the name of the log file, members of archives are named archive/member.
+	FileName string
+
+	// LineNumber is the number of the line in the log file, starting from 1.
+	LineNumber int64
+
+	// ByteOffset is the offset of the start of the line in the (decompressed) log file.
+	ByteOffset int64
+
+	// RawLine is a copy of the original line, it is only filled if raw lines are enabled in the parser.
+	RawLine string
+}
+
+// SourceDC returns the DC writing a log file, which is the directory of the file in the container.
+// The archives are left out of the directory of their members, so overlapping exports of a DC match.
+// The files in the root of the container belong to the same DC.
+func SourceDC(fileName string) string {
+	directories := []string{}
+	for _, directory := range strings.Split(path.Dir(fileName), "/") {
+		if directory != "." && directory != "" && !archive.IsArchive(directory) {
+			directories = append(directories, directory)
+		}
+	}
+
+	return strings.Join(directories, "/")
+}
@@ -0,0 +1,748 @@
+// Package service contains the HTTP API of the parser service, it sends the parsed entries
+// to RabbitMQ, or to an in-process postprocessor in the pipeline mode.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/checkpoint"
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/formatregistry"
+	"github.com/kozgot/go-log-processing/parser/internal/jobs"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/quarantine"
+	"github.com/kozgot/go-log-processing/parser/internal/rabbitmq"
+	"github.com/kozgot/go-log-processing/parser/internal/report"
+	"github.com/kozgot/go-log-processing/parser/internal/timezone"
+	"github.com/streadway/amqp"
+)
+
+// Service is the HTTP API of the parser service. The log files to parse, the settings of the parser
+// and the destination of the parsed entries are configured with environment variables.
+type Service struct {
+	// createProducer creates the producer of the parsed entries.
+	createProducer func() rabbitmq.MessageProducer
+
+	// createReportPublisher creates the publisher of the statistics reports, it returns nil if they are not published.
+	createReportPublisher func() report.Publisher
+
+	// inProcess is true if the entries are sent to an in-process postprocessor instead of RabbitMQ.
+	inProcess bool
+
+	// jobManager runs the jobs submitted to the job API in the background.
+	jobManager *jobs.Manager
+
+	// following contains the state of the follow mode, only one set of files can be followed at a time.
+	following struct {
+		sync.Mutex
+		stop chan struct{}
+		done chan struct{}
+	}
+
+	mux *http.ServeMux
+}
+
+// NewService creates a parser service that sends the parsed entries and the statistics reports to RabbitMQ.
+// Returns an error if the RabbitMQ settings are not configured correctly.
+func NewService() (*Service, error) {
+	createProducer, err := newRabbitMqProducerFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	createReportPublisher, err := newAmqpReportPublisherFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	return newService(createProducer, createReportPublisher, false)
+}
+
+// NewInProcessService creates a parser service that sends the parsed entries as deliveries
+// to the entries channel, in the same format as they are published to RabbitMQ.
+// The statistics reports are sent to the reports channel, they are not sent if it is nil.
+func NewInProcessService(entries chan<- amqp.Delivery, reports chan<- amqp.Delivery) (*Service, error) {
+	createProducer := func() rabbitmq.MessageProducer {
+		return rabbitmq.NewChannelProducer(entries)
+	}
+	createReportPublisher := func() report.Publisher {
+		if reports == nil {
+			return nil
+		}
+		return report.NewChannelPublisher(reports)
+	}
+
+	return newService(createProducer, createReportPublisher, true)
+}
+
+func newService(
+	createProducer func() rabbitmq.MessageProducer,
+	createReportPublisher func() report.Publisher,
+	inProcess bool,
+) (*Service, error) {
+	policy, err := createConcurrencyPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	service := Service{
+		createProducer:        createProducer,
+		createReportPublisher: createReportPublisher,
+		inProcess:             inProcess,
+		mux:                   http.NewServeMux(),
+	}
+	service.jobManager = jobs.NewManager(policy, service.runJob)
+
+	service.mux.HandleFunc("/process/", service.handler)
+	service.mux.HandleFunc("/jobs", service.submitJobHandler)
+	service.mux.HandleFunc("/jobs/", service.jobHandler)
+	service.mux.HandleFunc("/follow/start/", service.followStartHandler)
+	service.mux.HandleFunc("/follow/stop/", service.followStopHandler)
+
+	return &service, nil
+}
+
+// Handler returns the handler of the HTTP API.
+func (service *Service) Handler() http.Handler {
+	return service.mux
+}
+
+// ListenAndServe serves the HTTP API on the given address, eg.: :8080, until it fails.
+func (service *Service) ListenAndServe(address string) error {
+	log.Printf("  [PARSER] Application started, listening on %s...", address)
+	return http.ListenAndServe(address, service.mux)
+}
+
+// handler submits a job that parses every file, like a job submitted to the job API,
+// so it is run by the job manager according to the concurrency policy.
+func (service *Service) handler(w http.ResponseWriter, r *http.Request) {
+	request := jobs.Request{Reprocess: r.URL.Query().Get("reprocess") == "true"}
+	job, err := service.jobManager.Submit(request)
+	if errors.Is(err, jobs.ErrJobRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, "Could not submit job: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprintf(w, "<div>Submitted job %s, parsing log files in the background...</div>", job.ID)
+	fmt.Fprintf(w, "<a href=\"/jobs/%s\">Check the status of the job</a>", job.ID)
+}
+
+// submitJobHandler starts a new parser job, the body of the request may contain a jobs.Request in JSON format.
+func (service *Service) submitJobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	request := jobs.Request{}
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			http.Error(w, "Invalid job request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	job, err := service.jobManager.Submit(request)
+	if errors.Is(err, jobs.ErrJobRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, "Could not submit job: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJob(w, http.StatusAccepted, job)
+}
+
+// jobHandler returns the status of a job for GET requests, and cancels the job for DELETE requests.
+func (service *Service) jobHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	if id == "" {
+		service.submitJobHandler(w, r)
+		return
+	}
+
+	var job jobs.Job
+	var found bool
+	switch r.Method {
+	case http.MethodGet:
+		job, found = service.jobManager.Get(id)
+	case http.MethodDelete:
+		job, found = service.jobManager.Cancel(id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !found {
+		http.Error(w, "Job not found: "+id, http.StatusNotFound)
+		return
+	}
+
+	writeJob(w, http.StatusOK, job)
+}
+
+func writeJob(w http.ResponseWriter, statusCode int, job jobs.Job) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	err := json.NewEncoder(w).Encode(job)
+	if err != nil {
+		log.Printf("  [PARSER] Could not write job response: %s", err)
+	}
+}
+
+// runJob parses the log files selected by a job request.
+// Returns an error if the parser could not be configured, or some of the files could not be parsed.
+func (service *Service) runJob(ctx context.Context, request jobs.Request, runProgress *progress.Progress) error {
+	fileDownloader, sourceDescription, err := createFileDownloader()
+	if err != nil {
+		return err
+	}
+	log.Println(sourceDescription)
+
+	rabbitMqProducer := service.createProducer()
+	err = rabbitMqProducer.OpenChannelAndConnection()
+	if err != nil {
+		return err
+	}
+	defer rabbitMqProducer.CloseChannelAndConnection()
+
+	logParser, err := service.createLogParser(fileDownloader, rabbitMqProducer, request.Reprocess)
+	if err != nil {
+		return err
+	}
+
+	quarantineSink, err := service.openQuarantineSink(logParser)
+	if err != nil {
+		return err
+	}
+	defer closeQuarantineSink(quarantineSink)
+
+	_, err = logParser.ParseSelectedLogfiles(ctx, request.Files, runProgress)
+	return err
+}
+
+// createConcurrencyPolicy reads the policy applied to jobs submitted while another job is running.
+func createConcurrencyPolicy() (jobs.ConcurrencyPolicy, error) {
+	policyString := os.Getenv("JOB_CONCURRENCY_POLICY")
+	if policyString == "" {
+		policyString = string(jobs.QueueConcurrentJobs)
+	}
+	log.Println("Job concurrency policy: ", policyString)
+
+	policy, err := jobs.ParseConcurrencyPolicy(policyString)
+	if err != nil {
+		return "", fmt.Errorf("invalid JOB_CONCURRENCY_POLICY: %s", policyString)
+	}
+
+	return policy, nil
+}
+
+// createLogParser creates an incremental log parser if the CHECKPOINT_FILE environment variable is set,
+// otherwise every file is parsed from the beginning in every run.
+// The number of files parsed concurrently is set by the PARSER_WORKER_COUNT environment variable.
+func (service *Service) createLogParser(
+	fileDownloader filedownloader.FileDownloader,
+	rabbitMqProducer rabbitmq.MessageProducer,
+	reprocessAll bool,
+) (*logparser.LogParser, error) {
+	var logParser *logparser.LogParser
+
+	checkpointFile := os.Getenv("CHECKPOINT_FILE")
+	log.Println("Checkpoint file: ", checkpointFile)
+	if len(checkpointFile) == 0 {
+		logParser = logparser.NewLogParser(fileDownloader, rabbitMqProducer)
+	} else {
+		log.Println("Reprocess all files: ", reprocessAll)
+		checkpointStore, err := checkpoint.NewFileStore(checkpointFile)
+		if err != nil {
+			return nil, err
+		}
+		logParser, err = logparser.NewIncrementalLogParser(fileDownloader, rabbitMqProducer, checkpointStore, reprocessAll)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	workerCount := logparser.DefaultWorkerCount
+	if workerCountString := os.Getenv("PARSER_WORKER_COUNT"); workerCountString != "" {
+		var err error
+		workerCount, err = strconv.Atoi(workerCountString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PARSER_WORKER_COUNT: %s", workerCountString)
+		}
+	}
+	log.Println("Parser worker count: ", workerCount)
+	err := logParser.SetWorkerCount(workerCount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PARSER_WORKER_COUNT: %w", err)
+	}
+
+	err = service.configureLogParser(logParser)
+	if err != nil {
+		return nil, err
+	}
+
+	return logParser, nil
+}
+
+// configureLogParser sets the settings shared by the parsers of the jobs and the follow mode:
+// the formats of the entries, the timezones of the DCs, the deduplication and the publisher of the reports.
+func (service *Service) configureLogParser(logParser *logparser.LogParser) error {
+	err := configureFormats(logParser)
+	if err != nil {
+		return err
+	}
+
+	err = configureTimezones(logParser)
+	if err != nil {
+		return err
+	}
+
+	err = configureDeduplication(logParser)
+	if err != nil {
+		return err
+	}
+
+	service.configureReportPublisher(logParser)
+	return nil
+}
+
+// configureDeduplication enables suppressing the entries already published from another log file of the same DC,
+// if the DEDUPLICATION_WINDOW environment variable is set to the time the fingerprints of the entries are kept,
+// eg.: 1h.
+func configureDeduplication(logParser *logparser.LogParser) error {
+	windowString := os.Getenv("DEDUPLICATION_WINDOW")
+	if windowString == "" {
+		log.Println("Deduplication disabled")
+		return nil
+	}
+
+	window, err := time.ParseDuration(windowString)
+	if err != nil || window < 0 {
+		return fmt.Errorf("invalid DEDUPLICATION_WINDOW: %s", windowString)
+	}
+
+	log.Println("Deduplication window: ", window)
+	logParser.SetDeduplicationWindow(window)
+	return nil
+}
+
+// configureTimezones sets the timezones of the DCs writing the log files: DC_TIMEZONES is a comma separated
+// list of file name pattern=timezone pairs (eg.: dc18/*=Indian/Antananarivo), and DC_TIMEZONE is the timezone of
+// the other files. Files without a configured timezone use the timezone of their settings entries.
+func configureTimezones(logParser *logparser.LogParser) error {
+	var defaultLocation *time.Location
+	if defaultTimezone := os.Getenv("DC_TIMEZONE"); defaultTimezone != "" {
+		var err error
+		defaultLocation, err = time.LoadLocation(defaultTimezone)
+		if err != nil {
+			return fmt.Errorf("invalid DC_TIMEZONE: %s", defaultTimezone)
+		}
+	}
+	log.Println("Default DC timezone: ", defaultLocation)
+
+	fileLocations, err := timezone.ParseFileLocations(os.Getenv("DC_TIMEZONES"))
+	if err != nil {
+		return fmt.Errorf("invalid DC_TIMEZONES: %w", err)
+	}
+	log.Println("DC timezones: ", fileLocations)
+
+	logParser.SetTimezoneResolver(timezone.NewResolver(defaultLocation, fileLocations))
+	return nil
+}
+
+// configureReportPublisher sets the publisher of the statistics reports of the runs, if there is one.
+func (service *Service) configureReportPublisher(logParser *logparser.LogParser) {
+	if reportPublisher := service.createReportPublisher(); reportPublisher != nil {
+		logParser.SetReportPublisher(reportPublisher)
+	}
+}
+
+// newAmqpReportPublisherFactory reads the settings of the publisher of the statistics reports.
+// The reports are published if the PARSE_REPORT_ROUTING_KEY environment variable is set,
+// to the PROCESSED_DATA_EXCHANGE exchange, and uploaded to Elasticsearch by the elasticuploader service.
+func newAmqpReportPublisherFactory() (func() report.Publisher, error) {
+	parseReportRoutingKey := os.Getenv("PARSE_REPORT_ROUTING_KEY")
+	log.Println("Parse report routing key: ", parseReportRoutingKey)
+	if len(parseReportRoutingKey) == 0 {
+		return func() report.Publisher { return nil }, nil
+	}
+
+	processedDataExchangeName := os.Getenv("PROCESSED_DATA_EXCHANGE")
+	log.Println("Processed data exchange name: ", processedDataExchangeName)
+	if len(processedDataExchangeName) == 0 {
+		return nil, errors.New("the PROCESSED_DATA_EXCHANGE environment variable is not set")
+	}
+
+	rabbitMqURL := os.Getenv("RABBIT_URL")
+	return func() report.Publisher {
+		return report.NewAmqpPublisher(parseReportRoutingKey, processedDataExchangeName, rabbitMqURL)
+	}, nil
+}
+
+// configureFormats sets the settings of the log parser that affect the parsed entries:
+// INCLUDE_RAW_LINES=true adds a copy of the original line to the source location of the entries,
+// PARSE_VERBOSE_STATE_CHANGES=true and PARSE_VERBOSE_TASK_LAUNCHES=true enable parsing
+// the VERBOSE SMC state change and task launch entries,
+// and FORMAT_REGISTRY_FILE is a YAML or JSON file containing declarative log and entry formats.
+func configureFormats(logParser *logparser.LogParser) error {
+	includeRawLines := os.Getenv("INCLUDE_RAW_LINES") == "true"
+	log.Println("Include raw lines: ", includeRawLines)
+	logParser.SetIncludeRawLines(includeRawLines)
+
+	verboseEntries := fileparser.VerboseEntries{
+		StateChanges: os.Getenv("PARSE_VERBOSE_STATE_CHANGES") == "true",
+		TaskLaunches: os.Getenv("PARSE_VERBOSE_TASK_LAUNCHES") == "true",
+	}
+	log.Println("Parse VERBOSE state changes: ", verboseEntries.StateChanges)
+	log.Println("Parse VERBOSE task launches: ", verboseEntries.TaskLaunches)
+	logParser.SetVerboseEntries(verboseEntries)
+
+	formatRegistryFile := os.Getenv("FORMAT_REGISTRY_FILE")
+	log.Println("Format registry file: ", formatRegistryFile)
+	if len(formatRegistryFile) == 0 {
+		return nil
+	}
+
+	registry, err := formatregistry.LoadRegistry(formatRegistryFile)
+	if err != nil {
+		return err
+	}
+	logParser.SetFormatRegistry(registry)
+
+	// The log formats of the registry replace the default continuation rules.
+	rules, err := fileparser.RegistryContinuationRules(registry)
+	if err != nil {
+		return err
+	}
+
+	if rules != nil {
+		logParser.SetContinuationRules(rules)
+	}
+
+	return nil
+}
+
+// followStartHandler starts following the growing log files in the background.
+func (service *Service) followStartHandler(w http.ResponseWriter, r *http.Request) {
+	service.following.Lock()
+	defer service.following.Unlock()
+
+	if service.following.stop != nil {
+		fmt.Fprint(w, "<div>Already following log files</div>")
+		return
+	}
+
+	// Initialize file downloader.
+	fileDownloader, sourceDescription, err := createFileDownloader()
+	if err != nil {
+		http.Error(w, "Could not create file downloader: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	config, err := createFollowConfig()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Initialize rabbitMQ producer, the connection is opened after the configuration is validated,
+	// and it stays open until the follow mode is stopped.
+	rabbitMqProducer := service.createProducer()
+
+	logParser := logparser.NewLogParser(fileDownloader, rabbitMqProducer)
+	err = service.configureLogParser(logParser)
+	if err != nil {
+		http.Error(w, "Could not create log parser: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	quarantineSink, err := service.openQuarantineSink(logParser)
+	if err != nil {
+		http.Error(w, "Could not open quarantine: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = rabbitMqProducer.OpenChannelAndConnection()
+	if err != nil {
+		closeQuarantineSink(quarantineSink)
+		http.Error(w, "Could not open the producer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprintf(w, "<div>%s</div>", sourceDescription)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	service.following.stop = stop
+	service.following.done = done
+
+	go func() {
+		defer close(done)
+		defer rabbitMqProducer.CloseChannelAndConnection()
+		defer closeQuarantineSink(quarantineSink)
+		err := logParser.FollowLogfiles(config, stop)
+		if err != nil {
+			log.Printf("  [PARSER] Following log files failed: %s", err)
+		}
+	}()
+
+	fmt.Fprintf(w, "<div>Started following log files: %s</div>", strings.Join(config.FileNames, ", "))
+}
+
+// followStopHandler stops following the log files.
+func (service *Service) followStopHandler(w http.ResponseWriter, r *http.Request) {
+	service.following.Lock()
+	defer service.following.Unlock()
+
+	if service.following.stop == nil {
+		fmt.Fprint(w, "<div>Not following log files</div>")
+		return
+	}
+
+	close(service.following.stop)
+	<-service.following.done
+	service.following.stop = nil
+	service.following.done = nil
+
+	fmt.Fprint(w, "<div>Stopped following log files, allow a few seconds for the processing to finish...</div>")
+	fmt.Fprintf(w, "<a href=\"http://localhost:5601/app/home#/\">Check results in Kibana</a>")
+}
+
+// openQuarantineSink opens the sink of the lines that could not be parsed, and sets it in the log parser.
+// The rejected lines are published to the QUARANTINE_ROUTING_KEY dead-letter routing key,
+// or appended to the QUARANTINE_FILE file. Returns nil if neither is set.
+func (service *Service) openQuarantineSink(logParser *logparser.LogParser) (quarantine.Sink, error) {
+	quarantineRoutingKey := os.Getenv("QUARANTINE_ROUTING_KEY")
+	log.Println("Quarantine routing key: ", quarantineRoutingKey)
+
+	quarantineFile := os.Getenv("QUARANTINE_FILE")
+	log.Println("Quarantine file: ", quarantineFile)
+
+	var sink quarantine.Sink
+	switch {
+	case len(quarantineRoutingKey) > 0 && len(quarantineFile) > 0:
+		return nil, errors.New("only one of the QUARANTINE_ROUTING_KEY and QUARANTINE_FILE environment variables can be set")
+
+	case len(quarantineRoutingKey) > 0 && service.inProcess:
+		return nil, errors.New("the QUARANTINE_ROUTING_KEY environment variable is not supported in the pipeline mode")
+
+	case len(quarantineRoutingKey) > 0:
+		sink = quarantine.NewAmqpSink(quarantineRoutingKey, os.Getenv("LOG_ENTRIES_EXCHANGE"), os.Getenv("RABBIT_URL"))
+
+	case len(quarantineFile) > 0:
+		sink = quarantine.NewFileSink(quarantineFile)
+
+	default:
+		return nil, nil
+	}
+
+	err := sink.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	logParser.SetQuarantineSink(sink)
+	return sink, nil
+}
+
+func closeQuarantineSink(sink quarantine.Sink) {
+	if sink == nil {
+		return
+	}
+
+	err := sink.Close()
+	if err != nil {
+		log.Printf("  [PARSER] Could not close the quarantine: %s", err)
+	}
+}
+
+// newRabbitMqProducerFactory reads the settings of the rabbitMQ producers used to send the parsed log entries.
+func newRabbitMqProducerFactory() (func() rabbitmq.MessageProducer, error) {
+	rabbitMqURL := os.Getenv("RABBIT_URL")
+	log.Println("RabbitMQ URL: ", rabbitMqURL)
+
+	logEntriesExchangeName := os.Getenv("LOG_ENTRIES_EXCHANGE")
+	fmt.Println("LOG_ENTRIES_EXCHANGE:", logEntriesExchangeName)
+	if len(logEntriesExchangeName) == 0 {
+		return nil, errors.New("the LOG_ENTRIES_EXCHANGE environment variable is not set")
+	}
+
+	processEntryRoutingKey := os.Getenv("PROCESS_ENTRY_ROUTING_KEY")
+	fmt.Println("PROCESS_ENTRY_ROUTING_KEY:", processEntryRoutingKey)
+	if len(processEntryRoutingKey) == 0 {
+		return nil, errors.New("the PROCESS_ENTRY_ROUTING_KEY environment variable is not set")
+	}
+
+	return func() rabbitmq.MessageProducer {
+		return rabbitmq.NewAmqpProducer(processEntryRoutingKey, logEntriesExchangeName, rabbitMqURL)
+	}, nil
+}
+
+// createFollowConfig creates the settings of the follow mode from the environment variables.
+func createFollowConfig() (logparser.FollowConfig, error) {
+	fileNames := splitPatterns(os.Getenv("FOLLOW_FILES"))
+	if len(fileNames) == 0 {
+		fileNames = []string{"dc_main.log", "plc_manager.log"}
+	}
+	log.Println("Followed files: ", fileNames)
+
+	pollInterval := time.Second
+	if pollIntervalString := os.Getenv("FOLLOW_POLL_INTERVAL"); pollIntervalString != "" {
+		var err error
+		pollInterval, err = time.ParseDuration(pollIntervalString)
+		if err != nil || pollInterval <= 0 {
+			return logparser.FollowConfig{}, fmt.Errorf("invalid FOLLOW_POLL_INTERVAL: %s", pollIntervalString)
+		}
+	}
+	log.Println("Follow poll interval: ", pollInterval)
+
+	fromBeginning := os.Getenv("FOLLOW_FROM_BEGINNING") == "true"
+	log.Println("Follow from beginning: ", fromBeginning)
+
+	return logparser.FollowConfig{
+		FileNames:     fileNames,
+		PollInterval:  pollInterval,
+		FromBeginning: fromBeginning,
+	}, nil
+}
+
+// createFileDownloader creates the file downloader selected by the FILE_SOURCE environment variable,
+// and returns it with a short description of the source of the log files.
+// Returns an error if the source is not configured correctly.
+func createFileDownloader() (filedownloader.FileDownloader, string, error) {
+	fileSource := os.Getenv("FILE_SOURCE")
+	log.Println("File source: ", fileSource)
+
+	switch fileSource {
+	case "", "azure":
+		azureStorageAccountName := os.Getenv("AZURE_STORAGE_ACCOUNT")
+		log.Println("Azure storage account name: ", azureStorageAccountName)
+
+		azureStorageContainer := os.Getenv("AZURE_STORAGE_CONTAINER")
+		log.Println("Azure storage container: ", azureStorageContainer)
+
+		azureStorageAccessKey := os.Getenv("AZURE_STORAGE_ACCESS_KEY")
+		if len(azureStorageAccountName) == 0 || len(azureStorageAccessKey) == 0 {
+			return nil, "", errors.New(
+				"either the AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_ACCESS_KEY environment variable is not set")
+		}
+		log.Println("Azure storage access key: ", azureStorageAccessKey[0:5]+"...")
+
+		azureFileDownloader, err := filedownloader.NewAzureDownloader(
+			azureStorageAccountName,
+			azureStorageAccessKey,
+			azureStorageContainer)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return azureFileDownloader, fmt.Sprintf("Storage account: %s, container: %s",
+			azureStorageAccountName,
+			azureStorageContainer), nil
+
+	case "local":
+		localLogDirectory := os.Getenv("LOCAL_LOG_DIRECTORY")
+		log.Println("Local log directory: ", localLogDirectory)
+		if len(localLogDirectory) == 0 {
+			return nil, "", errors.New("the LOCAL_LOG_DIRECTORY environment variable is not set")
+		}
+
+		recursive := os.Getenv("LOCAL_LOG_RECURSIVE") == "true"
+		log.Println("Local log recursive listing: ", recursive)
+
+		includePatterns := splitPatterns(os.Getenv("LOCAL_LOG_INCLUDE"))
+		log.Println("Local log include patterns: ", includePatterns)
+
+		excludePatterns := splitPatterns(os.Getenv("LOCAL_LOG_EXCLUDE"))
+		log.Println("Local log exclude patterns: ", excludePatterns)
+
+		localFileDownloader, err := filedownloader.NewLocalDownloader(
+			localLogDirectory,
+			recursive,
+			includePatterns,
+			excludePatterns)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return localFileDownloader, fmt.Sprintf("Local directory: %s", localLogDirectory), nil
+
+	case "s3":
+		s3Endpoint := os.Getenv("S3_ENDPOINT")
+		log.Println("S3 endpoint: ", s3Endpoint)
+
+		s3Bucket := os.Getenv("S3_BUCKET")
+		log.Println("S3 bucket: ", s3Bucket)
+
+		s3Prefix := os.Getenv("S3_PREFIX")
+		log.Println("S3 prefix: ", s3Prefix)
+
+		s3AccessKey := os.Getenv("S3_ACCESS_KEY")
+		s3SecretKey := os.Getenv("S3_SECRET_KEY")
+		if len(s3Endpoint) == 0 || len(s3Bucket) == 0 {
+			return nil, "", errors.New("either the S3_ENDPOINT or S3_BUCKET environment variable is not set")
+		}
+		if len(s3AccessKey) == 0 || len(s3SecretKey) == 0 {
+			return nil, "", errors.New("either the S3_ACCESS_KEY or S3_SECRET_KEY environment variable is not set")
+		}
+
+		s3UseSSL := os.Getenv("S3_USE_SSL") == "true"
+		log.Println("S3 use SSL: ", s3UseSSL)
+
+		s3PathStyle := os.Getenv("S3_PATH_STYLE") != "false"
+		log.Println("S3 path-style addressing: ", s3PathStyle)
+
+		s3FileDownloader, err := filedownloader.NewS3Downloader(
+			s3Endpoint,
+			s3AccessKey,
+			s3SecretKey,
+			s3Bucket,
+			s3Prefix,
+			s3UseSSL,
+			s3PathStyle)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return s3FileDownloader, fmt.Sprintf("S3 endpoint: %s, bucket: %s", s3Endpoint, s3Bucket), nil
+
+	default:
+		return nil, "", fmt.Errorf("unknown FILE_SOURCE: %s", fileSource)
+	}
+}
+
+// splitPatterns splits a comma separated list of glob patterns or file names.
+func splitPatterns(patternList string) []string {
+	patterns := []string{}
+	for _, pattern := range strings.Split(patternList, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
@@ -0,0 +1,117 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/loglevelparser"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/timestampparser"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+// The benchmarks parse the test logs of the logparser unit tests, run them with: go test -bench . ./benchmarks/
+const (
+	dcMainLogPath     = "../logparser_unit_tests/resources/test_dc_main.log"
+	plcManagerLogPath = "../logparser_unit_tests/resources/test_plc_manager.log"
+	fileRepetitions   = 100
+)
+
+// discardingProducer drops the published entries, so the benchmarks only measure the parsing.
+type discardingProducer struct{}
+
+func (producer *discardingProducer) PublishRunControl(control models.RunControl) error { return nil }
+func (producer *discardingProducer) PublishEntry(line models.ParsedLogEntry) error     { return nil }
+func (producer *discardingProducer) OpenChannelAndConnection() error                   { return nil }
+func (producer *discardingProducer) CloseChannelAndConnection()                        {}
+
+func BenchmarkParseLineDCMain(b *testing.B) {
+	benchmarkParseLines(b, dcMainLogPath)
+}
+
+func BenchmarkParseLinePLCManager(b *testing.B) {
+	benchmarkParseLines(b, plcManagerLogPath)
+}
+
+func BenchmarkParseFileDCMain(b *testing.B) {
+	benchmarkParseFile(b, dcMainLogPath, "dc_main.log")
+}
+
+func BenchmarkParseFilePLCManager(b *testing.B) {
+	benchmarkParseFile(b, plcManagerLogPath, "plc_manager.log")
+}
+
+func BenchmarkParseLogLevel(b *testing.B) {
+	lines := readLines(b, dcMainLogPath)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		loglevelparser.ParseLogLevelAndFilter(lines[i%len(lines)])
+	}
+}
+
+func BenchmarkParseTimestamp(b *testing.B) {
+	entries := []models.EntryWithLogLevel{}
+	for _, line := range readLines(b, dcMainLogPath) {
+		if entry := loglevelparser.ParseLogLevelAndFilter(line); entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		timestampparser.ParseTimestamp(entries[i%len(entries)])
+	}
+}
+
+// benchmarkParseLines parses the lines of the log file one by one, an operation is a single line.
+func benchmarkParseLines(b *testing.B, path string) {
+	lines := readLines(b, path)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fileparser.ParseLine(lines[i%len(lines)])
+	}
+}
+
+// benchmarkParseFile parses the log file repeated fileRepetitions times with a file parser,
+// including the joining of the multi-line entries. The throughput is reported in MB/s.
+func benchmarkParseFile(b *testing.B, path string, logFileName string) {
+	contents, err := ioutil.ReadFile(path)
+	utils.FailOnError(err, "Could not read benchmark log file.")
+	contents = bytes.Repeat(contents, fileRepetitions)
+
+	fileParser := fileparser.NewFileParser(context.Background(), &discardingProducer{}, progress.NewProgress())
+
+	b.SetBytes(int64(len(contents)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := fileParser.ParseCompleteLines(bytes.NewReader(contents), logFileName, fileparser.Position{})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func readLines(b *testing.B, path string) []string {
+	contents, err := ioutil.ReadFile(path)
+	utils.FailOnError(err, "Could not read benchmark log file.")
+
+	lines := []string{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) == 0 {
+		b.Fatal("The benchmark log file is empty.")
+	}
+
+	return lines
+}
@@ -0,0 +1,64 @@
+package confirmunittests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/confirm"
+	"github.com/streadway/amqp"
+)
+
+func TestWindow(t *testing.T) {
+	window := confirm.NewWindow(3)
+	for i, message := range []string{"a", "b", "c"} {
+		if tag := window.Add([]byte(message)); tag != uint64(i+1) {
+			t.Fatalf("Expected delivery tag %d, got %d", i+1, tag)
+		}
+	}
+
+	if !window.IsFull() || window.IsSettled() {
+		t.Fatalf("Expected a full window that is not settled")
+	}
+
+	// Duplicate confirmations and confirmations of unknown messages are ignored.
+	window.Confirm(amqp.Confirmation{DeliveryTag: 1, Ack: true})
+	window.Confirm(amqp.Confirmation{DeliveryTag: 1, Ack: true})
+	window.Confirm(amqp.Confirmation{DeliveryTag: 7, Ack: true})
+	window.Confirm(amqp.Confirmation{DeliveryTag: 2, Ack: false})
+	if window.IsSettled() {
+		t.Fatalf("Expected the window not to be settled before the last confirmation")
+	}
+
+	window.Confirm(amqp.Confirmation{DeliveryTag: 3, Ack: true})
+	if !window.IsSettled() {
+		t.Fatalf("Expected the window to be settled")
+	}
+
+	assertMessages(t, window.TakeUnconfirmed(), "b")
+
+	// The delivery tags continue after the removed messages on the same channel.
+	if tag := window.Add([]byte("d")); tag != 4 {
+		t.Fatalf("Expected delivery tag 4, got %d", tag)
+	}
+
+	window.Add([]byte("e"))
+	window.Confirm(amqp.Confirmation{DeliveryTag: 4, Ack: true})
+
+	// The messages that were not confirmed before the channel closed are published again on a new channel.
+	assertMessages(t, window.TakeUnconfirmed(), "e")
+	window.ResetDeliveryTags()
+	if tag := window.Add([]byte("e")); tag != 1 || window.Len() != 1 {
+		t.Fatalf("Expected delivery tag 1 on the new channel, got %d", tag)
+	}
+}
+
+func assertMessages(t *testing.T, messages [][]byte, expected ...string) {
+	actual := []string{}
+	for _, message := range messages {
+		actual = append(actual, string(message))
+	}
+
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected unconfirmed messages %v, got %v", expected, actual)
+	}
+}
@@ -87,10 +87,37 @@ func TestParseContents(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: models.EntryWithLevelAndTimestamp{
+				Level:     "INFO",
+				Timestamp: time.Date(2020, time.June, 10, 9, 18, 30, 0, time.UTC),
+				Rest:      "<--[last index]--(DB) pod_uid[1478] time[1591776000] value[14989] (smart_meter_cabinet_facade.cc::315)",
+			},
+			expectedOutput: &models.ParsedLogEntry{
+				Level:     "INFO",
+				Timestamp: time.Date(2020, time.June, 10, 9, 18, 30, 0, time.UTC),
+				InfoParams: &models.InfoParams{
+					EntryType: models.DCMessage,
+					DCMessage: &models.DCMessageParams{
+						IsInComing:       true,
+						SourceOrDestName: "DB",
+						MessageType:      models.LastIndex,
+						Payload: &models.DcMessagePayload{
+							PodUID: "1478",
+							Value:  14989,
+							Time:   time.Date(2020, time.June, 10, 8, 0, 0, 0, time.UTC), // 1591776000
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for index, test := range tests {
-		output := contentparser.ParseEntryContents(test.input)
+		output, err := contentparser.ParseEntryContents(test.input)
+		if err != nil {
+			t.Fatalf("Could not parse test case no. %d: %s", index, err)
+		}
 
 		if output == nil && test.expectedOutput != nil {
 			t.Fatalf("Test output was nil, but expected output is not nil in test case no. %d", index)
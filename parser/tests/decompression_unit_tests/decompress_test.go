@@ -0,0 +1,162 @@
+package decompressionunittests
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/decompression"
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+type decompressionTest struct {
+	fileName         string
+	contents         []byte
+	expectedLogFiles map[string]string
+	expectedOrder    []string
+}
+
+func TestForEachLogFile(t *testing.T) {
+	members := map[string]string{
+		"dc18/dc_main.log":     "dc main contents",
+		"dc18/plc_manager.log": "plc manager contents",
+	}
+	memberOrder := []string{"dc18/dc_main.log", "dc18/plc_manager.log"}
+
+	tests := []decompressionTest{
+		{
+			fileName:         "dc_main.log",
+			contents:         []byte("plain contents"),
+			expectedLogFiles: map[string]string{"dc_main.log": "plain contents"},
+			expectedOrder:    []string{"dc_main.log"},
+		},
+		{
+			fileName:         "dc_main.log.1.gz",
+			contents:         gzipBytes([]byte("rotated contents")),
+			expectedLogFiles: map[string]string{"dc_main.log.1": "rotated contents"},
+			expectedOrder:    []string{"dc_main.log.1"},
+		},
+		{
+			// Detected by the magic bytes, without the extension.
+			fileName:         "dc_main.log.1",
+			contents:         gzipBytes([]byte("rotated contents")),
+			expectedLogFiles: map[string]string{"dc_main.log.1": "rotated contents"},
+			expectedOrder:    []string{"dc_main.log.1"},
+		},
+		{
+			fileName: "logs.zip",
+			contents: zipBytes(memberOrder, members),
+			expectedLogFiles: map[string]string{
+				"logs.zip/dc18/dc_main.log":     "dc main contents",
+				"logs.zip/dc18/plc_manager.log": "plc manager contents",
+			},
+			expectedOrder: []string{"logs.zip/dc18/dc_main.log", "logs.zip/dc18/plc_manager.log"},
+		},
+		{
+			fileName: "logs.tar.gz",
+			contents: gzipBytes(tarBytes(memberOrder, members)),
+			expectedLogFiles: map[string]string{
+				"logs.tar.gz/dc18/dc_main.log":     "dc main contents",
+				"logs.tar.gz/dc18/plc_manager.log": "plc manager contents",
+			},
+			expectedOrder: []string{"logs.tar.gz/dc18/dc_main.log", "logs.tar.gz/dc18/plc_manager.log"},
+		},
+		{
+			// Compressed members of an archive are decompressed as well.
+			fileName: "logs.zip",
+			contents: zipBytes(
+				[]string{"dc_main.log.1.gz"},
+				map[string]string{"dc_main.log.1.gz": string(gzipBytes([]byte("rotated contents")))}),
+			expectedLogFiles: map[string]string{"logs.zip/dc_main.log.1": "rotated contents"},
+			expectedOrder:    []string{"logs.zip/dc_main.log.1"},
+		},
+	}
+
+	for _, test := range tests {
+		actualLogFiles := map[string]string{}
+		actualOrder := []string{}
+		err := decompression.ForEachLogFile(bytes.NewReader(test.contents), test.fileName,
+			func(logFileName string, reader io.Reader) error {
+				contents, err := ioutil.ReadAll(reader)
+				utils.FailOnError(err, "Could not read log file.")
+				actualLogFiles[logFileName] = string(contents)
+				actualOrder = append(actualOrder, logFileName)
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("Could not decompress %s: %s", test.fileName, err)
+		}
+
+		if !reflect.DeepEqual(actualLogFiles, test.expectedLogFiles) {
+			t.Fatalf("%s: expected %v log files, got %v", test.fileName, test.expectedLogFiles, actualLogFiles)
+		}
+		if !reflect.DeepEqual(actualOrder, test.expectedOrder) {
+			t.Fatalf("%s: expected log files in order %v, got %v", test.fileName, test.expectedOrder, actualOrder)
+		}
+	}
+}
+
+func TestParseCompressedFile(t *testing.T) {
+	logFileBytes, err := ioutil.ReadFile("../logparser_unit_tests/resources/test_dc_main.log")
+	utils.FailOnError(err, "Could not read test log file.")
+
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+
+	fileParser := fileparser.NewFileParser(context.Background(), &mockMessageProducer, progress.NewProgress())
+	err = fileParser.ParseSingleFile(ioutil.NopCloser(bytes.NewReader(gzipBytes(logFileBytes))), "dc_main.log.1.gz")
+	utils.FailOnError(err, "Could not parse compressed log file.")
+
+	// The number of relevant lines in the provided test log file.
+	expectedEntryCount := 40
+	if len(mockMessageProducer.Entries) != expectedEntryCount {
+		t.Fatalf("Expected %d entries, got %d entries.", expectedEntryCount, len(mockMessageProducer.Entries))
+	}
+}
+
+func gzipBytes(contents []byte) []byte {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, err := writer.Write(contents)
+	utils.FailOnError(err, "Could not write gzip contents.")
+	utils.FailOnError(writer.Close(), "Could not close gzip writer.")
+
+	return buffer.Bytes()
+}
+
+func zipBytes(names []string, members map[string]string) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, name := range names {
+		memberWriter, err := writer.Create(name)
+		utils.FailOnError(err, "Could not create zip member.")
+		_, err = memberWriter.Write([]byte(members[name]))
+		utils.FailOnError(err, "Could not write zip member.")
+	}
+	utils.FailOnError(writer.Close(), "Could not close zip writer.")
+
+	return buffer.Bytes()
+}
+
+func tarBytes(names []string, members map[string]string) []byte {
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for _, name := range names {
+		header := tar.Header{Name: name, Mode: 0600, Size: int64(len(members[name])), Typeflag: tar.TypeReg}
+		utils.FailOnError(writer.WriteHeader(&header), "Could not write tar header.")
+		_, err := writer.Write([]byte(members[name]))
+		utils.FailOnError(err, "Could not write tar member.")
+	}
+	utils.FailOnError(writer.Close(), "Could not close tar writer.")
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,124 @@
+package dedupunittests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/dedup"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+// TestSourceDC checks that the files of a DC are matched by their directory,
+// leaving out the archives they were extracted from.
+func TestSourceDC(t *testing.T) {
+	tests := []struct {
+		fileName      string
+		otherFileName string
+		sameDC        bool
+	}{
+		{fileName: "dc_main.log", otherFileName: "plc_manager.log", sameDC: true},
+		{fileName: "dc18/dc_main.log", otherFileName: "dc18/dc_main.log.1", sameDC: true},
+		{fileName: "dc18/dc_main.log", otherFileName: "dc18/export.tar.gz/dc_main.log", sameDC: true},
+		{fileName: "dc18/dc_main.log", otherFileName: "export.zip/dc18/dc_main.log", sameDC: true},
+		{fileName: "dc18/dc_main.log", otherFileName: "dc19/dc_main.log", sameDC: false},
+		{fileName: "site1/dc19/plc_manager.log", otherFileName: "dc19/plc_manager.log", sameDC: false},
+	}
+
+	for index, test := range tests {
+		deduplicator := dedup.NewDeduplicator(time.Hour)
+		entry := testEntry("Task failed", 10)
+
+		deduplicator.IsDuplicate(test.fileName, entry)
+		if actual := deduplicator.IsDuplicate(test.otherFileName, entry); actual != test.sameDC {
+			t.Fatalf("Expected duplicate %v, got %v in test case no. %d", test.sameDC, actual, index)
+		}
+	}
+}
+
+func TestDuplicateFromAnotherFile(t *testing.T) {
+	deduplicator := dedup.NewDeduplicator(time.Hour)
+	entry := testEntry("Task failed", 10)
+
+	if deduplicator.IsDuplicate("dc18/dc_main.log", entryAt(entry, 1)) {
+		t.Fatal("Expected the first entry not to be a duplicate")
+	}
+
+	// The same entry logged twice in a file is kept.
+	if deduplicator.IsDuplicate("dc18/dc_main.log", entryAt(entry, 2)) {
+		t.Fatal("Expected an entry repeated in the same file not to be a duplicate")
+	}
+
+	// A rotated copy contains both entries, they have already been published.
+	if !deduplicator.IsDuplicate("dc18/dc_main.log.1", entryAt(entry, 7)) ||
+		!deduplicator.IsDuplicate("dc18/dc_main.log.1", entryAt(entry, 8)) {
+		t.Fatal("Expected the entries of the rotated copy to be duplicates")
+	}
+
+	// A third occurrence in the rotated copy has not been published yet.
+	if deduplicator.IsDuplicate("dc18/dc_main.log.1", entryAt(entry, 9)) {
+		t.Fatal("Expected the third occurrence not to be a duplicate")
+	}
+
+	if deduplicator.IsDuplicate("dc19/dc_main.log", entryAt(entry, 1)) {
+		t.Fatal("Expected the entry of another DC not to be a duplicate")
+	}
+
+	if deduplicator.IsDuplicate("dc18/dc_main.log.1", testEntry("Task failed", 11)) {
+		t.Fatal("Expected an entry with another timestamp not to be a duplicate")
+	}
+
+	if deduplicator.IsDuplicate("dc18/dc_main.log.1", testEntry("Task finished", 10)) {
+		t.Fatal("Expected an entry with another content not to be a duplicate")
+	}
+}
+
+func TestFingerprintIgnoresSourceAndTimezone(t *testing.T) {
+	entry := testEntry("Task failed", 10)
+	budapest, err := time.LoadLocation("Europe/Budapest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := entryAt(entry, 42)
+	other.RunID = "run-2"
+	other.Timestamp = entry.Timestamp.In(budapest)
+
+	if dedup.NewFingerprint("dc18", entry) != dedup.NewFingerprint("dc18", other) {
+		t.Fatal("Expected the same fingerprint for the same entry at another source location")
+	}
+
+	if dedup.NewFingerprint("dc18", entry) == dedup.NewFingerprint("dc19", entry) {
+		t.Fatal("Expected different fingerprints for the entries of different DCs")
+	}
+}
+
+func TestDeduplicationWindow(t *testing.T) {
+	deduplicator := dedup.NewDeduplicator(10 * time.Millisecond)
+	entry := testEntry("Task failed", 10)
+
+	if deduplicator.IsDuplicate("dc_main.log", entry) {
+		t.Fatal("Expected the first entry not to be a duplicate")
+	}
+
+	// The fingerprint is forgotten after the window.
+	time.Sleep(30 * time.Millisecond)
+	if deduplicator.IsDuplicate("dc_main.log.1", entry) {
+		t.Fatal("Expected the entry not to be a duplicate after the window")
+	}
+}
+
+func testEntry(message string, second int) models.ParsedLogEntry {
+	return models.ParsedLogEntry{
+		Timestamp: time.Date(2020, time.June, 10, 12, 0, second, 0, time.UTC),
+		Level:     "ERROR",
+		ErrorParams: &models.ErrorParams{
+			Message: message,
+		},
+		Source: models.SourceLocation{FileName: "dc_main.log", LineNumber: 1},
+	}
+}
+
+func entryAt(entry models.ParsedLogEntry, lineNumber int64) models.ParsedLogEntry {
+	entry.Source.LineNumber = lineNumber
+	return entry
+}
@@ -0,0 +1,92 @@
+package filedownloaderintegrationtests
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	minioEndpoint  = "minio:9000"
+	minioAccessKey = "minioadmin"
+	minioSecretKey = "minioadmin"
+	testBucketName = "test-logs"
+)
+
+// TestS3DownloaderWithMinio uploads the test log files to a MinIO bucket,
+// then lists and parses them using the S3Downloader and a mock message producer.
+func TestS3DownloaderWithMinio(t *testing.T) {
+	downloader, err := filedownloader.NewS3Downloader(
+		minioEndpoint,
+		minioAccessKey,
+		minioSecretKey,
+		testBucketName,
+		"dc18/",
+		false,
+		true)
+	utils.FailOnError(err, "Could not create S3 downloader.")
+
+	setupBucket(downloader.Client, map[string]string{
+		"dc18/dc_main.log":      "../logparser_unit_tests/resources/test_dc_main.log",
+		"dc18/plc_manager.log":  "../logparser_unit_tests/resources/test_plc_manager.log",
+		"other/plc_manager.log": "../logparser_unit_tests/resources/test_plc_manager.log",
+	})
+
+	// Only the objects under the prefix should be listed.
+	expectedFileNames := []string{"dc18/dc_main.log", "dc18/plc_manager.log"}
+	actualFileNames, err := downloader.ListFileNames()
+	utils.FailOnError(err, "Could not list objects.")
+	if !reflect.DeepEqual(actualFileNames, expectedFileNames) {
+		t.Fatalf("Expected %v file names, got %v", expectedFileNames, actualFileNames)
+	}
+
+	// Downloaded contents should be identical to the uploaded files.
+	readCloser, err := downloader.DownloadFile("dc18/dc_main.log")
+	utils.FailOnError(err, "Could not download object.")
+	actualBytes, err := ioutil.ReadAll(readCloser)
+	utils.FailOnError(err, "Could not read downloaded object.")
+	readCloser.Close()
+
+	expectedBytes, err := ioutil.ReadFile("../logparser_unit_tests/resources/test_dc_main.log")
+	utils.FailOnError(err, "Could not read test log file.")
+
+	if string(actualBytes) != string(expectedBytes) {
+		t.Fatal("Downloaded object does not match the uploaded test log file.")
+	}
+
+	// Parse the files from the bucket.
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+	_, err = logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+
+	// The number of relevant lines in the dc main and plc manager test log files.
+	expectedEntryCount := 90
+	if len(mockMessageProducer.Entries) != expectedEntryCount {
+		t.Fatalf("Expected %d entries, got %d entries.", expectedEntryCount, len(mockMessageProducer.Entries))
+	}
+}
+
+func setupBucket(client *minio.Client, objects map[string]string) {
+	ctx := context.Background()
+
+	exists, err := client.BucketExists(ctx, testBucketName)
+	utils.FailOnError(err, "Could not check test bucket.")
+	if !exists {
+		err = client.MakeBucket(ctx, testBucketName, minio.MakeBucketOptions{})
+		utils.FailOnError(err, "Could not create test bucket.")
+	}
+
+	for objectName, fileName := range objects {
+		_, err = client.FPutObject(ctx, testBucketName, objectName, fileName, minio.PutObjectOptions{})
+		utils.FailOnError(err, "Could not upload test log file.")
+	}
+}
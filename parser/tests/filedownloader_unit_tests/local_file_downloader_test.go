@@ -0,0 +1,110 @@
+package filedownloaderunittests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+)
+
+type localDownloaderTest struct {
+	recursive         bool
+	includePatterns   []string
+	excludePatterns   []string
+	expectedFileNames []string
+}
+
+func TestLocalDownloaderListFileNames(t *testing.T) {
+	rootDirectory := createTestDirectory()
+	defer os.RemoveAll(rootDirectory)
+
+	tests := []localDownloaderTest{
+		{
+			recursive:         false,
+			expectedFileNames: []string{"dc_main.log", "notes.txt", "plc_manager.log"},
+		},
+		{
+			recursive:         true,
+			expectedFileNames: []string{"dc18/dc_main.log", "dc18/old/dc_main.log", "dc_main.log", "notes.txt", "plc_manager.log"},
+		},
+		{
+			recursive:         true,
+			includePatterns:   []string{"*.log"},
+			expectedFileNames: []string{"dc18/dc_main.log", "dc18/old/dc_main.log", "dc_main.log", "plc_manager.log"},
+		},
+		{
+			recursive:         true,
+			includePatterns:   []string{"*.log"},
+			excludePatterns:   []string{"dc18/old/*"},
+			expectedFileNames: []string{"dc18/dc_main.log", "dc_main.log", "plc_manager.log"},
+		},
+		{
+			recursive:         false,
+			includePatterns:   []string{"dc_*"},
+			expectedFileNames: []string{"dc_main.log"},
+		},
+	}
+
+	for _, test := range tests {
+		downloader, err := filedownloader.NewLocalDownloader(
+			rootDirectory,
+			test.recursive,
+			test.includePatterns,
+			test.excludePatterns)
+		utils.FailOnError(err, "Could not create file downloader.")
+
+		actualFileNames, err := downloader.ListFileNames()
+		utils.FailOnError(err, "Could not list files.")
+		if !reflect.DeepEqual(actualFileNames, test.expectedFileNames) {
+			t.Fatalf("Expected %v file names, got %v", test.expectedFileNames, actualFileNames)
+		}
+	}
+}
+
+func TestLocalDownloaderDownloadFile(t *testing.T) {
+	rootDirectory := createTestDirectory()
+	defer os.RemoveAll(rootDirectory)
+
+	downloader, err := filedownloader.NewLocalDownloader(rootDirectory, true, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+
+	readCloser, err := downloader.DownloadFile("dc18/old/dc_main.log")
+	utils.FailOnError(err, "Could not download file.")
+	defer readCloser.Close()
+
+	contents, err := ioutil.ReadAll(readCloser)
+	utils.FailOnError(err, "Could not read downloaded file.")
+
+	if string(contents) != "dc18/old/dc_main.log" {
+		t.Fatalf("Unexpected file contents: %s", string(contents))
+	}
+}
+
+// createTestDirectory creates a temporary directory tree, each file contains its own relative path.
+func createTestDirectory() string {
+	rootDirectory, err := ioutil.TempDir("", "local_downloader_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+
+	fileNames := []string{
+		"dc_main.log",
+		"plc_manager.log",
+		"notes.txt",
+		"dc18/dc_main.log",
+		"dc18/old/dc_main.log",
+	}
+
+	for _, fileName := range fileNames {
+		path := filepath.Join(rootDirectory, filepath.FromSlash(fileName))
+		err = os.MkdirAll(filepath.Dir(path), 0700)
+		utils.FailOnError(err, "Could not create test directory.")
+
+		err = ioutil.WriteFile(path, []byte(fileName), 0600)
+		utils.FailOnError(err, "Could not create test file.")
+	}
+
+	return rootDirectory
+}
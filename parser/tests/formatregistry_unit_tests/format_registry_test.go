@@ -0,0 +1,178 @@
+package formatregistryunittests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/formatregistry"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+const testRegistryYAML = `
+entryFormats:
+  - name: firmware_update
+    level: INFO
+    match: "Firmware update"
+    entryType: FirmwareUpdate
+    fields:
+      - name: SmcUID
+        key: smc_uid
+        extractor: bracketed
+      - name: UID
+        key: uid
+        extractor: int
+      - name: Started
+        key: started
+        extractor: date
+      - name: SourceFile
+        extractor: parenthesised
+  - name: firmware_image
+    level: INFO
+    match: "\\[firmware image\\]"
+    entryType: FirmwareImage
+    fallback: true
+    fields:
+      - name: SmcUID
+        key: smc_uid
+        extractor: bracketed
+  - name: unknown_info
+    level: INFO
+    entryType: UnknownInfo
+    fallback: true
+`
+
+const testRegistryJSON = `{
+  "entryFormats": [
+    {"name": "routing", "level": "INFO", "match": "Routing Table", "entryType": "Routing"}
+  ]
+}`
+
+type registryTest struct {
+	line          string
+	expectedEntry *models.GenericParams
+}
+
+func TestFormatRegistryYAML(t *testing.T) {
+	registry, err := formatregistry.ParseRegistry([]byte(testRegistryYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []registryTest{
+		{
+			line: "Wed Jun 10 09:18:28 2020 INFO    : Firmware update smc_uid[dc18-smc3] uid[1479] " +
+				"started[Wed Jun 10 09:18:20 2020] (firmware_updater.cc::42)",
+			expectedEntry: &models.GenericParams{
+				FormatName: "firmware_update",
+				EntryType:  "FirmwareUpdate",
+				Fields: map[string]interface{}{
+					"SmcUID":     "dc18-smc3",
+					"UID":        int64(1479),
+					"Started":    time.Date(2020, time.June, 10, 9, 18, 20, 0, time.UTC),
+					"SourceFile": "firmware_updater.cc::42",
+				},
+			},
+		},
+		{
+			// Missing fields are left out of the payload.
+			line: "Wed Jun 10 09:18:28 2020 INFO    : Firmware update smc_uid[dc18-smc3]",
+			expectedEntry: &models.GenericParams{
+				FormatName: "firmware_update",
+				EntryType:  "FirmwareUpdate",
+				Fields:     map[string]interface{}{"SmcUID": "dc18-smc3"},
+			},
+		},
+		{
+			// Unknown entries are parsed by the fallback format.
+			line: "Wed Jun 10 09:18:28 2020 INFO    : Something new happened",
+			expectedEntry: &models.GenericParams{
+				FormatName: "unknown_info",
+				EntryType:  "UnknownInfo",
+				Fields:     map[string]interface{}{},
+			},
+		},
+		{
+			// The dc messages of an unknown type are parsed by the fallback formats.
+			line: "Wed Jun 10 09:18:28 2020 INFO    : <--[firmware image]--(DB) smc_uid[dc18-smc3] " +
+				"version[01.08] (distribution_controller_initializer.cc::251)",
+			expectedEntry: &models.GenericParams{
+				FormatName: "firmware_image",
+				EntryType:  "FirmwareImage",
+				Fields:     map[string]interface{}{"SmcUID": "dc18-smc3"},
+			},
+		},
+		{
+			// Entries recognised by the built-in parsers are not parsed by fallback formats.
+			line: "Wed Jun 10 09:18:28 2020 INFO    : <--[pod configuration]--(DB) pod_uid[1479] " +
+				"serial_number[98020068957] (distribution_controller_initializer.cc::244)",
+			expectedEntry: nil,
+		},
+	}
+
+	for _, test := range tests {
+		entry, _, _ := fileparser.ParseLineWithRegistry(test.line, registry)
+		if entry == nil {
+			t.Fatalf("Could not parse line: %s", test.line)
+		}
+
+		if !reflect.DeepEqual(entry.GenericParams, test.expectedEntry) {
+			t.Fatalf("Expected generic params %+v, got %+v", test.expectedEntry, entry.GenericParams)
+		}
+	}
+}
+
+func TestFormatRegistryJSON(t *testing.T) {
+	registry, err := formatregistry.ParseRegistry([]byte(testRegistryJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, _, _ := fileparser.ParseLineWithRegistry("Wed Jun 10 09:18:30 2020 INFO    : Routing Table: Addr[0x0008]", registry)
+	if entry == nil || entry.GenericParams == nil || entry.GenericParams.EntryType != "Routing" {
+		t.Fatalf("Expected the entry format to take precedence over the built-in parser, got %+v", entry)
+	}
+
+	if entry.InfoParams != nil {
+		t.Fatalf("Expected no info params, got %+v", entry.InfoParams)
+	}
+}
+
+func TestExampleFormatRegistry(t *testing.T) {
+	registry, err := formatregistry.LoadRegistry("../../../deployments/format_registry.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, _, _ := fileparser.ParseLineWithRegistry("[ 2020-06-10-09:18:38 ]INFO: Management socket = 9", registry)
+	if entry == nil || entry.GenericParams == nil || entry.GenericParams.EntryType != "ManagementSocket" {
+		t.Fatalf("Expected a ManagementSocket entry, got %+v", entry)
+	}
+
+	if len(registry.LogFormats) != 2 {
+		t.Fatalf("Expected 2 log formats, got %d", len(registry.LogFormats))
+	}
+}
+
+func TestInvalidFormatRegistry(t *testing.T) {
+	invalidRegistries := []string{
+		"entryFormats:\n  - name: Firmware\n",
+		"entryFormats:\n  - name: Firmware\n    entryType: Firmware\n    match: '('\n",
+		"entryFormats:\n  - name: Firmware\n    entryType: Firmware\n    fields:\n      - extractor: quoted\n",
+		"unknownKey: true\n",
+		"logFormats:\n  - name: Modem\n    fileNamePattern: '[modem'\n    entryStart: '^\\w'\n",
+		"logFormats:\n  - name: Modem\n    fileNamePattern: '*modem*'\n    entryStart: '('\n",
+		"logFormats:\n  - name: Modem\n    entryStart: '^\\w'\n",
+	}
+
+	for index, invalidRegistry := range invalidRegistries {
+		if _, err := formatregistry.ParseRegistry([]byte(invalidRegistry)); err == nil {
+			t.Fatalf("Expected an error for the invalid registry no. %d", index)
+		}
+	}
+
+	if _, err := formatregistry.LoadRegistry("missing_format_registry.yaml"); err == nil {
+		t.Fatal("Expected an error for a missing registry file")
+	}
+}
@@ -0,0 +1,204 @@
+package jobsunittests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/jobs"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+)
+
+// blockingRun returns a run function that parses the given number of files,
+// and then blocks until the release channel is closed or the job is cancelled.
+func blockingRun(release <-chan struct{}) jobs.RunFunc {
+	return func(ctx context.Context, request jobs.Request, runProgress *progress.Progress) error {
+		runProgress.SetFilesTotal(len(request.Files))
+		for range request.Files {
+			runProgress.FileDone()
+		}
+
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+
+		return nil
+	}
+}
+
+func TestJobManagerQueuesJobs(t *testing.T) {
+	release := make(chan struct{})
+	manager := jobs.NewManager(jobs.QueueConcurrentJobs, blockingRun(release))
+
+	first, err := manager.Submit(jobs.Request{Files: []string{"dc_main.log", "plc_manager.log"}})
+	if err != nil {
+		t.Fatalf("Could not submit first job: %s", err)
+	}
+
+	second, err := manager.Submit(jobs.Request{})
+	if err != nil {
+		t.Fatalf("Could not submit second job: %s", err)
+	}
+
+	waitForStatus(t, manager, first.ID, jobs.Running)
+	if job, _ := manager.Get(second.ID); job.Status != jobs.Queued {
+		t.Fatalf("Expected second job to be queued, got %s", job.Status)
+	}
+
+	close(release)
+	firstJob := waitForStatus(t, manager, first.ID, jobs.Succeeded)
+	waitForStatus(t, manager, second.ID, jobs.Succeeded)
+
+	if firstJob.Progress.FilesTotal != 2 || firstJob.Progress.FilesDone != 2 {
+		t.Fatalf("Unexpected progress of the first job: %+v", firstJob.Progress)
+	}
+
+	// The data of the run can be found by the ID of the job.
+	if firstJob.Report.RunID != first.ID {
+		t.Fatalf("Expected run ID %s in the report of the first job, got %q", first.ID, firstJob.Report.RunID)
+	}
+}
+
+func TestJobManagerRejectsConcurrentJobs(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	manager := jobs.NewManager(jobs.RejectConcurrentJobs, blockingRun(release))
+
+	_, err := manager.Submit(jobs.Request{})
+	if err != nil {
+		t.Fatalf("Could not submit first job: %s", err)
+	}
+
+	_, err = manager.Submit(jobs.Request{})
+	if err != jobs.ErrJobRunning {
+		t.Fatalf("Expected %s, got %v", jobs.ErrJobRunning, err)
+	}
+}
+
+func TestJobManagerCancelsJobs(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	manager := jobs.NewManager(jobs.QueueConcurrentJobs, blockingRun(release))
+
+	running, _ := manager.Submit(jobs.Request{})
+	queued, _ := manager.Submit(jobs.Request{})
+
+	if job, _ := manager.Cancel(queued.ID); job.Status != jobs.Cancelled {
+		t.Fatalf("Expected queued job to be cancelled, got %s", job.Status)
+	}
+
+	waitForStatus(t, manager, running.ID, jobs.Running)
+	manager.Cancel(running.ID)
+	waitForStatus(t, manager, running.ID, jobs.Cancelled)
+
+	if _, found := manager.Cancel("unknown"); found {
+		t.Fatal("Expected unknown job not to be found")
+	}
+}
+
+func TestJobManagerRecoversFailedJobs(t *testing.T) {
+	manager := jobs.NewManager(jobs.QueueConcurrentJobs,
+		func(ctx context.Context, request jobs.Request, runProgress *progress.Progress) error {
+			panic("storage unavailable")
+		})
+
+	failed, _ := manager.Submit(jobs.Request{})
+	job := waitForStatus(t, manager, failed.ID, jobs.Failed)
+	if len(job.Progress.Errors) != 1 || job.Progress.Errors[0] != "storage unavailable" {
+		t.Fatalf("Unexpected errors of the failed job: %v", job.Progress.Errors)
+	}
+}
+
+func TestJobManagerFailsJobsReturningErrors(t *testing.T) {
+	manager := jobs.NewManager(jobs.QueueConcurrentJobs,
+		func(ctx context.Context, request jobs.Request, runProgress *progress.Progress) error {
+			return errors.New("could not list blobs")
+		})
+
+	failed, _ := manager.Submit(jobs.Request{})
+	job := waitForStatus(t, manager, failed.ID, jobs.Failed)
+	if len(job.Progress.Errors) != 1 || job.Progress.Errors[0] != "could not list blobs" {
+		t.Fatalf("Unexpected errors of the failed job: %v", job.Progress.Errors)
+	}
+
+	// The next job runs after the failed one.
+	next, _ := manager.Submit(jobs.Request{})
+	waitForStatus(t, manager, next.ID, jobs.Failed)
+}
+
+func TestJobManagerEvictsFinishedJobs(t *testing.T) {
+	manager := jobs.NewManager(jobs.QueueConcurrentJobs,
+		func(ctx context.Context, request jobs.Request, runProgress *progress.Progress) error {
+			return nil
+		})
+	manager.SetMaxFinishedJobs(2)
+
+	submitted := []jobs.Job{}
+	for i := 0; i < 3; i++ {
+		job, _ := manager.Submit(jobs.Request{})
+		waitForStatus(t, manager, job.ID, jobs.Succeeded)
+		submitted = append(submitted, job)
+	}
+
+	if _, found := manager.Get(submitted[0].ID); found {
+		t.Fatal("Expected the oldest finished job to be evicted")
+	}
+
+	for _, job := range submitted[1:] {
+		if _, found := manager.Get(job.ID); !found {
+			t.Fatalf("Expected job %s to be kept", job.ID)
+		}
+	}
+}
+
+func TestJobFieldNames(t *testing.T) {
+	body, err := json.Marshal(jobs.Job{ID: "job-1", Status: jobs.Queued, Request: jobs.Request{Reprocess: true}})
+	if err != nil {
+		t.Fatalf("Could not serialize job: %s", err)
+	}
+
+	for _, field := range []string{`"ID":"job-1"`, `"Status":"queued"`, `"Reprocess":true`, `"FilesTotal":0`} {
+		if !strings.Contains(string(body), field) {
+			t.Fatalf("Expected %s in the job, got %s", field, body)
+		}
+	}
+
+	// The times of a job that has not started yet are left out.
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(body, &fields)
+	if err != nil {
+		t.Fatalf("Could not deserialize job: %s", err)
+	}
+
+	if _, found := fields["StartedAt"]; found {
+		t.Fatalf("Expected no start time in the queued job, got %s", body)
+	}
+
+	if _, found := fields["FinishedAt"]; found {
+		t.Fatalf("Expected no finish time in the queued job, got %s", body)
+	}
+}
+
+func waitForStatus(t *testing.T, manager *jobs.Manager, id string, status jobs.Status) jobs.Job {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, found := manager.Get(id)
+		if !found {
+			t.Fatalf("Job not found: %s", id)
+		}
+
+		if job.Status == status {
+			return job
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected job %s to be %s, got %s", id, status, job.Status)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -3,7 +3,6 @@ package logparserintegrationtests
 import (
 	"io/ioutil"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/kozgot/go-log-processing/parser/internal/logparser"
@@ -41,7 +40,8 @@ func TestLogParserDCMain(t *testing.T) {
 
 	// Parse
 	logParser := logparser.NewLogParser(mockDownloader, rabbitProducer)
-	logParser.ParseLogfiles()
+	_, err = logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
 
 	// Get entries sent to RabbitMq.
 	entries := getSentParsedEntries(msgs)
@@ -89,7 +89,8 @@ func TestLogParserPLCManager(t *testing.T) {
 
 	// Parse
 	logParser := logparser.NewLogParser(mockDownloader, rabbitProducer)
-	logParser.ParseLogfiles()
+	_, err = logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
 
 	// Get entries sent to RabbitMq.
 	entries := getSentParsedEntries(msgs)
@@ -135,7 +136,8 @@ func setupDependencies(
 	testConsumer.Connect()
 
 	// Open a connection and a channel to send the log entries to.
-	rabbitMqProducer.OpenChannelAndConnection()
+	err := rabbitMqProducer.OpenChannelAndConnection()
+	utils.FailOnError(err, "Could not open producer.")
 
 	// Create mock filedownloader.
 	mockFileDownloader := mocks.MockFileDownloader{FileNameToDownload: testLogfileName}
@@ -154,18 +156,30 @@ func getSentParsedEntries(deliveries <-chan amqp.Delivery) []models.ParsedLogEnt
 	entries := []models.ParsedLogEntry{}
 
 	for d := range deliveries {
-		if strings.Contains(string(d.Body), "END") {
-			log.Println("End of entries...")
+		envelope, err := models.OpenEnvelope(d.Body)
+		utils.FailOnError(err, "Could not open envelope")
+
+		if envelope.PayloadType == models.RunControlPayload {
+			runControl, err := envelope.RunControl()
+			utils.FailOnError(err, "Could not deserialize run control message")
 
 			// Acknowledge the message after it has been processed.
-			err := d.Ack(false)
-			utils.FailOnError(err, "Could not acknowledge END message")
-			break
+			err = d.Ack(false)
+			utils.FailOnError(err, "Could not acknowledge run control message")
+
+			if runControl.ControlType == models.RunEnd {
+				log.Println("End of entries...")
+				break
+			}
+			continue
 		}
 		entry := models.ParsedLogEntry{}
-		entry.FromJSON(d.Body)
+		entry.FromJSON(envelope.Payload)
+
+		// The run ID is generated, it is removed to compare the entries to the expected entries.
+		entry.RunID = ""
 		entries = append(entries, entry)
-		err := d.Ack(false)
+		err = d.Ack(false)
 		utils.FailOnError(err, "Could not acknowledge")
 	}
 
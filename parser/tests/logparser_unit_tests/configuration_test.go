@@ -0,0 +1,33 @@
+package logparserunittests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+// TestInvalidConfiguration checks that the configuration errors are returned,
+// so a service can report them instead of exiting.
+func TestInvalidConfiguration(t *testing.T) {
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader := mocks.MockFileDownloader{}
+
+	_, err := logparser.NewIncrementalLogParser(&downloader, &mockMessageProducer, nil, false)
+	if !errors.Is(err, logparser.ErrRangeDownloaderRequired) {
+		t.Fatalf("Expected ErrRangeDownloaderRequired for incremental parsing, got %v", err)
+	}
+
+	logParser := logparser.NewLogParser(&downloader, &mockMessageProducer)
+	err = logParser.FollowLogfiles(logparser.FollowConfig{FileNames: []string{"dc_main.log"}}, make(chan struct{}))
+	if !errors.Is(err, logparser.ErrRangeDownloaderRequired) {
+		t.Fatalf("Expected ErrRangeDownloaderRequired for the follow mode, got %v", err)
+	}
+
+	err = logParser.SetWorkerCount(0)
+	if err == nil {
+		t.Fatal("Expected an error for worker count 0")
+	}
+}
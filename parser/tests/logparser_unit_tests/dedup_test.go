@@ -0,0 +1,64 @@
+package logparserunittests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+// TestDeduplication checks that the entries of a rotated copy are suppressed,
+// unless they were written by another DC, and that the suppressed duplicates are counted in the report.
+func TestDeduplication(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "dedup_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(logDirectory)
+
+	testLogFileBytes, err := ioutil.ReadFile("./resources/test_dc_main.log")
+	utils.FailOnError(err, "Could not read test log file.")
+
+	for _, fileName := range []string{"dc18/dc_main.log", "dc18/dc_main.log.1", "dc19/dc_main.log"} {
+		logFilePath := filepath.Join(logDirectory, filepath.FromSlash(fileName))
+		utils.FailOnError(os.MkdirAll(filepath.Dir(logFilePath), 0700), "Could not create DC directory.")
+		err = ioutil.WriteFile(logFilePath, testLogFileBytes, 0600)
+		utils.FailOnError(err, "Could not write test log file.")
+	}
+
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(logDirectory, true, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+	logParser.SetDeduplicationWindow(time.Hour)
+	runReport, err := logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+
+	// The number of relevant lines in the provided test log file is 40, the entries of dc18 are published once.
+	if len(mockMessageProducer.Entries) != 80 {
+		t.Fatalf("Expected 80 entries, got %d", len(mockMessageProducer.Entries))
+	}
+
+	if runReport.Totals.DuplicatesSuppressed != 40 || runReport.Totals.EntriesParsed != 80 {
+		t.Fatalf("Expected 80 parsed entries and 40 suppressed duplicates, got %d and %d",
+			runReport.Totals.EntriesParsed, runReport.Totals.DuplicatesSuppressed)
+	}
+
+	runEnd := mockMessageProducer.RunControls[len(mockMessageProducer.RunControls)-1]
+	if runEnd.TotalEntries != 80 {
+		t.Fatalf("Expected 80 entries in the run end, got %d", runEnd.TotalEntries)
+	}
+
+	// The workers parse the files of dc18 concurrently, so the entries may be published from either file.
+	for _, fileReport := range runReport.Files {
+		if fileReport.FileName == "dc19/dc_main.log" && fileReport.DuplicatesSuppressed != 0 {
+			t.Fatalf("Expected no duplicates suppressed in dc19, got %d", fileReport.DuplicatesSuppressed)
+		}
+	}
+}
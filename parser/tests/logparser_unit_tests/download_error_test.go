@@ -0,0 +1,74 @@
+package logparserunittests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+// failingDownloader lists the given files, and fails to download the missing ones,
+// or to list the files if listErr is set.
+type failingDownloader struct {
+	fileNames []string
+	listErr   error
+}
+
+func (downloader *failingDownloader) ListFileNames() ([]string, error) {
+	return downloader.fileNames, downloader.listErr
+}
+
+func (downloader *failingDownloader) DownloadFile(fileName string) (io.ReadCloser, error) {
+	return os.Open(fileName)
+}
+
+// TestFailedDownload checks that a file that could not be downloaded fails the run,
+// while the other files are still parsed.
+func TestFailedDownload(t *testing.T) {
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader := failingDownloader{
+		fileNames: []string{"./resources/test_dc_main.log", "./resources/missing.log"},
+	}
+
+	runProgress := progress.NewProgress()
+	logParser := logparser.NewLogParser(&downloader, &mockMessageProducer)
+	_, err := logParser.ParseSelectedLogfiles(context.Background(), nil, runProgress)
+	if err == nil {
+		t.Fatal("Expected an error for the missing file")
+	}
+
+	snapshot := runProgress.Snapshot()
+	if snapshot.FilesFailed != 1 || snapshot.FilesDone != 1 || len(snapshot.Errors) != 1 {
+		t.Fatalf("Expected 1 failed and 1 parsed file with 1 error, got %d, %d and %v",
+			snapshot.FilesFailed, snapshot.FilesDone, snapshot.Errors)
+	}
+
+	// The number of relevant lines in the provided test log file.
+	if len(mockMessageProducer.Entries) != 40 {
+		t.Fatalf("Expected 40 entries, got %d", len(mockMessageProducer.Entries))
+	}
+}
+
+// TestFailedListing checks that the run fails without parsing any files if the files could not be listed.
+func TestFailedListing(t *testing.T) {
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	listErr := errors.New("could not list blobs")
+	downloader := failingDownloader{listErr: listErr}
+
+	logParser := logparser.NewLogParser(&downloader, &mockMessageProducer)
+	_, err := logParser.ParseSelectedLogfiles(context.Background(), nil, progress.NewProgress())
+	if !errors.Is(err, listErr) {
+		t.Fatalf("Expected the listing error, got %v", err)
+	}
+
+	if len(mockMessageProducer.Entries) != 0 || len(mockMessageProducer.RunControls) != 0 {
+		t.Fatalf("Expected no entries and no run control messages, got %d and %d",
+			len(mockMessageProducer.Entries), len(mockMessageProducer.RunControls))
+	}
+}
@@ -0,0 +1,150 @@
+package logparserunittests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+const (
+	followedINFOLine = "Wed Jun 10 09:18:28 2020 INFO    : <--[pod configuration]--(DB) pod_uid[1479] " +
+		"serial_number[98020068957] phase[2] smc_uid[dc18-smc3] service_level_id[9] position_in_smc[3] " +
+		"software_firmware_version[IMETER190530] (distribution_controller_initializer.cc::244)\n"
+	followedVERBOSELine = "Wed Jun 10 09:18:30 2020 VERBOSE : SMC[dc18-smc3] changing state, new state[0] " +
+		"((null)::-1225668530)\n"
+)
+
+func TestFollowLogfiles(t *testing.T) {
+	rootDirectory, err := ioutil.TempDir("", "follow_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(rootDirectory)
+
+	logFilePath := filepath.Join(rootDirectory, "dc_main.log")
+	writeLogFile(logFilePath, followedINFOLine+followedVERBOSELine, os.O_CREATE|os.O_WRONLY)
+
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(rootDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := logParser.FollowLogfiles(logparser.FollowConfig{
+			FileNames:     []string{"dc_main.log"},
+			PollInterval:  10 * time.Millisecond,
+			FromBeginning: true,
+		}, stop)
+		utils.FailOnError(err, "Could not follow log files.")
+	}()
+
+	// The existing contents are parsed first.
+	waitForEntryCount(t, &mockMessageProducer, 1)
+
+	// Appended lines are parsed, an incomplete line is only parsed when it is complete.
+	writeLogFile(logFilePath, followedINFOLine+followedINFOLine[:50], os.O_APPEND|os.O_WRONLY)
+	waitForEntryCount(t, &mockMessageProducer, 2)
+
+	writeLogFile(logFilePath, followedINFOLine[50:], os.O_APPEND|os.O_WRONLY)
+	waitForEntryCount(t, &mockMessageProducer, 3)
+
+	// A truncated file is parsed again from the beginning.
+	writeLogFile(logFilePath, followedINFOLine, os.O_TRUNC|os.O_WRONLY)
+	waitForEntryCount(t, &mockMessageProducer, 4)
+
+	close(stop)
+	<-done
+
+	for _, entry := range mockMessageProducer.GetEntries() {
+		if entry.InfoParams == nil || entry.InfoParams.DCMessage == nil ||
+			entry.InfoParams.DCMessage.MessageType != models.PodConfig {
+			t.Fatal("Expected only pod configuration entries.")
+		}
+	}
+}
+
+// TestFollowRotatedLogfile checks that a rotated file is parsed from the beginning,
+// even if the new file has already grown past the parsed part of the old file when it is polled.
+func TestFollowRotatedLogfile(t *testing.T) {
+	rootDirectory, err := ioutil.TempDir("", "follow_rotation_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(rootDirectory)
+
+	logFilePath := filepath.Join(rootDirectory, "dc_main.log")
+	writeLogFile(logFilePath, followedINFOLine+followedVERBOSELine, os.O_CREATE|os.O_WRONLY)
+
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(rootDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := logParser.FollowLogfiles(logparser.FollowConfig{
+			FileNames:     []string{"dc_main.log"},
+			PollInterval:  10 * time.Millisecond,
+			FromBeginning: true,
+		}, stop)
+		utils.FailOnError(err, "Could not follow log files.")
+	}()
+
+	waitForEntryCount(t, &mockMessageProducer, 1)
+
+	// The new file replaces the old one at once, and it is longer than the parsed part of the old file,
+	// so the old offset points into the middle of its second line.
+	rotatedINFOLine := strings.Replace(followedINFOLine, "09:18:28", "10:18:28", 1)
+	newLogFilePath := filepath.Join(rootDirectory, "dc_main.log.new")
+	writeLogFile(newLogFilePath, strings.Repeat(rotatedINFOLine, 3), os.O_CREATE|os.O_WRONLY)
+	utils.FailOnError(os.Rename(newLogFilePath, logFilePath), "Could not rotate test log file.")
+
+	waitForEntryCount(t, &mockMessageProducer, 4)
+
+	close(stop)
+	<-done
+
+	for index, entry := range mockMessageProducer.GetEntries()[1:] {
+		if entry.Timestamp.Hour() != 10 || entry.Source.LineNumber != int64(index+1) {
+			t.Fatalf("Expected the lines of the new file from the beginning, got line %d at %v",
+				entry.Source.LineNumber, entry.Timestamp)
+		}
+	}
+}
+
+func writeLogFile(path string, contents string, flag int) {
+	file, err := os.OpenFile(path, flag, 0600)
+	utils.FailOnError(err, "Could not open test log file.")
+	defer file.Close()
+
+	_, err = file.WriteString(contents)
+	utils.FailOnError(err, "Could not write test log file.")
+}
+
+func waitForEntryCount(t *testing.T, producer *mocks.MessageProducerMock, expectedEntryCount int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(producer.GetEntries()) >= expectedEntryCount {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Wait for a few more polls, to make sure that no unexpected entries are published.
+	time.Sleep(50 * time.Millisecond)
+
+	actualEntryCount := len(producer.GetEntries())
+	if actualEntryCount != expectedEntryCount {
+		t.Fatalf("Expected %d entries, got %d entries.", expectedEntryCount, actualEntryCount)
+	}
+}
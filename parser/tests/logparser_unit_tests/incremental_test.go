@@ -0,0 +1,133 @@
+package logparserunittests
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/checkpoint"
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+func TestIncrementalLogParser(t *testing.T) {
+	rootDirectory, err := ioutil.TempDir("", "incremental_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(rootDirectory)
+
+	logDirectory := filepath.Join(rootDirectory, "logs")
+	err = os.Mkdir(logDirectory, 0700)
+	utils.FailOnError(err, "Could not create log directory.")
+
+	testLogFileBytes, err := ioutil.ReadFile("./resources/test_dc_main.log")
+	utils.FailOnError(err, "Could not read test log file.")
+
+	logFilePath := filepath.Join(logDirectory, "dc_main.log")
+	err = ioutil.WriteFile(logFilePath, testLogFileBytes, 0600)
+	utils.FailOnError(err, "Could not write test log file.")
+
+	checkpointFilePath := filepath.Join(rootDirectory, "checkpoints.json")
+
+	// The first run parses the whole file.
+	// The number of relevant lines in the provided test log file is 40.
+	assertIncrementalRun(t, logDirectory, checkpointFilePath, false, 40)
+
+	// The second run skips the unchanged file.
+	assertIncrementalRun(t, logDirectory, checkpointFilePath, false, 0)
+
+	// Only the appended lines are parsed.
+	writeLogFile(logFilePath, followedINFOLine+followedVERBOSELine+followedINFOLine, os.O_APPEND|os.O_WRONLY)
+	assertIncrementalRun(t, logDirectory, checkpointFilePath, false, 2)
+
+	// Every file is parsed from the beginning when reprocessing is requested.
+	assertIncrementalRun(t, logDirectory, checkpointFilePath, true, 42)
+	assertIncrementalRun(t, logDirectory, checkpointFilePath, false, 0)
+
+	// A replaced file, that is already longer than the parsed part of the old file, is parsed from the beginning.
+	err = ioutil.WriteFile(logFilePath, append([]byte(strings.Repeat(followedINFOLine, 3)), testLogFileBytes...), 0600)
+	utils.FailOnError(err, "Could not replace test log file.")
+	assertIncrementalRun(t, logDirectory, checkpointFilePath, false, 43)
+}
+
+// cancellingProducer cancels the run when the first entry is published.
+type cancellingProducer struct {
+	mocks.MessageProducerMock
+	cancel context.CancelFunc
+}
+
+func (producer *cancellingProducer) PublishEntry(line models.ParsedLogEntry) error {
+	err := producer.MessageProducerMock.PublishEntry(line)
+	producer.cancel()
+	return err
+}
+
+// TestResumeCancelledMultilineEntry checks that a multi-line entry, that was pending when the run was cancelled,
+// is parsed when the file is parsed again from its checkpoint.
+func TestResumeCancelledMultilineEntry(t *testing.T) {
+	rootDirectory, err := ioutil.TempDir("", "incremental_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(rootDirectory)
+
+	logDirectory := filepath.Join(rootDirectory, "logs")
+	err = os.Mkdir(logDirectory, 0700)
+	utils.FailOnError(err, "Could not create log directory.")
+
+	// The first entry is published when the first line of the split entry is read, and the run is cancelled.
+	writeLogFile(filepath.Join(logDirectory, "dc_main.log"),
+		followedINFOLine+splitINFOLine+followedINFOLine, os.O_CREATE|os.O_WRONLY)
+	checkpointFilePath := filepath.Join(rootDirectory, "checkpoints.json")
+
+	downloader, err := filedownloader.NewLocalDownloader(logDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+	checkpointStore, err := checkpoint.NewFileStore(checkpointFilePath)
+	utils.FailOnError(err, "Could not load checkpoints.")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	producer := cancellingProducer{cancel: cancel}
+
+	logParser, err := logparser.NewIncrementalLogParser(downloader, &producer, checkpointStore, false)
+	utils.FailOnError(err, "Could not create log parser.")
+	_, err = logParser.ParseSelectedLogfiles(ctx, nil, progress.NewProgress())
+	utils.FailOnError(err, "Could not parse log files.")
+
+	if len(producer.Entries) != 1 {
+		t.Fatalf("Expected 1 entry before the run was cancelled, got %d entries.", len(producer.Entries))
+	}
+
+	// The resumed run parses the split entry from its first line.
+	assertIncrementalRun(t, logDirectory, checkpointFilePath, false, 2)
+}
+
+func assertIncrementalRun(
+	t *testing.T,
+	logDirectory string,
+	checkpointFilePath string,
+	reprocessAll bool,
+	expectedEntryCount int,
+) {
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(logDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+
+	// A new store is created for every run, to make sure that the checkpoints are persisted.
+	checkpointStore, err := checkpoint.NewFileStore(checkpointFilePath)
+	utils.FailOnError(err, "Could not load checkpoints.")
+
+	logParser, err := logparser.NewIncrementalLogParser(downloader, &mockMessageProducer, checkpointStore, reprocessAll)
+	utils.FailOnError(err, "Could not create log parser.")
+	_, err = logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+
+	actualEntryCount := len(mockMessageProducer.Entries)
+	if actualEntryCount != expectedEntryCount {
+		t.Fatalf("Expected %d entries, got %d entries.", expectedEntryCount, actualEntryCount)
+	}
+}
@@ -23,7 +23,8 @@ func TestLogParserDCMain(t *testing.T) {
 
 	// Run parser
 	logParser := logparser.NewLogParser(&mockFileDownloader, &mockMessageProducer)
-	logParser.ParseLogfiles()
+	_, err := logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
 
 	// The number of relevant lines in the provided test log file.
 	expectedEntryCount := 40
@@ -59,7 +60,8 @@ func TestLogParserPLCManager(t *testing.T) {
 
 	// Run parser
 	logParser := logparser.NewLogParser(&mockFileDownloader, &mockMessageProducer)
-	logParser.ParseLogfiles()
+	_, err := logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
 
 	// The number of relevant lines in the provided test log file.
 	expectedEntryCount := 50
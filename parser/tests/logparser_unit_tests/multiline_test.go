@@ -0,0 +1,80 @@
+package logparserunittests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+// splitINFOLine is followedINFOLine split into three physical lines.
+const splitINFOLine = "Wed Jun 10 09:18:28 2020 INFO    : <--[pod configuration]--(DB) pod_uid[1479]\n" +
+	"    serial_number[98020068957] phase[2] smc_uid[dc18-smc3] service_level_id[9] position_in_smc[3]\n" +
+	"    software_firmware_version[IMETER190530] (distribution_controller_initializer.cc::244)\n"
+
+func TestMultilineEntries(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "multiline_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(logDirectory)
+
+	writeLogFile(filepath.Join(logDirectory, "dc_main.log"), splitINFOLine+followedINFOLine, os.O_CREATE|os.O_WRONLY)
+
+	// The continuation lines are joined to the first line of the entry.
+	entries := parseMultilineTestFiles(logDirectory, nil)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d entries.", len(entries))
+	}
+
+	expectedEntry := fileparser.ParseLine(strings.TrimSuffix(followedINFOLine, "\n"))
+	if !reflect.DeepEqual(entries[0].InfoParams, expectedEntry.InfoParams) {
+		t.Fatalf("Expected info params %+v, got %+v", expectedEntry.InfoParams, entries[0].InfoParams)
+	}
+
+	assertSourceLocation(t, entries, 0, models.SourceLocation{
+		FileName:   "dc_main.log",
+		LineNumber: 1,
+		ByteOffset: 0,
+		RawLine:    strings.TrimSuffix(splitINFOLine, "\n"),
+	})
+	assertSourceLocation(t, entries, 1, models.SourceLocation{
+		FileName:   "dc_main.log",
+		LineNumber: 4,
+		ByteOffset: int64(len(splitINFOLine)),
+		RawLine:    strings.TrimSuffix(followedINFOLine, "\n"),
+	})
+
+	// Without a matching continuation rule, the lines are parsed one by one, and the continuation lines are dropped.
+	entries = parseMultilineTestFiles(logDirectory, []fileparser.ContinuationRule{})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d entries.", len(entries))
+	}
+
+	if reflect.DeepEqual(entries[0].InfoParams, expectedEntry.InfoParams) {
+		t.Fatal("Expected the continuation lines to be dropped without a continuation rule.")
+	}
+}
+
+func parseMultilineTestFiles(logDirectory string, rules []fileparser.ContinuationRule) []models.ParsedLogEntry {
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(logDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+	logParser.SetIncludeRawLines(true)
+	if rules != nil {
+		logParser.SetContinuationRules(rules)
+	}
+	_, err = logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+
+	return mockMessageProducer.GetEntries()
+}
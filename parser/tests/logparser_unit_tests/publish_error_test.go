@@ -0,0 +1,69 @@
+package logparserunittests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+var errBrokerUnreachable = errors.New("broker unreachable")
+
+// entryFailingProducer publishes the run control messages, but fails to publish the entries.
+type entryFailingProducer struct {
+	mocks.MessageProducerMock
+}
+
+func (producer *entryFailingProducer) PublishEntry(line models.ParsedLogEntry) error {
+	return errBrokerUnreachable
+}
+
+// TestFailedRunStart checks that the run fails without parsing any files if its start could not be published.
+func TestFailedRunStart(t *testing.T) {
+	mockMessageProducer := mocks.MessageProducerMock{Err: errBrokerUnreachable}
+	downloader, err := filedownloader.NewLocalDownloader("./resources", false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+	runReport, err := logParser.ParseLogfiles()
+	if !errors.Is(err, errBrokerUnreachable) {
+		t.Fatalf("Expected the publishing error, got %v", err)
+	}
+
+	if runReport.Totals.LinesRead != 0 {
+		t.Fatalf("Expected no lines read, got %d", runReport.Totals.LinesRead)
+	}
+}
+
+// TestFailedPublishing checks that the files whose entries could not be published fail the run,
+// and the end of the run is still published.
+func TestFailedPublishing(t *testing.T) {
+	producer := entryFailingProducer{}
+	downloader := failingDownloader{fileNames: []string{"./resources/test_dc_main.log"}}
+
+	runProgress := progress.NewProgress()
+	logParser := logparser.NewLogParser(&downloader, &producer)
+	_, err := logParser.ParseSelectedLogfiles(context.Background(), nil, runProgress)
+	if err == nil {
+		t.Fatal("Expected an error for the entries that could not be published")
+	}
+
+	snapshot := runProgress.Snapshot()
+	if snapshot.FilesFailed != 1 || snapshot.FilesDone != 0 {
+		t.Fatalf("Expected 1 failed file, got %d failed and %d parsed files", snapshot.FilesFailed, snapshot.FilesDone)
+	}
+
+	if len(producer.RunControls) != 2 {
+		t.Fatalf("Expected the start and the end of the run, got %d run control messages", len(producer.RunControls))
+	}
+
+	if producer.RunControls[1].TotalEntries != 0 {
+		t.Fatalf("Expected no entries in the run end, got %d", producer.RunControls[1].TotalEntries)
+	}
+}
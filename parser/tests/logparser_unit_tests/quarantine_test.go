@@ -0,0 +1,178 @@
+package logparserunittests
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/quarantine"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+const (
+	noTimestampLine = "INFO    : <--[pod configuration]--(DB) pod_uid[1479]\n"
+	unknownWarnLine = "Wed Jun 10 09:18:30 2020 WARN    : Something unexpected happened (warner.cc::12)\n"
+	noLevelLine     = "Wed Jun 10 09:18:30 2020 this line has no log level\n"
+
+	malformedRoutingLine = "Wed Jun 10 09:18:30 2020 INFO    : Routing Table: Addr[0x0008] NextHop[0x0001] " +
+		"RouteCost[n/a] HopCount[0] WeakLink[0] ValidTime[240] (plc_manager.cc::1044)\n"
+	invalidDateLine = "Wed Jun 31 09:18:30 2020 INFO    : <--[pod configuration]--(DB) pod_uid[1479]\n"
+)
+
+func TestQuarantineRejectedLines(t *testing.T) {
+	rootDirectory, err := ioutil.TempDir("", "quarantine_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(rootDirectory)
+
+	logDirectory := filepath.Join(rootDirectory, "logs")
+	err = os.Mkdir(logDirectory, 0700)
+	utils.FailOnError(err, "Could not create log directory.")
+
+	// Without continuation rules, every line is parsed separately.
+	contents := followedINFOLine + noTimestampLine + followedVERBOSELine + "\n" + unknownWarnLine + noLevelLine
+	writeLogFile(filepath.Join(logDirectory, "dc_main.log"), contents, os.O_CREATE|os.O_WRONLY)
+
+	quarantineFilePath := filepath.Join(rootDirectory, "quarantine.ndjson")
+	sink := quarantine.NewFileSink(quarantineFilePath)
+	utils.FailOnError(sink.Open(), "Could not open quarantine file.")
+
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(logDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+	logParser.SetContinuationRules([]fileparser.ContinuationRule{})
+	logParser.SetQuarantineSink(sink)
+	_, err = logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+	utils.FailOnError(sink.Close(), "Could not close quarantine file.")
+
+	if len(mockMessageProducer.GetEntries()) != 1 {
+		t.Fatalf("Expected 1 entry, got %d entries.", len(mockMessageProducer.GetEntries()))
+	}
+
+	// The VERBOSE and the empty lines are not quarantined.
+	expectedLines := []models.RejectedLine{
+		{
+			Reason:       models.MissingTimestamp,
+			ReasonString: "MissingTimestamp",
+			Source: models.SourceLocation{
+				FileName:   "dc_main.log",
+				LineNumber: 2,
+				ByteOffset: int64(len(followedINFOLine)),
+				RawLine:    noTimestampLine[:len(noTimestampLine)-1],
+			},
+		},
+		{
+			Reason:       models.UnrecognisedWarn,
+			ReasonString: "UnrecognisedWarn",
+			Source: models.SourceLocation{
+				FileName:   "dc_main.log",
+				LineNumber: 5,
+				ByteOffset: int64(len(followedINFOLine) + len(noTimestampLine) + len(followedVERBOSELine) + 1),
+				RawLine:    unknownWarnLine[:len(unknownWarnLine)-1],
+			},
+		},
+		{
+			Reason:       models.MissingLogLevel,
+			ReasonString: "MissingLogLevel",
+			Source: models.SourceLocation{
+				FileName:   "dc_main.log",
+				LineNumber: 6,
+				ByteOffset: int64(len(contents) - len(noLevelLine)),
+				RawLine:    noLevelLine[:len(noLevelLine)-1],
+			},
+		},
+	}
+
+	actualLines := readQuarantineFile(quarantineFilePath)
+	if len(actualLines) != len(expectedLines) {
+		t.Fatalf("Expected %d quarantined lines, got %d.", len(expectedLines), len(actualLines))
+	}
+
+	for i := range expectedLines {
+		if actualLines[i] != expectedLines[i] {
+			t.Fatalf("Expected quarantined line %+v, got %+v", expectedLines[i], actualLines[i])
+		}
+	}
+}
+
+func TestQuarantineMalformedEntries(t *testing.T) {
+	rootDirectory, err := ioutil.TempDir("", "quarantine_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(rootDirectory)
+
+	logDirectory := filepath.Join(rootDirectory, "logs")
+	err = os.Mkdir(logDirectory, 0700)
+	utils.FailOnError(err, "Could not create log directory.")
+
+	// The lines after the malformed entries are still parsed.
+	contents := malformedRoutingLine + invalidDateLine + followedINFOLine
+	writeLogFile(filepath.Join(logDirectory, "dc_main.log"), contents, os.O_CREATE|os.O_WRONLY)
+
+	quarantineFilePath := filepath.Join(rootDirectory, "quarantine.ndjson")
+	sink := quarantine.NewFileSink(quarantineFilePath)
+	utils.FailOnError(sink.Open(), "Could not open quarantine file.")
+
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(logDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+	logParser.SetQuarantineSink(sink)
+	runReport, err := logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+	utils.FailOnError(sink.Close(), "Could not close quarantine file.")
+
+	if len(mockMessageProducer.GetEntries()) != 1 {
+		t.Fatalf("Expected 1 entry, got %d entries.", len(mockMessageProducer.GetEntries()))
+	}
+
+	if runReport.Totals.LinesQuarantined != 2 || runReport.Totals.LinesMalformed != 2 {
+		t.Fatalf("Expected 2 quarantined and 2 malformed lines, got %d and %d.",
+			runReport.Totals.LinesQuarantined, runReport.Totals.LinesMalformed)
+	}
+
+	expectedLines := []struct {
+		reason models.RejectReason
+		error  string
+	}{
+		{reason: models.MalformedEntry, error: `strconv.Atoi: parsing "n/a": invalid syntax`},
+		{reason: models.MalformedTimestamp, error: `parsing time "Wed Jun 31 09:18:30 2020": day out of range`},
+	}
+
+	actualLines := readQuarantineFile(quarantineFilePath)
+	if len(actualLines) != len(expectedLines) {
+		t.Fatalf("Expected %d quarantined lines, got %d.", len(expectedLines), len(actualLines))
+	}
+
+	for i, expectedLine := range expectedLines {
+		if actualLines[i].Reason != expectedLine.reason || actualLines[i].Error != expectedLine.error {
+			t.Fatalf("Expected quarantined line with reason %s and error %s, got %+v",
+				models.RejectReasonToString(expectedLine.reason), expectedLine.error, actualLines[i])
+		}
+	}
+}
+
+func readQuarantineFile(path string) []models.RejectedLine {
+	file, err := os.Open(path)
+	utils.FailOnError(err, "Could not open quarantine file.")
+	defer file.Close()
+
+	lines := []models.RejectedLine{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := models.RejectedLine{}
+		err = json.Unmarshal(scanner.Bytes(), &line)
+		utils.FailOnError(err, "Could not unmarshal quarantined line.")
+		lines = append(lines, line)
+	}
+
+	return lines
+}
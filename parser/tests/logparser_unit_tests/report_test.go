@@ -0,0 +1,114 @@
+package logparserunittests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/report"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+const (
+	unknownDCMessageLine = "Wed Jun 10 09:18:30 2020 INFO    : <--[unknown message]--(DB) pod_uid[1477] " +
+		"value[0] (smart_meter_cabinet_facade.cc::315)\n"
+	unknownINFOLine = "Wed Jun 10 09:18:30 2020 INFO    : Something else happened (facade.cc::12)\n"
+)
+
+// reportPublisherMock records the published reports.
+type reportPublisherMock struct {
+	reports []report.Report
+}
+
+func (m *reportPublisherMock) Publish(runReport report.Report) error {
+	m.reports = append(m.reports, runReport)
+	return nil
+}
+
+func TestParseReport(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "report_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(logDirectory)
+
+	writeLogFile(
+		filepath.Join(logDirectory, "dc_main.log"),
+		followedINFOLine+unknownDCMessageLine+followedVERBOSELine+"\n"+unknownINFOLine+noLevelLine+unknownWarnLine,
+		os.O_CREATE|os.O_WRONLY)
+	writeLogFile(
+		filepath.Join(logDirectory, "other.log"),
+		followedINFOLine+followedVERBOSELine,
+		os.O_CREATE|os.O_WRONLY)
+
+	publisher := reportPublisherMock{}
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(logDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+	logParser.SetContinuationRules([]fileparser.ContinuationRule{})
+	logParser.SetReportPublisher(&publisher)
+	runReport, err := logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+
+	if len(publisher.reports) != 1 || !reflect.DeepEqual(publisher.reports[0], runReport) {
+		t.Fatalf("Expected the returned report to be published once, got %d reports", len(publisher.reports))
+	}
+
+	if runReport.FinishedAt == nil || runReport.FinishedAt.Before(runReport.StartedAt) {
+		t.Fatalf("Expected the report to be finished after it started, got %v - %v", runReport.StartedAt, runReport.FinishedAt)
+	}
+
+	expectedDcMain := report.FileReport{
+		FileName: "dc_main.log",
+		Statistics: report.Statistics{
+			LinesRead:          7,
+			LinesFiltered:      1,
+			LinesQuarantined:   2,
+			EntriesParsed:      3,
+			EntriesByLevel:     map[string]int{"INFO": 3},
+			InfoEntries:        map[string]int{"DCMessage": 2, "UnknownInfoType": 1},
+			DCMessages:         map[string]int{"PodConfig": 1, "UnknownDCMessage": 1},
+			UnknownInfoEntries: 1,
+			UnknownDCMessages:  1,
+		},
+	}
+
+	expectedOther := report.FileReport{
+		FileName: "other.log",
+		Statistics: report.Statistics{
+			LinesRead:      2,
+			LinesFiltered:  1,
+			EntriesParsed:  1,
+			EntriesByLevel: map[string]int{"INFO": 1},
+			InfoEntries:    map[string]int{"DCMessage": 1},
+			DCMessages:     map[string]int{"PodConfig": 1},
+		},
+	}
+
+	expectedTotals := report.Statistics{
+		LinesRead:          9,
+		LinesFiltered:      2,
+		LinesQuarantined:   2,
+		EntriesParsed:      4,
+		EntriesByLevel:     map[string]int{"INFO": 4},
+		InfoEntries:        map[string]int{"DCMessage": 3, "UnknownInfoType": 1},
+		DCMessages:         map[string]int{"PodConfig": 2, "UnknownDCMessage": 1},
+		UnknownInfoEntries: 1,
+		UnknownDCMessages:  1,
+	}
+
+	expectedFiles := []report.FileReport{expectedDcMain, expectedOther}
+	if !reflect.DeepEqual(runReport.Files, expectedFiles) {
+		t.Fatalf("Expected file reports %+v, got %+v", expectedFiles, runReport.Files)
+	}
+
+	if !reflect.DeepEqual(runReport.Totals, expectedTotals) {
+		t.Fatalf("Expected totals %+v, got %+v", expectedTotals, runReport.Totals)
+	}
+}
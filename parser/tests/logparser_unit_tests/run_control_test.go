@@ -0,0 +1,79 @@
+package logparserunittests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+// TestRunControlMessages checks that a run is started and ended with run control messages,
+// that the run end contains the number of published entries, and that the entries carry the ID of the run.
+func TestRunControlMessages(t *testing.T) {
+	const logFileName = "./resources/test_dc_main.log"
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	mockFileDownloader := mocks.MockFileDownloader{FileNameToDownload: logFileName}
+
+	logParser := logparser.NewLogParser(&mockFileDownloader, &mockMessageProducer)
+	_, err := logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+
+	if len(mockMessageProducer.RunControls) != 2 {
+		t.Fatalf("Expected a run start and a run end, got %d run control messages", len(mockMessageProducer.RunControls))
+	}
+
+	runStart := mockMessageProducer.RunControls[0]
+	runEnd := mockMessageProducer.RunControls[1]
+	if runStart.ControlType != models.RunStart || runEnd.ControlType != models.RunEnd {
+		t.Fatalf("Expected a run start and a run end, got %s and %s",
+			models.RunControlTypeToString(runStart.ControlType), models.RunControlTypeToString(runEnd.ControlType))
+	}
+
+	if runStart.RunID == "" || runStart.RunID != runEnd.RunID {
+		t.Errorf("Expected the same run ID in the run start and the run end, got %q and %q", runStart.RunID, runEnd.RunID)
+	}
+
+	if len(runStart.FileNames) != 1 || runStart.FileNames[0] != logFileName {
+		t.Errorf("Expected file names [%s], got %v", logFileName, runStart.FileNames)
+	}
+
+	expectedEntryCount := len(mockMessageProducer.Entries)
+	if runEnd.TotalEntries != expectedEntryCount || runEnd.EntryCounts[logFileName] != expectedEntryCount {
+		t.Errorf("Expected %d entries in the run end, got %s", expectedEntryCount, runEnd.Serialize())
+	}
+
+	if len(mockMessageProducer.EntryRunIDs) != 1 || mockMessageProducer.EntryRunIDs[runStart.RunID] != expectedEntryCount {
+		t.Errorf("Expected every entry to carry run ID %s, got %v", runStart.RunID, mockMessageProducer.EntryRunIDs)
+	}
+}
+
+// TestJobRunID checks that the ID of the run is not replaced if it is set in the progress, eg.: to the ID of a job.
+func TestJobRunID(t *testing.T) {
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	mockFileDownloader := mocks.MockFileDownloader{FileNameToDownload: "./resources/test_plc_manager.log"}
+
+	runProgress := progress.NewProgress()
+	runProgress.SetRunID("job-1")
+
+	logParser := logparser.NewLogParser(&mockFileDownloader, &mockMessageProducer)
+	runReport, err := logParser.ParseSelectedLogfiles(context.Background(), nil, runProgress)
+	utils.FailOnError(err, "Could not parse log files.")
+
+	if runReport.RunID != "job-1" {
+		t.Errorf("Expected run ID job-1 in the report, got %q", runReport.RunID)
+	}
+
+	for _, runControl := range mockMessageProducer.RunControls {
+		if runControl.RunID != "job-1" {
+			t.Errorf("Expected run ID job-1 in the run control messages, got %q", runControl.RunID)
+		}
+	}
+
+	if mockMessageProducer.EntryRunIDs["job-1"] != len(mockMessageProducer.Entries) {
+		t.Errorf("Expected every entry to carry run ID job-1, got %v", mockMessageProducer.EntryRunIDs)
+	}
+}
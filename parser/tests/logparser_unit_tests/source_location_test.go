@@ -0,0 +1,79 @@
+package logparserunittests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/checkpoint"
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+func TestSourceLocationOfIncrementallyParsedEntries(t *testing.T) {
+	rootDirectory, err := ioutil.TempDir("", "source_location_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(rootDirectory)
+
+	logDirectory := filepath.Join(rootDirectory, "logs")
+	err = os.Mkdir(logDirectory, 0700)
+	utils.FailOnError(err, "Could not create log directory.")
+
+	logFilePath := filepath.Join(logDirectory, "dc_main.log")
+	writeLogFile(logFilePath, followedINFOLine+followedVERBOSELine, os.O_CREATE|os.O_WRONLY)
+
+	checkpointFilePath := filepath.Join(rootDirectory, "checkpoints.json")
+	entries := parseWithRawLines(logDirectory, checkpointFilePath)
+	assertSourceLocation(t, entries, 0, models.SourceLocation{
+		FileName:   "dc_main.log",
+		LineNumber: 1,
+		ByteOffset: 0,
+		RawLine:    strings.TrimSuffix(followedINFOLine, "\n"),
+	})
+
+	// The line numbers and offsets of the appended lines continue from the checkpoint.
+	writeLogFile(logFilePath, followedVERBOSELine+followedINFOLine, os.O_APPEND|os.O_WRONLY)
+	entries = parseWithRawLines(logDirectory, checkpointFilePath)
+	assertSourceLocation(t, entries, 0, models.SourceLocation{
+		FileName:   "dc_main.log",
+		LineNumber: 4,
+		ByteOffset: int64(len(followedINFOLine) + 2*len(followedVERBOSELine)),
+		RawLine:    strings.TrimSuffix(followedINFOLine, "\n"),
+	})
+}
+
+func parseWithRawLines(logDirectory string, checkpointFilePath string) []models.ParsedLogEntry {
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(logDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+	checkpointStore, err := checkpoint.NewFileStore(checkpointFilePath)
+	utils.FailOnError(err, "Could not load checkpoints.")
+
+	logParser, err := logparser.NewIncrementalLogParser(downloader, &mockMessageProducer, checkpointStore, false)
+	utils.FailOnError(err, "Could not create log parser.")
+	logParser.SetIncludeRawLines(true)
+	_, err = logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+
+	return mockMessageProducer.GetEntries()
+}
+
+func assertSourceLocation(
+	t *testing.T,
+	entries []models.ParsedLogEntry,
+	index int,
+	expected models.SourceLocation,
+) {
+	if len(entries) <= index {
+		t.Fatalf("Expected at least %d entries, got %d entries.", index+1, len(entries))
+	}
+
+	if entries[index].Source != expected {
+		t.Fatalf("Expected source location %+v, got %+v", expected, entries[index].Source)
+	}
+}
@@ -0,0 +1,67 @@
+package logparserunittests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+func TestParseStateChange(t *testing.T) {
+	line := "Wed Jun 10 09:18:30 2020 VERBOSE : SMC[dc18-smc3] changing state, new state[3038] ((null)::-1225668530)"
+
+	expectedEntry := &models.ParsedLogEntry{
+		Level:     "VERBOSE",
+		Timestamp: time.Date(2020, time.June, 10, 9, 18, 30, 0, time.UTC),
+		InfoParams: &models.InfoParams{
+			EntryType: models.SmcStateChange,
+			StateChange: &models.SmcStateChangeParams{
+				SmcUID: "dc18-smc3",
+				State:  3038,
+				Flags: []string{
+					"AddressKnown", "ConfigurationRead", "PodsConfigured", "ConnectionRequested",
+					"ServiceLevelSet", "IndexRead", "ConsumptionRead", "SettingsSent", "ProfileRead",
+				},
+			},
+		},
+	}
+
+	actualEntry, err := fileparser.ParseVerboseLine(line, fileparser.VerboseEntries{StateChanges: true})
+	if err != nil {
+		t.Fatalf("Could not parse the state change entry: %s", err)
+	}
+
+	if !reflect.DeepEqual(actualEntry, expectedEntry) {
+		t.Fatalf("Expected entry %+v, got %+v", expectedEntry.InfoParams.StateChange, actualEntry)
+	}
+
+	// The state change entries are filtered out by the default parsers.
+	if entry := fileparser.ParseLine(line); entry != nil {
+		t.Fatalf("Expected the VERBOSE line to be filtered out, got %+v", entry)
+	}
+
+	// The state change entries are only parsed if they are selected.
+	if entry, _ := fileparser.ParseVerboseLine(line, fileparser.VerboseEntries{TaskLaunches: true}); entry != nil {
+		t.Fatalf("Expected the state change entry not to be parsed, got %+v", entry)
+	}
+
+	// Other VERBOSE entries are not parsed.
+	otherLine := "Wed Jun 10 09:18:30 2020 VERBOSE : Received packet ((null)::-1225668530)"
+	if entry, _ := fileparser.ParseVerboseLine(otherLine, fileparser.VerboseEntries{StateChanges: true}); entry != nil {
+		t.Fatalf("Expected the VERBOSE line not to be parsed, got %+v", entry)
+	}
+}
+
+func TestDecodeSmcStateFlags(t *testing.T) {
+	if flags := models.DecodeSmcStateFlags(0); len(flags) != 0 {
+		t.Fatalf("Expected no flags, got %v", flags)
+	}
+
+	expectedFlags := []string{"Joined", "Connected", "Bit12"}
+	actualFlags := models.DecodeSmcStateFlags(1 + 32 + 4096)
+	if !reflect.DeepEqual(actualFlags, expectedFlags) {
+		t.Fatalf("Expected flags %v, got %v", expectedFlags, actualFlags)
+	}
+}
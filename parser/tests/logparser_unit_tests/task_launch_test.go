@@ -0,0 +1,47 @@
+package logparserunittests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+func TestParseTaskLaunch(t *testing.T) {
+	line := "Wed Jun 10 09:18:39 2020 VERBOSE : Launch Task name[update_connection_task] type[connect] " +
+		"name[update_connection_task] smc_uid[dc18-smc3] uid[67] priority[4294967293] retry[1] " +
+		"creation_time[Wed Jun 10 09:18:38 2020] on thread 2968499248 (priority_task_scheduler.cc::131)"
+
+	expectedEntry := &models.ParsedLogEntry{
+		Level:     "VERBOSE",
+		Timestamp: time.Date(2020, time.June, 10, 9, 18, 39, 0, time.UTC),
+		InfoParams: &models.InfoParams{
+			EntryType: models.TaskLaunch,
+			TaskLaunch: &models.TaskLaunchParams{
+				Name:     "update_connection_task",
+				Type:     "connect",
+				SmcUID:   "dc18-smc3",
+				UID:      67,
+				Priority: 4294967293,
+				Retry:    1,
+				Creation: time.Date(2020, time.June, 10, 9, 18, 38, 0, time.UTC),
+				Thread:   "2968499248",
+			},
+		},
+	}
+
+	actualEntry, err := fileparser.ParseVerboseLine(line, fileparser.VerboseEntries{TaskLaunches: true})
+	if err != nil {
+		t.Fatalf("Could not parse the task launch entry: %s", err)
+	}
+
+	if !reflect.DeepEqual(actualEntry, expectedEntry) {
+		t.Fatalf("Expected entry %+v, got %+v", expectedEntry.InfoParams.TaskLaunch, actualEntry)
+	}
+
+	if entry, _ := fileparser.ParseVerboseLine(line, fileparser.VerboseEntries{StateChanges: true}); entry != nil {
+		t.Fatalf("Expected the task launch entry not to be parsed, got %+v", entry)
+	}
+}
@@ -0,0 +1,84 @@
+package logparserunittests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/timezone"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+const (
+	settingsLine = "Wed Jun 10 09:18:29 2020 INFO    : --[settings]-->(DB) dc_uid[dc18] locality[Tanambao Daoud] " +
+		"region[Madagascar] timezone[Indian/Antananarivo] (distribution_controller_initializer.cc::109)\n"
+	localTaskLaunchLine = "Wed Jun 10 09:18:39 2020 VERBOSE : Launch Task name[update_connection_task] " +
+		"type[connect] name[update_connection_task] smc_uid[dc18-smc3] uid[67] priority[4294967293] retry[1] " +
+		"creation_time[Wed Jun 10 09:18:38 2020] on thread 2968499248 (priority_task_scheduler.cc::131)\n"
+)
+
+func TestSettingsTimezone(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "timezone_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(logDirectory)
+
+	writeLogFile(
+		filepath.Join(logDirectory, "dc_main.log"),
+		localTaskLaunchLine+settingsLine+localTaskLaunchLine,
+		os.O_CREATE|os.O_WRONLY)
+
+	// The entries before the settings entry are in the default timezone (UTC),
+	// the entries after it are in the timezone of the settings (UTC+3).
+	entries := parseTimezoneTestFiles(logDirectory, timezone.NewResolver(nil, nil))
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d entries.", len(entries))
+	}
+
+	assertTaskLaunchTimes(t, entries[0], time.Date(2020, time.June, 10, 9, 18, 39, 0, time.UTC))
+	assertTaskLaunchTimes(t, entries[2], time.Date(2020, time.June, 10, 6, 18, 39, 0, time.UTC))
+
+	// The configured timezone of the file takes precedence over the timezone of the settings.
+	fileLocations, err := timezone.ParseFileLocations("dc_main.log=Europe/Budapest")
+	utils.FailOnError(err, "Could not parse file locations.")
+	entries = parseTimezoneTestFiles(logDirectory, timezone.NewResolver(nil, fileLocations))
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d entries.", len(entries))
+	}
+
+	assertTaskLaunchTimes(t, entries[0], time.Date(2020, time.June, 10, 7, 18, 39, 0, time.UTC))
+	assertTaskLaunchTimes(t, entries[2], time.Date(2020, time.June, 10, 7, 18, 39, 0, time.UTC))
+}
+
+func parseTimezoneTestFiles(logDirectory string, resolver *timezone.Resolver) []models.ParsedLogEntry {
+	mockMessageProducer := mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	downloader, err := filedownloader.NewLocalDownloader(logDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+
+	logParser := logparser.NewLogParser(downloader, &mockMessageProducer)
+	logParser.SetVerboseEntries(fileparser.VerboseEntries{TaskLaunches: true})
+	logParser.SetTimezoneResolver(resolver)
+	_, err = logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+
+	return mockMessageProducer.GetEntries()
+}
+
+// assertTaskLaunchTimes checks the timestamp of a task launch entry, the task was created a second before the launch.
+func assertTaskLaunchTimes(t *testing.T, entry models.ParsedLogEntry, expectedTimestamp time.Time) {
+	if !entry.Timestamp.Equal(expectedTimestamp) || entry.Timestamp.Location() != time.UTC {
+		t.Fatalf("Expected timestamp %v, got %v", expectedTimestamp, entry.Timestamp)
+	}
+
+	expectedCreation := expectedTimestamp.Add(-time.Second)
+	creation := entry.InfoParams.TaskLaunch.Creation
+	if !creation.Equal(expectedCreation) || creation.Location() != time.UTC {
+		t.Fatalf("Expected creation time %v, got %v", expectedCreation, creation)
+	}
+}
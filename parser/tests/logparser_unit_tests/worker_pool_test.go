@@ -0,0 +1,128 @@
+package logparserunittests
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/internal/filedownloader"
+	"github.com/kozgot/go-log-processing/parser/internal/logparser"
+	"github.com/kozgot/go-log-processing/parser/internal/rabbitmq"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/parser/tests/mocks"
+)
+
+// countingDownloader records the maximum number of files that were open at the same time.
+type countingDownloader struct {
+	*filedownloader.LocalDownloader
+	mutex       sync.Mutex
+	open        int
+	maxOpen     int
+	downloadLog []string
+}
+
+func (downloader *countingDownloader) DownloadFile(fileName string) (io.ReadCloser, error) {
+	readCloser, err := downloader.LocalDownloader.DownloadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	downloader.mutex.Lock()
+	defer downloader.mutex.Unlock()
+
+	downloader.open++
+	if downloader.open > downloader.maxOpen {
+		downloader.maxOpen = downloader.open
+	}
+	downloader.downloadLog = append(downloader.downloadLog, fileName)
+
+	return &countingReadCloser{ReadCloser: readCloser, downloader: downloader}, nil
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	downloader *countingDownloader
+}
+
+func (readCloser *countingReadCloser) Close() error {
+	readCloser.downloader.mutex.Lock()
+	defer readCloser.downloader.mutex.Unlock()
+
+	readCloser.downloader.open--
+	return readCloser.ReadCloser.Close()
+}
+
+// channelProducerMock creates a separate mock producer for each worker.
+type channelProducerMock struct {
+	mocks.MessageProducerMock
+	mutex            sync.Mutex
+	channelProducers []*mocks.MessageProducerMock
+}
+
+func (m *channelProducerMock) NewChannelProducer() (rabbitmq.MessageProducer, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	producer := &mocks.MessageProducerMock{Entries: []models.ParsedLogEntry{}}
+	m.channelProducers = append(m.channelProducers, producer)
+	return producer, nil
+}
+
+func TestLogParserWorkerPool(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "worker_pool_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(logDirectory)
+
+	testLogFileBytes, err := ioutil.ReadFile("./resources/test_dc_main.log")
+	utils.FailOnError(err, "Could not read test log file.")
+
+	fileCount := 10
+	for i := 0; i < fileCount; i++ {
+		logFilePath := filepath.Join(logDirectory, "dc_main_"+strconv.Itoa(i)+".log")
+		err = ioutil.WriteFile(logFilePath, testLogFileBytes, 0600)
+		utils.FailOnError(err, "Could not write test log file.")
+	}
+
+	localDownloader, err := filedownloader.NewLocalDownloader(logDirectory, false, nil, nil)
+	utils.FailOnError(err, "Could not create file downloader.")
+
+	downloader := &countingDownloader{LocalDownloader: localDownloader}
+	producer := &channelProducerMock{}
+
+	logParser := logparser.NewLogParser(downloader, producer)
+	err = logParser.SetWorkerCount(3)
+	utils.FailOnError(err, "Could not set worker count.")
+	_, err = logParser.ParseLogfiles()
+	utils.FailOnError(err, "Could not parse log files.")
+
+	if downloader.maxOpen > 3 {
+		t.Fatalf("Expected at most 3 open files, got %d", downloader.maxOpen)
+	}
+
+	if len(downloader.downloadLog) != fileCount {
+		t.Fatalf("Expected %d downloaded files, got %d", fileCount, len(downloader.downloadLog))
+	}
+
+	if len(producer.channelProducers) != 3 {
+		t.Fatalf("Expected 3 channel producers, got %d", len(producer.channelProducers))
+	}
+
+	if len(producer.GetEntries()) != 0 {
+		t.Fatalf("Expected no entries published on the shared producer, got %d", len(producer.GetEntries()))
+	}
+
+	// The number of relevant lines in the provided test log file is 40.
+	entryCount := 0
+	for _, channelProducer := range producer.channelProducers {
+		entryCount += len(channelProducer.GetEntries())
+	}
+
+	if entryCount != 40*fileCount {
+		t.Fatalf("Expected %d entries, got %d", 40*fileCount, entryCount)
+	}
+}
@@ -2,7 +2,6 @@ package mocks
 
 import (
 	"io"
-	"log"
 	"os"
 )
 
@@ -10,15 +9,10 @@ type MockFileDownloader struct {
 	FileNameToDownload string
 }
 
-func (mock *MockFileDownloader) ListFileNames() []string {
-	return []string{mock.FileNameToDownload}
+func (mock *MockFileDownloader) ListFileNames() ([]string, error) {
+	return []string{mock.FileNameToDownload}, nil
 }
 
-func (mock *MockFileDownloader) DownloadFile(fileName string) io.ReadCloser {
-	f, err := os.Open(fileName)
-	if err != nil {
-		log.Fatal("Could not open given log file. " + fileName)
-	}
-
-	return f
+func (mock *MockFileDownloader) DownloadFile(fileName string) (io.ReadCloser, error) {
+	return os.Open(fileName)
 }
@@ -1,23 +1,65 @@
 package mocks
 
-import "github.com/kozgot/go-log-processing/parser/pkg/models"
+import (
+	"sync"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
 
 // MessageProducerMock mocks a rabbitmq message producer, implements the MessageProducer interface.
 type MessageProducerMock struct {
-	Entries []models.ParsedLogEntry
+	Entries     []models.ParsedLogEntry
+	RunControls []models.RunControl
+
+	// EntryRunIDs contains the number of published entries by run ID.
+	// The run IDs are generated, so they are removed from the saved entries to compare them to the expected entries.
+	EntryRunIDs map[string]int
+
+	// Err is returned by the publish methods instead of saving the messages if it is set,
+	// eg.: to simulate an unreachable broker.
+	Err   error
+	mutex sync.Mutex
 }
 
-func (m *MessageProducerMock) PublishStringMessage(indexName string) {
-	// NOOP
+func (m *MessageProducerMock) PublishRunControl(control models.RunControl) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+
+	m.RunControls = append(m.RunControls, control)
+	return nil
 }
 
-func (m *MessageProducerMock) PublishEntry(line models.ParsedLogEntry) {
+func (m *MessageProducerMock) PublishEntry(line models.ParsedLogEntry) error {
 	// Save sent entries to be able to validate them in the test.
+	// Files are parsed concurrently, so entries can be published from multiple goroutines.
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+
+	if m.EntryRunIDs == nil {
+		m.EntryRunIDs = make(map[string]int)
+	}
+
+	m.EntryRunIDs[line.RunID]++
+	line.RunID = ""
 	m.Entries = append(m.Entries, line)
+	return nil
 }
 
-func (m *MessageProducerMock) OpenChannelAndConnection() {
-	// NOOP
+// GetEntries returns a copy of the entries published so far, it is safe to call while entries are published.
+func (m *MessageProducerMock) GetEntries() []models.ParsedLogEntry {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]models.ParsedLogEntry{}, m.Entries...)
+}
+
+func (m *MessageProducerMock) OpenChannelAndConnection() error {
+	return nil
 }
 
 func (m *MessageProducerMock) CloseChannelAndConnection() {
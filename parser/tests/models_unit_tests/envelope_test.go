@@ -0,0 +1,122 @@
+package modelsunittests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+func TestOpenEnvelope(t *testing.T) {
+	entry := models.ParsedLogEntry{
+		Timestamp: time.Date(2020, time.June, 10, 9, 18, 39, 0, time.UTC),
+		Level:     "INFO",
+		Source:    models.SourceLocation{FileName: "dc_main.log", LineNumber: 3},
+	}
+
+	envelope := models.NewEnvelope("parser@test", "run-1", entry.Serialize())
+	openedEnvelope, err := models.OpenEnvelope(envelope.Serialize())
+	if err != nil {
+		t.Fatalf("Could not open envelope: %s", err)
+	}
+
+	if openedEnvelope.SchemaVersion != models.CurrentSchemaVersion ||
+		openedEnvelope.ProducerID != "parser@test" || openedEnvelope.RunID != "run-1" {
+		t.Errorf("Unexpected envelope: %+v", openedEnvelope)
+	}
+
+	assertEntry(t, openedEnvelope, entry)
+
+	if _, err := openedEnvelope.RunControl(); err == nil {
+		t.Errorf("Expected an error for the run control message of an entry envelope")
+	}
+
+	runEnd := models.NewRunEnd("run-1", []string{"dc_main.log", "plc_manager.log"},
+		map[string]int{"dc_main.log": 3, "plc_manager.log": 2})
+	runEndEnvelope := models.NewRunControlEnvelope("parser@test", runEnd)
+	openedEnvelope, err = models.OpenEnvelope(runEndEnvelope.Serialize())
+	if err != nil {
+		t.Fatalf("Could not open envelope: %s", err)
+	}
+
+	openedRunEnd, err := openedEnvelope.RunControl()
+	if err != nil {
+		t.Fatalf("Could not deserialize run control message: %s", err)
+	}
+
+	if openedEnvelope.RunID != "run-1" || openedRunEnd.ControlType != models.RunEnd ||
+		openedRunEnd.TotalEntries != 5 || len(openedRunEnd.FileNames) != 2 {
+		t.Errorf("Unexpected run end: %s", openedRunEnd.Serialize())
+	}
+}
+
+func TestOpenLegacyMessages(t *testing.T) {
+	entry := models.ParsedLogEntry{Level: "WARN", Source: models.SourceLocation{FileName: "plc_manager.log"}}
+
+	openedEnvelope, err := models.OpenEnvelope(entry.Serialize())
+	if err != nil {
+		t.Fatalf("Could not upgrade legacy entry: %s", err)
+	}
+
+	if openedEnvelope.SchemaVersion != models.CurrentSchemaVersion {
+		t.Errorf("Expected legacy entry to be upgraded to version %d, got %d",
+			models.CurrentSchemaVersion, openedEnvelope.SchemaVersion)
+	}
+
+	assertEntry(t, openedEnvelope, entry)
+
+	openedEnvelope, err = models.OpenEnvelope([]byte("END"))
+	if err != nil {
+		t.Fatalf("Could not upgrade legacy string message: %s", err)
+	}
+
+	if message, ok := openedEnvelope.StringMessage(); !ok || message != "END" {
+		t.Errorf("Expected string message END, got %s", openedEnvelope.Payload)
+	}
+
+	// Envelopes published before the run control messages signal the end of a run with a string message.
+	openedEnvelope, err = models.OpenEnvelope([]byte(`{"SchemaVersion":1,"ProducerID":"parser@test","Payload":"END"}`))
+	if err != nil {
+		t.Fatalf("Could not upgrade string message envelope: %s", err)
+	}
+
+	if openedEnvelope.SchemaVersion != models.CurrentSchemaVersion || openedEnvelope.PayloadType != models.EntryPayload {
+		t.Errorf("Unexpected upgraded envelope: %+v", openedEnvelope)
+	}
+
+	if message, ok := openedEnvelope.StringMessage(); !ok || message != "END" {
+		t.Errorf("Expected string message END, got %s", openedEnvelope.Payload)
+	}
+}
+
+func TestRejectUnsupportedEnvelopes(t *testing.T) {
+	invalidMessages := []struct {
+		body        string
+		unsupported bool
+	}{
+		{body: `{"SchemaVersion":3,"ProducerID":"parser@test","Payload":{}}`, unsupported: true},
+		{body: `{"SchemaVersion":2,"ProducerID":"parser@test"}`},
+		{body: `{"SchemaVersion":1,`},
+	}
+
+	for _, invalidMessage := range invalidMessages {
+		_, err := models.OpenEnvelope([]byte(invalidMessage.body))
+		if err == nil {
+			t.Errorf("Expected an error for message %s", invalidMessage.body)
+			continue
+		}
+
+		if errors.Is(err, models.ErrUnsupportedSchemaVersion) != invalidMessage.unsupported {
+			t.Errorf("Unexpected error for message %s: %s", invalidMessage.body, err)
+		}
+	}
+}
+
+func assertEntry(t *testing.T, envelope *models.Envelope, expectedEntry models.ParsedLogEntry) {
+	actualEntry := models.ParsedLogEntry{}
+	actualEntry.FromJSON(envelope.Payload)
+	if string(actualEntry.Serialize()) != string(expectedEntry.Serialize()) {
+		t.Errorf("Expected entry %s, got %s", expectedEntry.Serialize(), actualEntry.Serialize())
+	}
+}
@@ -0,0 +1,27 @@
+package modelsunittests
+
+import (
+	"testing"
+
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+func TestSourceDC(t *testing.T) {
+	tests := []struct {
+		fileName string
+		expected string
+	}{
+		{fileName: "dc_main.log", expected: ""},
+		{fileName: "dc18/dc_main.log", expected: "dc18"},
+		{fileName: "dc18/dc_main.log.1", expected: "dc18"},
+		{fileName: "dc18/export.tar.gz/dc_main.log", expected: "dc18"},
+		{fileName: "export.zip/dc18/dc_main.log", expected: "dc18"},
+		{fileName: "site1/dc19/plc_manager.log", expected: "site1/dc19"},
+	}
+
+	for index, test := range tests {
+		if actual := models.SourceDC(test.fileName); actual != test.expected {
+			t.Fatalf("Expected %q, got %q in test case no. %d", test.expected, actual, index)
+		}
+	}
+}
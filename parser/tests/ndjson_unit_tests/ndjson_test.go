@@ -0,0 +1,108 @@
+package ndjsonunittests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/fileparser"
+	"github.com/kozgot/go-log-processing/parser/internal/ndjson"
+	"github.com/kozgot/go-log-processing/parser/internal/progress"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+)
+
+const testLog = "Wed Jun 10 09:18:39 2020 INFO    : <--[last index]--(DB) pod_uid[1478] time[1591776000] " +
+	"value[14989] (dc_main.cc::1011)\n" +
+	"Wed Jun 10 09:44:30 2020 WARN    : Timeout protocol[plc-udp] url[fe80::4021:ff:fe00:9:61616] " +
+	"(plc_bridge_connector.cc::227)\n" +
+	"Wed Jun 10 10:02:11 2020 ERROR   : error_code[65] message[PLC socket receive error] severity[2] " +
+	"description[n/a] source[]  (dlms_tcp_socket_connector_adapter.h::85)\n"
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ndjson.ParseFilter("info, warn", "2020-06-10T09:00:00Z", "2020-06-10T10:00:00Z")
+	if err != nil {
+		t.Fatalf("Could not parse filter: %s", err)
+	}
+
+	expectedLevels := []string{"INFO", "WARN"}
+	if strings.Join(filter.Levels, ",") != strings.Join(expectedLevels, ",") {
+		t.Errorf("Expected levels %v, got %v.", expectedLevels, filter.Levels)
+	}
+
+	if !filter.From.Equal(time.Date(2020, time.June, 10, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected start of the time range: %s", filter.From)
+	}
+
+	invalidFilters := [][]string{
+		{"", "10/06/2020", ""},
+		{"", "", "2020-06-10"},
+		{"", "2020-06-10T10:00:00Z", "2020-06-10T09:00:00Z"},
+	}
+	for _, invalidFilter := range invalidFilters {
+		if _, err := ndjson.ParseFilter(invalidFilter[0], invalidFilter[1], invalidFilter[2]); err == nil {
+			t.Errorf("Expected an error for filter %v.", invalidFilter)
+		}
+	}
+}
+
+func TestEntryWriter(t *testing.T) {
+	testCases := []struct {
+		name           string
+		filter         ndjson.Filter
+		expectedLevels []string
+	}{
+		{
+			name:           "No filter",
+			filter:         ndjson.Filter{},
+			expectedLevels: []string{"INFO", "WARN", "ERROR"},
+		},
+		{
+			name:           "Log levels",
+			filter:         ndjson.Filter{Levels: []string{"warn", "ERROR"}},
+			expectedLevels: []string{"WARN", "ERROR"},
+		},
+		{
+			name: "Time range",
+			filter: ndjson.Filter{
+				From: time.Date(2020, time.June, 10, 9, 18, 39, 0, time.UTC),
+				To:   time.Date(2020, time.June, 10, 10, 2, 11, 0, time.UTC),
+			},
+			expectedLevels: []string{"INFO", "WARN"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		output := bytes.Buffer{}
+		entryWriter := ndjson.NewEntryWriter(&output, testCase.filter)
+		fileParser := fileparser.NewFileParser(context.Background(), entryWriter, progress.NewProgress())
+		err := fileParser.ParseSingleFile(ioutil.NopCloser(strings.NewReader(testLog)), "dc_main.log")
+		utils.FailOnError(err, "Could not parse log file.")
+		utils.FailOnError(entryWriter.Flush(), "Could not flush parsed entries.")
+
+		levels := []string{}
+		scanner := bufio.NewScanner(&output)
+		for scanner.Scan() {
+			entry := models.ParsedLogEntry{}
+			entry.FromJSON(scanner.Bytes())
+			levels = append(levels, entry.Level)
+
+			if entry.Source.FileName != "dc_main.log" {
+				t.Errorf("%s: unexpected source file name: %s", testCase.name, entry.Source.FileName)
+			}
+		}
+
+		if strings.Join(levels, ",") != strings.Join(testCase.expectedLevels, ",") {
+			t.Errorf("%s: expected entries with levels %v, got %v.", testCase.name, testCase.expectedLevels, levels)
+		}
+
+		if entryWriter.EntriesWritten() != len(testCase.expectedLevels) {
+			t.Errorf("%s: expected %d written entries, got %d.",
+				testCase.name, len(testCase.expectedLevels), entryWriter.EntriesWritten())
+		}
+	}
+}
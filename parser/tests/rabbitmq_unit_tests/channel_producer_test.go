@@ -0,0 +1,59 @@
+package rabbitmqunittests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/rabbitmq"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/streadway/amqp"
+)
+
+func TestChannelProducer(t *testing.T) {
+	deliveries := make(chan amqp.Delivery, 10)
+	producer := rabbitmq.NewChannelProducer(deliveries)
+	utils.FailOnError(producer.OpenChannelAndConnection(), "Could not open producer.")
+
+	entry := models.ParsedLogEntry{
+		Timestamp: time.Date(2020, time.June, 10, 9, 18, 39, 0, time.UTC),
+		Level:     "INFO",
+		Source:    models.SourceLocation{FileName: "dc_main.log", LineNumber: 1},
+	}
+	utils.FailOnError(producer.PublishEntry(entry), "Could not publish entry.")
+	err := producer.PublishRunControl(models.NewRunEnd("run-1", []string{"dc_main.log"}, map[string]int{"dc_main.log": 1}))
+	utils.FailOnError(err, "Could not publish run control.")
+	producer.CloseChannelAndConnection()
+
+	entryDelivery := <-deliveries
+	envelope, err := models.OpenEnvelope(entryDelivery.Body)
+	if err != nil {
+		t.Fatalf("Could not open the envelope of the entry: %s", err)
+	}
+
+	actualEntry := models.ParsedLogEntry{}
+	actualEntry.FromJSON(envelope.Payload)
+	if string(actualEntry.Serialize()) != string(entry.Serialize()) {
+		t.Errorf("Expected entry %s, got %s", entry.Serialize(), actualEntry.Serialize())
+	}
+
+	// The consumers acknowledge the deliveries the same way as the deliveries of RabbitMQ.
+	if err := entryDelivery.Ack(false); err != nil {
+		t.Errorf("Could not acknowledge delivery: %s", err)
+	}
+
+	endDelivery := <-deliveries
+	envelope, err = models.OpenEnvelope(endDelivery.Body)
+	if err != nil {
+		t.Fatalf("Could not open the envelope of the run end: %s", err)
+	}
+
+	runEnd, err := envelope.RunControl()
+	if err != nil || runEnd.ControlType != models.RunEnd || runEnd.TotalEntries != 1 || envelope.RunID != "run-1" {
+		t.Errorf("Expected the end of run-1 with 1 entry, got %s", envelope.Serialize())
+	}
+
+	if entryDelivery.DeliveryTag != 1 || endDelivery.DeliveryTag != 2 {
+		t.Errorf("Expected delivery tags 1 and 2, got %d and %d", entryDelivery.DeliveryTag, endDelivery.DeliveryTag)
+	}
+}
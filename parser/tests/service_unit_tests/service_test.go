@@ -0,0 +1,203 @@
+package serviceunittests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/jobs"
+	"github.com/kozgot/go-log-processing/parser/internal/utils"
+	"github.com/kozgot/go-log-processing/parser/pkg/service"
+	"github.com/streadway/amqp"
+)
+
+// TestInProcessJob submits a job to the API of an in-process parser service,
+// and checks that the entries of the local log files are sent to the entries channel.
+func TestInProcessJob(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "service_test")
+	utils.FailOnError(err, "Could not create temporary directory.")
+	defer os.RemoveAll(logDirectory)
+
+	testLogFileBytes, err := ioutil.ReadFile("../logparser_unit_tests/resources/test_dc_main.log")
+	utils.FailOnError(err, "Could not read test log file.")
+
+	err = ioutil.WriteFile(filepath.Join(logDirectory, "dc_main.log"), testLogFileBytes, 0600)
+	utils.FailOnError(err, "Could not write test log file.")
+
+	setLocalFileSource(logDirectory)
+	defer unsetLocalFileSource()
+
+	entries := make(chan amqp.Delivery, 100)
+	parserService, err := service.NewInProcessService(entries, nil)
+	utils.FailOnError(err, "Could not create the parser service.")
+
+	server := httptest.NewServer(parserService.Handler())
+	defer server.Close()
+
+	job := waitForJob(t, server, submitJob(t, server))
+	if job.Status != jobs.Succeeded {
+		t.Fatalf("Expected the job to succeed, got %s with errors %v", job.Status, job.Progress.Errors)
+	}
+
+	// The 40 relevant lines of the test log file, between the run start and the run end messages.
+	if len(entries) != 42 {
+		t.Fatalf("Expected 42 messages, got %d", len(entries))
+	}
+}
+
+// TestInProcessJobFailure checks that a job fails, and the service keeps running,
+// if the log files could not be listed.
+func TestInProcessJobFailure(t *testing.T) {
+	setLocalFileSource(filepath.Join(os.TempDir(), "missing_service_test_directory"))
+	defer unsetLocalFileSource()
+
+	entries := make(chan amqp.Delivery, 100)
+	parserService, err := service.NewInProcessService(entries, nil)
+	utils.FailOnError(err, "Could not create the parser service.")
+
+	server := httptest.NewServer(parserService.Handler())
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		job := waitForJob(t, server, submitJob(t, server))
+		if job.Status != jobs.Failed || len(job.Progress.Errors) != 1 {
+			t.Fatalf("Expected the job to fail with an error, got %s with errors %v", job.Status, job.Progress.Errors)
+		}
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("Expected no messages, got %d", len(entries))
+	}
+}
+
+// TestInProcessJobQuarantineFailure checks that a job fails, and the service keeps running,
+// if the quarantine file could not be opened.
+func TestInProcessJobQuarantineFailure(t *testing.T) {
+	setLocalFileSource("../logparser_unit_tests/resources")
+	defer unsetLocalFileSource()
+
+	os.Setenv("QUARANTINE_FILE", filepath.Join(os.TempDir(), "missing_service_test_directory", "quarantine.ndjson"))
+	defer os.Unsetenv("QUARANTINE_FILE")
+
+	entries := make(chan amqp.Delivery, 100)
+	parserService, err := service.NewInProcessService(entries, nil)
+	utils.FailOnError(err, "Could not create the parser service.")
+
+	server := httptest.NewServer(parserService.Handler())
+	defer server.Close()
+
+	job := waitForJob(t, server, submitJob(t, server))
+	if job.Status != jobs.Failed || len(job.Progress.Errors) != 1 {
+		t.Fatalf("Expected the job to fail with an error, got %s with errors %v", job.Status, job.Progress.Errors)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("Expected no messages, got %d", len(entries))
+	}
+}
+
+// TestProcessEndpointSubmitsJob checks that the process endpoint runs the parser as a job of the job manager.
+func TestProcessEndpointSubmitsJob(t *testing.T) {
+	setLocalFileSource("../logparser_unit_tests/resources")
+	defer unsetLocalFileSource()
+
+	entries := make(chan amqp.Delivery, 100)
+	parserService, err := service.NewInProcessService(entries, nil)
+	utils.FailOnError(err, "Could not create the parser service.")
+
+	server := httptest.NewServer(parserService.Handler())
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/process/")
+	utils.FailOnError(err, "Could not call the process endpoint.")
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	utils.FailOnError(err, "Could not read the response.")
+
+	matches := regexp.MustCompile(`/jobs/([0-9a-f-]+)`).FindStringSubmatch(string(body))
+	if response.StatusCode != http.StatusOK || matches == nil {
+		t.Fatalf("Expected a link to the submitted job, got %d: %s", response.StatusCode, body)
+	}
+
+	job := waitForJob(t, server, matches[1])
+	if job.Status != jobs.Succeeded {
+		t.Fatalf("Expected the job to succeed, got %s with errors %v", job.Status, job.Progress.Errors)
+	}
+}
+
+// TestFollowConfiguration checks that the follow mode is configured like the jobs,
+// so it does not start with an invalid setting.
+func TestFollowConfiguration(t *testing.T) {
+	setLocalFileSource("../logparser_unit_tests/resources")
+	defer unsetLocalFileSource()
+
+	os.Setenv("DC_TIMEZONE", "Invalid/Timezone")
+	defer os.Unsetenv("DC_TIMEZONE")
+
+	parserService, err := service.NewInProcessService(make(chan amqp.Delivery, 100), nil)
+	utils.FailOnError(err, "Could not create the parser service.")
+
+	server := httptest.NewServer(parserService.Handler())
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/follow/start/")
+	utils.FailOnError(err, "Could not call the follow endpoint.")
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected status %d for an invalid timezone, got %d", http.StatusInternalServerError, response.StatusCode)
+	}
+}
+
+func setLocalFileSource(logDirectory string) {
+	os.Setenv("FILE_SOURCE", "local")
+	os.Setenv("LOCAL_LOG_DIRECTORY", logDirectory)
+}
+
+func unsetLocalFileSource() {
+	os.Unsetenv("FILE_SOURCE")
+	os.Unsetenv("LOCAL_LOG_DIRECTORY")
+}
+
+func submitJob(t *testing.T, server *httptest.Server) string {
+	response, err := http.Post(server.URL+"/jobs", "application/json", nil)
+	utils.FailOnError(err, "Could not submit job.")
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d", http.StatusAccepted, response.StatusCode)
+	}
+
+	job := jobs.Job{}
+	utils.FailOnError(json.NewDecoder(response.Body).Decode(&job), "Could not decode job.")
+	return job.ID
+}
+
+// waitForJob polls the status of a job until it finishes.
+func waitForJob(t *testing.T, server *httptest.Server, id string) jobs.Job {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		response, err := http.Get(server.URL + "/jobs/" + id)
+		utils.FailOnError(err, "Could not get job.")
+
+		job := jobs.Job{}
+		err = json.NewDecoder(response.Body).Decode(&job)
+		response.Body.Close()
+		utils.FailOnError(err, "Could not decode job.")
+
+		if job.Status != jobs.Queued && job.Status != jobs.Running {
+			return job
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Job %s did not finish in time", id)
+	return jobs.Job{}
+}
@@ -75,7 +75,10 @@ func TestParseTimestamp(t *testing.T) {
 	}
 
 	for index, test := range tests {
-		output := timestampparser.ParseTimestamp(test.input)
+		output, err := timestampparser.ParseTimestamp(test.input)
+		if err != nil {
+			t.Fatalf("Could not parse the timestamp in test no. %d: %s", index, err)
+		}
 
 		if !reflect.DeepEqual(*output, test.expectedOutput) {
 			t.Fatalf("Parsed entry does not match expected parsed entry in test no. %d", index)
@@ -0,0 +1,132 @@
+package timezoneunittests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kozgot/go-log-processing/parser/internal/timezone"
+)
+
+func TestClockDaylightSavingTime(t *testing.T) {
+	budapest, err := time.LoadLocation("Europe/Budapest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Daylight saving time ended at 03:00 CEST on 25 October 2020, the clocks were turned back to 02:00 CET,
+	// so the local times between 02:00 and 03:00 were written twice.
+	tests := []struct {
+		local    time.Time
+		expected time.Time
+	}{
+		{local: localTime(1, 50), expected: utcTime(23, 50)},
+		{local: localTime(2, 30), expected: utcTime(0, 30)},
+		{local: localTime(2, 45), expected: utcTime(0, 45)},
+		{local: localTime(2, 15), expected: utcTime(1, 15)},
+		{local: localTime(2, 50), expected: utcTime(1, 50)},
+		{local: localTime(3, 10), expected: utcTime(2, 10)},
+	}
+
+	clock := timezone.NewClock(budapest)
+	for index, test := range tests {
+		actual := clock.ToUTC(test.local)
+		if !actual.Equal(test.expected) || actual.Location() != time.UTC {
+			t.Fatalf("Expected %v, got %v in test case no. %d", test.expected, actual, index)
+		}
+	}
+
+	// Without the order of the entries, the earlier time is used.
+	if actual := timezone.ToUTC(localTime(2, 15), budapest); !actual.Equal(utcTime(0, 15)) {
+		t.Fatalf("Expected %v, got %v", utcTime(0, 15), actual)
+	}
+
+	// The nil clock does not convert the timestamps.
+	var nilClock *timezone.Clock
+	if actual := nilClock.ToUTC(localTime(2, 15)); !actual.Equal(localTime(2, 15)) {
+		t.Fatalf("Expected %v, got %v", localTime(2, 15), actual)
+	}
+}
+
+func TestClockSkippedLocalTime(t *testing.T) {
+	budapest, err := time.LoadLocation("Europe/Budapest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Daylight saving time started at 02:00 CET on 29 March 2020, the local times until 03:00 were skipped.
+	clock := timezone.NewClock(budapest)
+	tests := []struct {
+		local    time.Time
+		expected time.Time
+	}{
+		{
+			local:    time.Date(2020, time.March, 29, 1, 59, 0, 0, time.UTC),
+			expected: time.Date(2020, time.March, 29, 0, 59, 0, 0, time.UTC),
+		},
+		{
+			local:    time.Date(2020, time.March, 29, 3, 1, 0, 0, time.UTC),
+			expected: time.Date(2020, time.March, 29, 1, 1, 0, 0, time.UTC),
+		},
+	}
+
+	for index, test := range tests {
+		if actual := clock.ToUTC(test.local); !actual.Equal(test.expected) {
+			t.Fatalf("Expected %v, got %v in test case no. %d", test.expected, actual, index)
+		}
+	}
+}
+
+func TestResolver(t *testing.T) {
+	fileLocations, err := timezone.ParseFileLocations("dc18/*=Indian/Antananarivo, plc_manager.log=Europe/Budapest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := timezone.NewResolver(nil, fileLocations)
+	expectedLocations := map[string]string{
+		"dc18/dc_main.log":     "Indian/Antananarivo",
+		"dc19/plc_manager.log": "Europe/Budapest",
+		"dc19/dc_main.log":     "UTC",
+	}
+	for fileName, expected := range expectedLocations {
+		if actual := resolver.Location(fileName).String(); actual != expected {
+			t.Fatalf("Expected timezone %s for %s, got %s", expected, fileName, actual)
+		}
+	}
+
+	// The timezone of the settings entries is only used for the files without a configured timezone.
+	if !resolver.SettingsTimezoneSeen("dc19/dc_main.log", "Europe/Budapest") ||
+		!resolver.SettingsTimezoneSeen("dc18/dc_main.log", "Europe/Budapest") {
+		t.Fatal("Expected the settings timezones to be recorded")
+	}
+
+	if actual := resolver.Location("dc19/dc_main.log").String(); actual != "Europe/Budapest" {
+		t.Fatalf("Expected the timezone of the settings, got %s", actual)
+	}
+
+	if actual := resolver.Location("dc18/dc_main.log").String(); actual != "Indian/Antananarivo" {
+		t.Fatalf("Expected the configured timezone, got %s", actual)
+	}
+
+	if resolver.SettingsTimezoneSeen("dc19/dc_main.log", "Nowhere/Unknown") {
+		t.Fatal("Expected an unknown timezone to be ignored")
+	}
+
+	for _, config := range []string{"dc18/*", "[=UTC", "dc18/*=Nowhere/Unknown"} {
+		if _, err := timezone.ParseFileLocations(config); err == nil {
+			t.Fatalf("Expected an error for %q", config)
+		}
+	}
+}
+
+func localTime(hour int, minute int) time.Time {
+	return time.Date(2020, time.October, 25, hour, minute, 0, 0, time.UTC)
+}
+
+func utcTime(hour int, minute int) time.Time {
+	if hour == 23 {
+		return time.Date(2020, time.October, 24, hour, minute, 0, 0, time.UTC)
+	}
+
+	return time.Date(2020, time.October, 25, hour, minute, 0, 0, time.UTC)
+}
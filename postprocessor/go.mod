@@ -6,3 +6,5 @@ require (
 	github.com/kozgot/go-log-processing/parser v0.0.0-20211130125815-f7855ac3898c
 	github.com/streadway/amqp v1.0.0
 )
+
+replace github.com/kozgot/go-log-processing/parser => ../parser
@@ -9,18 +9,22 @@ import (
 type ConsumptionProcessor struct {
 	consumptionValues []models.ConsumtionValue
 	indexValues       []models.IndexValue
+	indexBaselines    map[string][]models.IndexBaseline
 	messageProducer   rabbitmq.MessageProducer
 }
 
 // NewConsumptionProcessor creates a new consmptionprocessor instance.
+// The index baselines contain the last index values of the pods read from the DB, by pod UID.
 func NewConsumptionProcessor(
 	consumptionValues []models.ConsumtionValue,
 	indexValues []models.IndexValue,
+	indexBaselines map[string][]models.IndexBaseline,
 	messageProducer rabbitmq.MessageProducer,
 ) *ConsumptionProcessor {
 	consumptionProcessor := ConsumptionProcessor{
 		consumptionValues: consumptionValues,
 		indexValues:       indexValues,
+		indexBaselines:    indexBaselines,
 		messageProducer:   messageProducer,
 	}
 
@@ -28,23 +32,62 @@ func NewConsumptionProcessor(
 }
 
 // ProcessConsumptionAndIndexValues performs further processing on to retrieve consumption values for SMCs.
-func (consumptionProcessor *ConsumptionProcessor) ProcessConsumptionAndIndexValues() {
+// The consumption values are checked against the index baseline of their pod.
+// Returns the number of published consumption values, and stops at the first value that could not be published.
+func (consumptionProcessor *ConsumptionProcessor) ProcessConsumptionAndIndexValues() (int, error) {
+	publishedCount := 0
 	for _, cons := range consumptionProcessor.consumptionValues {
-		smcUID := consumptionProcessor.findRelatedSmc(cons)
-		if smcUID != "" {
-			cons.SmcUID = smcUID
-			consumptionProcessor.messageProducer.PublishConsumption(cons)
+		indexvalue := consumptionProcessor.findRelatedIndex(cons)
+		if indexvalue != nil && indexvalue.SmcUID != "" {
+			cons.SmcUID = indexvalue.SmcUID
+			cons.PodUID = indexvalue.PodUID
+			consumptionProcessor.checkAgainstBaseline(&cons, *indexvalue)
+			err := consumptionProcessor.messageProducer.PublishConsumption(cons)
+			if err != nil {
+				return publishedCount, err
+			}
+			publishedCount++
 		}
 	}
+
+	return publishedCount, nil
 }
 
-func (consumptionProcessor *ConsumptionProcessor) findRelatedSmc(cons models.ConsumtionValue) string {
-	for _, indexvalue := range consumptionProcessor.indexValues {
+func (consumptionProcessor *ConsumptionProcessor) findRelatedIndex(cons models.ConsumtionValue) *models.IndexValue {
+	for i, indexvalue := range consumptionProcessor.indexValues {
 		if indexvalue.ServiceLevel == cons.ServiceLevel &&
 			indexvalue.ReceiveTime == cons.ReceiveTime &&
 			indexvalue.PreviousTime == cons.StartTime {
-			return indexvalue.SmcUID
+			return &consumptionProcessor.indexValues[i]
+		}
+	}
+	return nil
+}
+
+// checkAgainstBaseline compares the consumption and the related index value to the last
+// index baseline of the pod that was read before the consumption.
+// The baselines are not sorted, as the entries of the log files may arrive in any order.
+func (consumptionProcessor *ConsumptionProcessor) checkAgainstBaseline(
+	cons *models.ConsumtionValue,
+	indexvalue models.IndexValue,
+) {
+	var baseline *models.IndexBaseline
+	baselines := consumptionProcessor.indexBaselines[indexvalue.PodUID]
+	for i := range baselines {
+		if baselines[i].ReceiveTime.After(cons.ReceiveTime) {
+			continue
+		}
+
+		if baseline == nil || !baselines[i].ReceiveTime.Before(baseline.ReceiveTime) {
+			baseline = &baselines[i]
 		}
 	}
-	return ""
+
+	if baseline == nil {
+		return
+	}
+
+	cons.IndexBaseline = baseline
+	cons.IndexSinceBaseline = indexvalue.Value - baseline.Value
+	cons.BaselineMismatch = cons.IndexSinceBaseline < 0 || cons.Value > cons.IndexSinceBaseline
 }
@@ -89,6 +89,17 @@ func processDCMessageEntry(
 		}
 		return result
 
+	case parsermodels.LastIndex:
+		indexBaseline := processLastIndex(logEntry, podUIDToSmcUID)
+		result := models.ProcessedEntryData{
+			SmcData:         nil,
+			SmcEvent:        nil,
+			ConsumtionValue: nil,
+			IndexValue:      nil,
+			IndexBaseline:   indexBaseline,
+		}
+		return result
+
 	case parsermodels.Consumption:
 		consumptionValue := processConsumption(logEntry)
 		result := models.ProcessedEntryData{
@@ -328,6 +339,25 @@ func processIndexReceived(logEntry parsermodels.ParsedLogEntry, podUIDToSmcUID m
 	return &result
 }
 
+// These entries are read from the DB at the startup of the DC, they contain the last stored index value of a pod.
+// eg.: <--[last index]--(DB) pod_uid[1478] time[1591776000] value[14989]
+func processLastIndex(logEntry parsermodels.ParsedLogEntry, podUIDToSmcUID map[string]string) *models.IndexBaseline {
+	payload := logEntry.InfoParams.DCMessage.Payload
+	if payload == nil || payload.PodUID == "" {
+		return nil
+	}
+
+	result := models.IndexBaseline{
+		ReceiveTime: logEntry.Timestamp,
+		Time:        payload.Time,
+		Value:       payload.Value,
+		PodUID:      payload.PodUID,
+		SmcUID:      podUIDToSmcUID[payload.PodUID],
+	}
+
+	return &result
+}
+
 // These entries have the same timestamp as the corresponding <--[read index profiles]
 // and <--[index]--(SMC) (IndexRead, IndexReceived) entries.
 // If the changes in dex or consumption values are interesting, we can get them from these messages.
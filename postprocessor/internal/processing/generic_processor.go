@@ -0,0 +1,35 @@
+package processing
+
+import (
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+)
+
+// smcUIDField is the name of the generic field that is used as the SMC UID of the event.
+const smcUIDField = "SmcUID"
+
+type GenericProcessor struct {
+}
+
+// ProcessGeneric processes a log entry that has been parsed by a declarative entry format of the parser.
+// The fields of the entry are copied to the event, the SmcUID field is used as the SMC UID of the event.
+func (g *GenericProcessor) ProcessGeneric(logEntry parsermodels.ParsedLogEntry) (*models.SmcData, *models.SmcEvent) {
+	if logEntry.GenericParams == nil {
+		return nil, nil
+	}
+
+	smcUID, _ := logEntry.GenericParams.Fields[smcUIDField].(string)
+	data := models.SmcData{SmcUID: smcUID}
+
+	event := models.SmcEvent{
+		Time:            logEntry.Timestamp,
+		EventType:       models.GenericEvent,
+		EventTypeString: models.EventTypeToString(models.GenericEvent),
+		Label:           logEntry.GenericParams.EntryType,
+		SmcUID:          smcUID,
+		SMC:             data,
+		Fields:          logEntry.GenericParams.Fields,
+	}
+
+	return &data, &event
+}
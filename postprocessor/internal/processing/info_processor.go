@@ -1,12 +1,20 @@
 package processing
 
 import (
+	"strconv"
+
 	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
 	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
 )
 
 type InfoProcessor struct {
 	PodUIDToSmcUID map[string]string
+
+	// SmcStatesByUID contains the last known state bitmask of the SMCs, it is updated by the state change entries.
+	SmcStatesByUID map[string]int64
+
+	// IndexBaselinesByPodUID contains the last index values of the pods read from the DB, in the order they were received.
+	IndexBaselinesByPodUID map[string][]models.IndexBaseline
 }
 
 // ProcessInfoEntry processes a log entry with INFO log level.
@@ -52,6 +60,11 @@ func (i *InfoProcessor) ProcessInfoEntry(logEntry parsermodels.ParsedLogEntry) (
 			}
 		}
 
+		if result.IndexBaseline != nil && i.IndexBaselinesByPodUID != nil {
+			podUID := result.IndexBaseline.PodUID
+			i.IndexBaselinesByPodUID[podUID] = append(i.IndexBaselinesByPodUID[podUID], *result.IndexBaseline)
+		}
+
 		return result.SmcData, result.SmcEvent, result.ConsumtionValue, result.IndexValue
 
 	case parsermodels.ConnectionAttempt:
@@ -74,6 +87,14 @@ func (i *InfoProcessor) ProcessInfoEntry(logEntry parsermodels.ParsedLogEntry) (
 		data, event := processSmcConfigUpdate(logEntry)
 		return data, event, nil, nil
 
+	case parsermodels.SmcStateChange:
+		data, event := processSmcStateChange(logEntry, i.SmcStatesByUID)
+		return data, event, nil, nil
+
+	case parsermodels.TaskLaunch:
+		// task launches are processed by the TaskProcessor at the end of the entries
+		return nil, nil, nil, nil
+
 	// Unrecognized entry type
 	case parsermodels.UnknownInfoType:
 		return nil, nil, nil, nil
@@ -241,3 +262,50 @@ func processSmcConfigUpdate(logEntry parsermodels.ParsedLogEntry) (*models.SmcDa
 
 	return &data, &event
 }
+
+// processSmcStateChange creates a state transition event from the previous and the new state of the SMC.
+// Entries repeating the previous state do not create an event.
+func processSmcStateChange(
+	logEntry parsermodels.ParsedLogEntry,
+	smcStatesByUID map[string]int64,
+) (*models.SmcData, *models.SmcEvent) {
+	if logEntry.InfoParams.StateChange == nil {
+		return nil, nil
+	}
+
+	smcUID := logEntry.InfoParams.StateChange.SmcUID
+	newState := logEntry.InfoParams.StateChange.State
+	previousState, hasPreviousState := smcStatesByUID[smcUID]
+	if hasPreviousState && previousState == newState {
+		return nil, nil
+	}
+
+	if smcStatesByUID != nil {
+		smcStatesByUID[smcUID] = newState
+	}
+
+	transition := models.StateTransition{
+		PreviousState:    previousState,
+		HasPreviousState: hasPreviousState,
+		NewState:         newState,
+		Flags:            logEntry.InfoParams.StateChange.Flags,
+		SetFlags:         parsermodels.DecodeSmcStateFlags(newState &^ previousState),
+		ClearedFlags:     parsermodels.DecodeSmcStateFlags(previousState &^ newState),
+	}
+
+	data := models.SmcData{
+		SmcUID: smcUID,
+	}
+
+	event := models.SmcEvent{
+		Time:            logEntry.Timestamp,
+		EventType:       models.SmcStateChanged,
+		EventTypeString: models.EventTypeToString(models.SmcStateChanged),
+		Label:           "Smc " + smcUID + " changed state to " + strconv.FormatInt(newState, 10),
+		SmcUID:          smcUID,
+		SMC:             data,
+		StateTransition: &transition,
+	}
+
+	return &data, &event
+}
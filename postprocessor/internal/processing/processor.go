@@ -2,13 +2,15 @@ package processing
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
-	"strings"
+	"time"
 
 	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
 	"github.com/kozgot/go-log-processing/postprocessor/internal/rabbitmq"
 	"github.com/kozgot/go-log-processing/postprocessor/internal/utils"
 	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+	"github.com/streadway/amqp"
 )
 
 type EntryProcessor struct {
@@ -16,8 +18,12 @@ type EntryProcessor struct {
 	smcDataBySmcUID   map[string]models.SmcData
 	smcUIDsByURL      map[string]string
 	podUIDToSmcUID    map[string]string
+	smcStatesByUID    map[string]int64
+	indexBaselines    map[string][]models.IndexBaseline
 	consumptionValues []models.ConsumtionValue
 	indexValues       []models.IndexValue
+	taskProcessor     *TaskProcessor
+	run               runTracker
 
 	messageProducer rabbitmq.MessageProducer
 	messageConsumer rabbitmq.MessageConsumer
@@ -31,6 +37,8 @@ func NewEntryProcessor(
 	smcDataBySmcUID := make(map[string]models.SmcData)
 	smcUIDsByURL := make(map[string]string)
 	podUIDToSmcUID := make(map[string]string)
+	smcStatesByUID := make(map[string]int64)
+	indexBaselines := make(map[string][]models.IndexBaseline)
 	consumptionValues := []models.ConsumtionValue{}
 	indexValues := []models.IndexValue{}
 
@@ -39,8 +47,11 @@ func NewEntryProcessor(
 		smcDataBySmcUID:   smcDataBySmcUID,
 		smcUIDsByURL:      smcUIDsByURL,
 		podUIDToSmcUID:    podUIDToSmcUID,
+		smcStatesByUID:    smcStatesByUID,
+		indexBaselines:    indexBaselines,
 		consumptionValues: consumptionValues,
 		indexValues:       indexValues,
+		taskProcessor:     NewTaskProcessor(),
 		messageProducer:   uploader,
 		messageConsumer:   messageConsumer,
 	}
@@ -50,54 +61,215 @@ func NewEntryProcessor(
 
 // HandleEntries consumes entries from the provided MessageConsumer,
 // and publishes them to a rabbitmq queue using the provided MessageProducer.
-func (processor *EntryProcessor) HandleEntries() {
+// The returned channel receives the error that stopped the processing, eg.: the processed data could not be published,
+// it is closed when the consumer has no more entries.
+func (processor *EntryProcessor) HandleEntries() <-chan error {
 	msgs := processor.messageConsumer.ConsumeMessages()
+	errs := make(chan error, 1)
 
 	go func() {
+		defer close(errs)
+
 		for d := range msgs {
-			if strings.Contains(string(d.Body), "END") {
-				log.Println(" [PROCESSOR] End of entries...")
-
-				// Further processing to get consumption and index info.
-				consumptionProcessor := NewConsumptionProcessor(
-					processor.consumptionValues,
-					processor.indexValues,
-					processor.messageProducer,
-				)
-				consumptionProcessor.ProcessConsumptionAndIndexValues()
-
-				log.Println(" [PROCESSOR] Done processing consumption data")
-
-				// Acknowledge the message after it has been processed.
-				err := d.Ack(false)
-				utils.FailOnError(err, " [PROCESSOR] Could not acknowledge END message")
-
-				// Clear previous processed data.
-				processor.reset()
-				continue
+			err := processor.handleDelivery(d)
+			if err != nil {
+				// The delivery is not acknowledged, so it is delivered again when the consumer reconnects.
+				log.Printf(" [PROCESSOR] Stopped processing entries: %s", err)
+				errs <- err
+				return
 			}
+		}
+	}()
+
+	return errs
+}
 
-			entry := deserializeParsedLogEntry(d.Body)
-			processor.ProcessEntry(entry)
+// handleDelivery processes a message consumed from the MessageConsumer, and acknowledges it if it was processed.
+func (processor *EntryProcessor) handleDelivery(d amqp.Delivery) error {
+	envelope, err := parsermodels.OpenEnvelope(d.Body)
+	if err != nil {
+		// Messages of an unknown schema version are not requeued, so a newer parser cannot block the queue.
+		return rejectDelivery(d, err)
+	}
+
+	if envelope.PayloadType == parsermodels.RunControlPayload {
+		return processor.handleRunControl(envelope, d)
+	}
 
-			// Acknowledge the message after it has been processed.
-			err := d.Ack(false)
-			utils.FailOnError(err,
-				" [PROCESSOR] Could not acknowledge message with timestamp: "+entry.Timestamp.Format("2 Jan 2006 15:04:05"))
+	// Parsers publishing string messages signal the end of a run with END, without the number of entries.
+	if message, ok := envelope.StringMessage(); ok {
+		if message != "END" {
+			return rejectDelivery(d, fmt.Errorf("unknown string message %q", message))
 		}
-	}()
+
+		err = processor.finishRun()
+		if err != nil {
+			return err
+		}
+
+		// Acknowledge the message after it has been processed.
+		err = d.Ack(false)
+		utils.FailOnError(err, " [PROCESSOR] Could not acknowledge END message")
+		return nil
+	}
+
+	entry, err := deserializeParsedLogEntry(envelope.Payload)
+	if err != nil {
+		return rejectDelivery(d, err)
+	}
+
+	// The entries of another run are not mixed into the state of the tracked run.
+	if !processor.run.belongsToRun(envelope.RunID) {
+		log.Printf(" [PROCESSOR] Dropped an entry of run %s while processing run %s",
+			envelope.RunID, processor.run.runID)
+		err = d.Ack(false)
+		utils.FailOnError(err, " [PROCESSOR] Could not acknowledge dropped message")
+		return nil
+	}
+
+	err = processor.ProcessEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	// Acknowledge the message after it has been processed.
+	err = d.Ack(false)
+	utils.FailOnError(err,
+		" [PROCESSOR] Could not acknowledge message with timestamp: "+entry.Timestamp.Format("2 Jan 2006 15:04:05"))
+
+	processor.run.entryReceived(envelope.RunID)
+
+	// The run end may arrive before the last entries of the run, eg.: when they are redelivered.
+	if processor.run.isComplete() {
+		return processor.finishRun()
+	}
+
+	return nil
+}
+
+// handleRunControl starts tracking a run on a run start, and finishes the run on a run end,
+// if every entry of the run has been received, otherwise the run is finished when the last entry arrives.
+func (processor *EntryProcessor) handleRunControl(envelope *parsermodels.Envelope, d amqp.Delivery) error {
+	runControl, err := envelope.RunControl()
+	if err != nil {
+		log.Printf(" [PROCESSOR] Rejected run control message: %s", err)
+		err = d.Reject(false)
+		utils.FailOnError(err, " [PROCESSOR] Could not reject message")
+		return nil
+	}
+
+	switch runControl.ControlType {
+	case parsermodels.RunStart:
+		// The parser only starts a run when the previous one has ended, the missing entries are not waited for.
+		if processor.run.hasEnded() {
+			log.Printf(" [PROCESSOR] Run %s started, finishing run %s with %d of %d entries",
+				runControl.RunID, processor.run.runID, processor.run.entriesSeen, processor.run.expectedEntries())
+			err = processor.finishRun()
+			if err != nil {
+				return err
+			}
+		}
+
+		processor.run.start(runControl, envelope.Timestamp)
+		log.Printf(" [PROCESSOR] Run %s started with %d files", runControl.RunID, len(runControl.FileNames))
+
+		err = d.Ack(false)
+		utils.FailOnError(err, " [PROCESSOR] Could not acknowledge run start")
+
+	case parsermodels.RunEnd:
+		if processor.run.runID != "" && processor.run.runID != runControl.RunID {
+			log.Printf(" [PROCESSOR] Received the end of run %s while processing run %s",
+				runControl.RunID, processor.run.runID)
+		}
+
+		// The run end is acknowledged when the run is finished.
+		processor.run.end(runControl, envelope.Timestamp, d)
+		if processor.run.isComplete() {
+			return processor.finishRun()
+		}
+
+		log.Printf(" [PROCESSOR] Run %s ended, waiting for %d of %d entries",
+			runControl.RunID, processor.run.expectedEntries()-processor.run.entriesSeen, processor.run.expectedEntries())
+
+	default:
+		log.Printf(" [PROCESSOR] Rejected run control message of unknown type: %d", runControl.ControlType)
+		err = d.Reject(false)
+		utils.FailOnError(err, " [PROCESSOR] Could not reject message")
+	}
+
+	return nil
+}
+
+// finishRun processes the consumption data and the task lifecycles of the run, and clears the processed data.
+// The run end is acknowledged when the processed data is delivered.
+func (processor *EntryProcessor) finishRun() error {
+	log.Printf(" [PROCESSOR] End of entries of run %s (%d entries)...", processor.run.runID, processor.run.entriesSeen)
+
+	// Further processing to get consumption and index info.
+	consumptionProcessor := NewConsumptionProcessor(
+		processor.consumptionValues,
+		processor.indexValues,
+		processor.indexBaselines,
+		processor.messageProducer,
+	)
+	consumptionCount, err := consumptionProcessor.ProcessConsumptionAndIndexValues()
+	processor.run.consumptionCount += consumptionCount
+	if err != nil {
+		return fmt.Errorf("could not publish the consumption data of run %s: %w", processor.run.runID, err)
+	}
+
+	log.Println(" [PROCESSOR] Done processing consumption data")
+
+	err = processor.publishTaskLifecycles()
+	if err != nil {
+		return fmt.Errorf("could not publish the task lifecycles of run %s: %w", processor.run.runID, err)
+	}
+
+	// The manifest is only published for the runs of parsers sending run control messages.
+	if processor.run.runID != "" {
+		err = processor.messageProducer.PublishRunManifest(processor.run.manifest(time.Now().UTC()))
+		if err != nil {
+			return fmt.Errorf("could not publish the manifest of run %s: %w", processor.run.runID, err)
+		}
+	}
+
+	err = processor.messageProducer.Flush()
+	if err != nil {
+		return fmt.Errorf("could not deliver the processed data of run %s: %w", processor.run.runID, err)
+	}
+
+	if processor.run.hasEnded() {
+		err = processor.run.endDelivery.Ack(false)
+		utils.FailOnError(err, " [PROCESSOR] Could not acknowledge run end")
+	}
+
+	// Clear previous processed data.
+	processor.reset()
+	return nil
 }
 
 // ProcessEntry processes the log entry received as a parameter.
-func (processor *EntryProcessor) ProcessEntry(logEntry parsermodels.ParsedLogEntry) {
+// Returns an error if the event created from the entry could not be published.
+func (processor *EntryProcessor) ProcessEntry(logEntry parsermodels.ParsedLogEntry) error {
 	var data *models.SmcData
 	var event *models.SmcEvent
 	var consumption *models.ConsumtionValue
 	var indexvalue *models.IndexValue
-	switch logEntry.Level {
-	case "INFO":
+
+	// Task launches and task failures are collected until the end of the entries.
+	processor.taskProcessor.ProcessEntry(logEntry)
+
+	switch {
+	case logEntry.GenericParams != nil:
+		genericProcessor := GenericProcessor{}
+		data, event = genericProcessor.ProcessGeneric(logEntry)
+
+	// VERBOSE entries are only sent by the parser if they are SMC state changes, these are parsed as info entries.
+	case logEntry.Level == "INFO" || logEntry.Level == "VERBOSE":
 		infoProcessor := InfoProcessor{
-			PodUIDToSmcUID: processor.podUIDToSmcUID,
+			PodUIDToSmcUID:         processor.podUIDToSmcUID,
+			SmcStatesByUID:         processor.smcStatesByUID,
+			IndexBaselinesByPodUID: processor.indexBaselines,
 		}
 		data, event, consumption, indexvalue = infoProcessor.ProcessInfoEntry(logEntry)
 
@@ -117,18 +289,19 @@ func (processor *EntryProcessor) ProcessEntry(logEntry parsermodels.ParsedLogEnt
 			processor.indexValues = append(processor.indexValues, *indexvalue)
 		}
 		if consumption != nil {
+			consumption.RunID = logEntry.RunID
 			processor.consumptionValues = append(processor.consumptionValues, *consumption)
 		}
 
-	case "WARN":
+	case logEntry.Level == "WARN":
 		warningProcessor := WarningProcessor{}
 		data, event = warningProcessor.ProcessWarn(logEntry)
 
-	case "WARNING":
+	case logEntry.Level == "WARNING":
 		warningProcessor := WarningProcessor{}
 		data, event = warningProcessor.ProcessWarning(logEntry)
 
-	case "ERROR":
+	case logEntry.Level == "ERROR":
 		errorProcessor := ErrorProcessor{}
 		data, event = errorProcessor.ProcessError(logEntry)
 
@@ -136,8 +309,36 @@ func (processor *EntryProcessor) ProcessEntry(logEntry parsermodels.ParsedLogEnt
 		log.Printf(" [PROCESSOR] Unknown log level %s", logEntry.Level)
 	}
 
-	processor.registerEvent(event, data)
+	if event != nil {
+		// Link the event to the log line and the run it was created from.
+		event.Source = models.SourceLocation(logEntry.Source)
+		event.RunID = logEntry.RunID
+	}
+
+	err := processor.registerEvent(event, data)
+	if err != nil {
+		return err
+	}
+
 	processor.updateSmcData(data)
+	return nil
+}
+
+// publishTaskLifecycles publishes the lifecycle of the tasks seen since the last reset.
+func (processor *EntryProcessor) publishTaskLifecycles() error {
+	lifecycles := processor.taskProcessor.Lifecycles()
+	for _, lifecycle := range lifecycles {
+		event := CreateTaskLifecycleEvent(lifecycle)
+		event.RunID = processor.run.runID
+		err := processor.messageProducer.PublishEvent(event)
+		if err != nil {
+			return err
+		}
+		processor.run.eventCount++
+	}
+
+	log.Printf(" [PROCESSOR] Published the lifecycle of %d tasks", len(lifecycles))
+	return nil
 }
 
 func initArrayIfNeeded(eventsBySmcUID map[string][]models.SmcEvent, uid string) {
@@ -147,13 +348,13 @@ func initArrayIfNeeded(eventsBySmcUID map[string][]models.SmcEvent, uid string)
 	}
 }
 
-func (processor *EntryProcessor) registerEvent(event *models.SmcEvent, data *models.SmcData) {
+func (processor *EntryProcessor) registerEvent(event *models.SmcEvent, data *models.SmcData) error {
 	if data == nil {
-		return
+		return nil
 	}
 
 	if event == nil {
-		return
+		return nil
 	}
 
 	smcUID := data.SmcUID
@@ -172,7 +373,14 @@ func (processor *EntryProcessor) registerEvent(event *models.SmcEvent, data *mod
 	processor.eventsBySmcUID[smcUID] = append(processor.eventsBySmcUID[smcUID], *event)
 
 	// send to ES
-	processor.messageProducer.PublishEvent(*event)
+	err := processor.messageProducer.PublishEvent(*event)
+	if err != nil {
+		return fmt.Errorf("could not publish the event of line %d of %s: %w",
+			event.Source.LineNumber, event.Source.FileName, err)
+	}
+
+	processor.run.eventCount++
+	return nil
 }
 
 func (processor *EntryProcessor) updateSmcData(data *models.SmcData) {
@@ -292,13 +500,35 @@ func (processor *EntryProcessor) reset() {
 		delete(processor.podUIDToSmcUID, k)
 	}
 
+	for k := range processor.smcStatesByUID {
+		delete(processor.smcStatesByUID, k)
+	}
+
+	for k := range processor.indexBaselines {
+		delete(processor.indexBaselines, k)
+	}
+
 	processor.consumptionValues = []models.ConsumtionValue{}
 	processor.indexValues = []models.IndexValue{}
+	processor.taskProcessor.Reset()
+	processor.run.next()
 }
 
-func deserializeParsedLogEntry(bytes []byte) parsermodels.ParsedLogEntry {
+// rejectDelivery rejects a message that can not be processed without requeueing it,
+// so a malformed message cannot block the queue.
+func rejectDelivery(d amqp.Delivery, reason error) error {
+	log.Printf(" [PROCESSOR] Rejected message: %s", reason)
+	err := d.Reject(false)
+	utils.FailOnError(err, " [PROCESSOR] Could not reject message")
+	return nil
+}
+
+func deserializeParsedLogEntry(bytes []byte) (parsermodels.ParsedLogEntry, error) {
 	var parsedEntry parsermodels.ParsedLogEntry
 	err := json.Unmarshal(bytes, &parsedEntry)
-	utils.FailOnError(err, "Failed to unmarshal log entry")
-	return parsedEntry
+	if err != nil {
+		return parsedEntry, fmt.Errorf("could not decode log entry: %w", err)
+	}
+
+	return parsedEntry, nil
 }
@@ -0,0 +1,140 @@
+package processing
+
+import (
+	"time"
+
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+	"github.com/streadway/amqp"
+)
+
+// runTracker counts the entries received in the parser run being processed.
+// The entries of every run are processed into the same state, so the runs are expected to follow each other,
+// a run is finished when its run end, and every entry promised in the run end has been received.
+type runTracker struct {
+	runID       string
+	fileNames   []string
+	startedAt   time.Time
+	entriesSeen int
+
+	// previousRunID is the ID of the last run tracked before this one, its late entries are dropped.
+	previousRunID string
+
+	// The number of data units published in the run.
+	eventCount       int
+	consumptionCount int
+
+	// runEnd is nil until the run end is received, its delivery is acknowledged when the run is finished.
+	runEnd      *parsermodels.RunControl
+	endedAt     time.Time
+	endDelivery amqp.Delivery
+}
+
+// start starts tracking a new run, startedAt is the time the parser started the run.
+// If the run is already tracked from its entries received before the run start, its counts are kept.
+func (tracker *runTracker) start(runStart parsermodels.RunControl, startedAt time.Time) {
+	if runStart.RunID != "" && runStart.RunID == tracker.runID {
+		tracker.fileNames = runStart.FileNames
+		tracker.startedAt = startedAt
+		return
+	}
+
+	*tracker = runTracker{
+		runID:         runStart.RunID,
+		previousRunID: tracker.lastRunID(),
+		fileNames:     runStart.FileNames,
+		startedAt:     startedAt,
+	}
+}
+
+// next clears the tracked run after it has been finished, the entries of the next run are tracked from now on.
+func (tracker *runTracker) next() {
+	*tracker = runTracker{previousRunID: tracker.lastRunID()}
+}
+
+// lastRunID returns the ID of the tracked run, or the ID of the previous run if no run is tracked.
+func (tracker *runTracker) lastRunID() string {
+	if tracker.runID != "" {
+		return tracker.runID
+	}
+
+	return tracker.previousRunID
+}
+
+// belongsToRun checks if an entry with the given run ID belongs to the tracked run.
+// Entries without a run ID, and the entries of a run whose start was not received, eg.: after a restart,
+// belong to the tracked run, the entries of the previous run are redelivered after the run has been finished.
+func (tracker *runTracker) belongsToRun(runID string) bool {
+	if runID == "" {
+		return true
+	}
+
+	if tracker.runID == "" {
+		return runID != tracker.previousRunID
+	}
+
+	return runID == tracker.runID
+}
+
+// entryReceived counts an entry of the tracked run, the run is identified by its first entry if it has no start.
+func (tracker *runTracker) entryReceived(runID string) {
+	if tracker.runID == "" {
+		tracker.runID = runID
+	}
+
+	tracker.entriesSeen++
+}
+
+// end records the run end of the tracked run, endedAt is the time the parser ended the run.
+func (tracker *runTracker) end(runEnd parsermodels.RunControl, endedAt time.Time, delivery amqp.Delivery) {
+	if tracker.runID == "" {
+		tracker.runID = runEnd.RunID
+	}
+
+	tracker.runEnd = &runEnd
+	tracker.endedAt = endedAt
+	tracker.endDelivery = delivery
+}
+
+// hasEnded checks if the run end of the tracked run has been received.
+func (tracker *runTracker) hasEnded() bool {
+	return tracker.runEnd != nil
+}
+
+// isComplete checks if the run end, and every entry promised in it has been received.
+func (tracker *runTracker) isComplete() bool {
+	return tracker.runEnd != nil && tracker.entriesSeen >= tracker.runEnd.TotalEntries
+}
+
+// expectedEntries returns the number of entries promised in the run end, or 0 if the run has not ended yet.
+func (tracker *runTracker) expectedEntries() int {
+	if tracker.runEnd == nil {
+		return 0
+	}
+
+	return tracker.runEnd.TotalEntries
+}
+
+// manifest creates the manifest of the tracked run, finishedAt is the time the processing of the run finished.
+func (tracker *runTracker) manifest(finishedAt time.Time) models.RunManifest {
+	manifest := models.RunManifest{
+		RunID:            tracker.runID,
+		StartedAt:        tracker.startedAt,
+		EndedAt:          tracker.endedAt,
+		FinishedAt:       finishedAt,
+		FileNames:        tracker.fileNames,
+		EntryCounts:      map[string]int{},
+		ExpectedEntries:  tracker.expectedEntries(),
+		ReceivedEntries:  tracker.entriesSeen,
+		Complete:         tracker.isComplete(),
+		EventCount:       tracker.eventCount,
+		ConsumptionCount: tracker.consumptionCount,
+	}
+
+	if tracker.runEnd != nil {
+		manifest.FileNames = tracker.runEnd.FileNames
+		manifest.EntryCounts = tracker.runEnd.EntryCounts
+	}
+
+	return manifest
+}
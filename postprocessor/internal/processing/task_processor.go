@@ -0,0 +1,203 @@
+package processing
+
+import (
+	"sort"
+	"time"
+
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+)
+
+// TaskProcessor correlates the task launch entries and the task failed warnings of the task scheduler
+// by the DC writing the entries and the UID of the task, and builds the lifecycle of the tasks.
+// The UIDs are only unique in a DC, the tasks of different DCs are tracked separately.
+type TaskProcessor struct {
+	tasks map[taskKey]*models.TaskLifecycle
+
+	// tasksByThread contains the last task launched on each thread of the task scheduler of the DCs.
+	tasksByThread map[threadKey]*models.TaskLifecycle
+}
+
+// taskKey identifies a task of the task scheduler of a DC.
+type taskKey struct {
+	dc  string
+	uid int
+}
+
+// threadKey identifies a thread of the task scheduler of a DC.
+type threadKey struct {
+	dc     string
+	thread string
+}
+
+// NewTaskProcessor creates a new TaskProcessor.
+func NewTaskProcessor() *TaskProcessor {
+	return &TaskProcessor{
+		tasks:         make(map[taskKey]*models.TaskLifecycle),
+		tasksByThread: make(map[threadKey]*models.TaskLifecycle),
+	}
+}
+
+// ProcessEntry records the task launch and task failed entries, the other entries are ignored.
+func (t *TaskProcessor) ProcessEntry(logEntry parsermodels.ParsedLogEntry) {
+	dc := parsermodels.SourceDC(logEntry.Source.FileName)
+
+	if logEntry.InfoParams != nil && logEntry.InfoParams.TaskLaunch != nil {
+		t.processLaunch(dc, logEntry.Timestamp, *logEntry.InfoParams.TaskLaunch)
+	}
+
+	if logEntry.WarningParams != nil && logEntry.WarningParams.TaskFailedWarningParams != nil {
+		t.processFailure(dc, logEntry.Timestamp, *logEntry.WarningParams.TaskFailedWarningParams)
+	}
+}
+
+func (t *TaskProcessor) processLaunch(dc string, timestamp time.Time, launch parsermodels.TaskLaunchParams) {
+	task := t.task(dc, launch.UID, launch.Name, launch.SmcUID, launch.Priority, launch.Creation)
+	task.Type = launch.Type
+	task.Launches++
+	if launch.Retry > task.Retry {
+		task.Retry = launch.Retry
+	}
+
+	if task.FirstLaunchedAt == nil {
+		task.FirstLaunchedAt = &timestamp
+	}
+	task.LastLaunchedAt = &timestamp
+
+	if launch.Thread == "" {
+		return
+	}
+
+	// A thread runs one task at a time, so the previous task of the thread has ended when the next one is launched.
+	// It has finished, unless its failure is logged.
+	// A retried task may be launched on another thread, it has only ended if it was last launched on this one.
+	thread := threadKey{dc: dc, thread: launch.Thread}
+	if previous, ok := t.tasksByThread[thread]; ok && previous != task && previous.Thread == launch.Thread {
+		previous.EndedAt = &timestamp
+	}
+	t.tasksByThread[thread] = task
+	task.Thread = launch.Thread
+}
+
+func (t *TaskProcessor) processFailure(dc string, timestamp time.Time, failure parsermodels.TaskFailedWarningParams) {
+	task := t.task(dc, failure.UID, failure.Name, failure.SmcUID, failure.Priority, failure.Creation)
+	task.Failures++
+	task.LastFailedAt = &timestamp
+	if failure.Retry > task.Retry {
+		task.Retry = failure.Retry
+	}
+
+	if failure.Details != nil {
+		task.LastError = failure.Details.Message
+	}
+}
+
+// task returns the lifecycle of the task with the given UID in the DC,
+// the missing details are filled in from the entry.
+func (t *TaskProcessor) task(
+	dc string,
+	uid int,
+	name string,
+	smcUID string,
+	priority int,
+	creation time.Time,
+) *models.TaskLifecycle {
+	key := taskKey{dc: dc, uid: uid}
+	task, ok := t.tasks[key]
+	if !ok {
+		task = &models.TaskLifecycle{DC: dc, UID: uid}
+		t.tasks[key] = task
+	}
+
+	if task.Name == "" {
+		task.Name = name
+	}
+
+	if task.SmcUID == "" {
+		task.SmcUID = smcUID
+	}
+
+	if task.Priority == 0 {
+		task.Priority = priority
+	}
+
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = creation
+	}
+
+	return task
+}
+
+// Lifecycles returns the lifecycles of the recorded tasks ordered by their creation time, DC and UID,
+// with their status and queue latency.
+func (t *TaskProcessor) Lifecycles() []models.TaskLifecycle {
+	lifecycles := []models.TaskLifecycle{}
+	for _, task := range t.tasks {
+		lifecycle := *task
+		lifecycle.Status = taskStatus(lifecycle)
+		lifecycle.StatusString = models.TaskStatusToString(lifecycle.Status)
+
+		if lifecycle.FirstLaunchedAt != nil && !lifecycle.CreatedAt.IsZero() {
+			lifecycle.QueueLatencySeconds = lifecycle.FirstLaunchedAt.Sub(lifecycle.CreatedAt).Seconds()
+		}
+
+		lifecycles = append(lifecycles, lifecycle)
+	}
+
+	sort.Slice(lifecycles, func(i, j int) bool {
+		if !lifecycles[i].CreatedAt.Equal(lifecycles[j].CreatedAt) {
+			return lifecycles[i].CreatedAt.Before(lifecycles[j].CreatedAt)
+		}
+
+		if lifecycles[i].DC != lifecycles[j].DC {
+			return lifecycles[i].DC < lifecycles[j].DC
+		}
+
+		return lifecycles[i].UID < lifecycles[j].UID
+	})
+
+	return lifecycles
+}
+
+// taskStatus returns the status of a task after its last logged launch or failure.
+// A task is only finished if the thread it was launched on has moved on to the next task without a failure.
+func taskStatus(lifecycle models.TaskLifecycle) models.TaskStatus {
+	switch {
+	case lifecycle.LastFailedAt != nil &&
+		(lifecycle.LastLaunchedAt == nil || !lifecycle.LastFailedAt.Before(*lifecycle.LastLaunchedAt)):
+		return models.TaskFailed
+
+	case lifecycle.LastLaunchedAt == nil:
+		return models.TaskCreated
+
+	case lifecycle.EndedAt != nil && !lifecycle.EndedAt.Before(*lifecycle.LastLaunchedAt):
+		return models.TaskFinished
+
+	default:
+		return models.TaskLaunched
+	}
+}
+
+// Reset clears the recorded tasks.
+func (t *TaskProcessor) Reset() {
+	for k := range t.tasks {
+		delete(t.tasks, k)
+	}
+
+	for k := range t.tasksByThread {
+		delete(t.tasksByThread, k)
+	}
+}
+
+// CreateTaskLifecycleEvent creates an event containing the lifecycle of a task.
+func CreateTaskLifecycleEvent(lifecycle models.TaskLifecycle) models.SmcEvent {
+	return models.SmcEvent{
+		Time:            lifecycle.CreatedAt,
+		EventType:       models.TaskLifecycleEvent,
+		EventTypeString: models.EventTypeToString(models.TaskLifecycleEvent),
+		Label:           "Task " + lifecycle.Name + " " + lifecycle.StatusString,
+		SmcUID:          lifecycle.SmcUID,
+		SMC:             models.SmcData{SmcUID: lifecycle.SmcUID},
+		Task:            &lifecycle,
+	}
+}
@@ -1,20 +1,53 @@
 package rabbitmq
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"sync"
+	"time"
 
+	"github.com/kozgot/go-log-processing/parser/pkg/confirm"
 	"github.com/kozgot/go-log-processing/postprocessor/internal/utils"
 	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
 	"github.com/streadway/amqp"
 )
 
+const (
+	// publishWindowSize is the number of messages published before waiting for their confirmations.
+	publishWindowSize = 256
+
+	// confirmTimeout is the time to wait for the confirmations of a window before reconnecting.
+	confirmTimeout = 30 * time.Second
+
+	// maxReconnectAttempts is the number of reconnect attempts before giving up, the delay doubles after every attempt.
+	maxReconnectAttempts  = 10
+	initialReconnectDelay = time.Second
+	maxReconnectDelay     = 30 * time.Second
+)
+
+var (
+	errChannelClosed  = errors.New("channel closed while waiting for confirmations")
+	errConfirmTimeout = errors.New("timed out waiting for confirmations")
+)
+
 // AmqpProducer implements the MessageProducer interface.
+// The data units are published in confirm mode: at most publishWindowSize messages are published
+// before waiting for the confirmations of the broker, and the nacked messages are published again.
+// If publishing fails, the producer reconnects, declares the exchange again,
+// and publishes the messages that were not confirmed yet, so messages may be delivered twice, but are not lost.
 type AmqpProducer struct {
-	rabbitMqURL  string
-	connection   *amqp.Connection
-	channel      *amqp.Channel
-	routingKey   string
-	exchangeName string
+	rabbitMqURL   string
+	connection    *amqp.Connection
+	channel       *amqp.Channel
+	confirmations chan amqp.Confirmation
+	window        *confirm.Window
+	mutex         sync.Mutex
+	routingKey    string
+	exchangeName  string
+
+	// producerID identifies this postprocessor instance in the envelopes of the published data units.
+	producerID string
 }
 
 // NewAmqpProducer creates a new message producer that publishes messages to rabbitmq.
@@ -24,34 +57,84 @@ func NewAmqpProducer(
 	routingKey string) *AmqpProducer {
 	producer := AmqpProducer{
 		rabbitMqURL:  rabbitMqURL,
+		window:       confirm.NewWindow(publishWindowSize),
 		exchangeName: exchangeName,
-		routingKey:   routingKey}
+		routingKey:   routingKey,
+		producerID:   models.NewProducerID("postprocessor")}
 
 	return &producer
 }
 
 // PublishEvent sends an SMC event to the uploader service.
-func (producer *AmqpProducer) PublishEvent(event models.SmcEvent) {
+func (producer *AmqpProducer) PublishEvent(event models.SmcEvent) error {
 	dataToSend := models.DataUnit{DataType: models.Event, Data: event.Serialize()}
-	producer.publishData(dataToSend.Serialize())
+	envelope := models.NewEnvelope(producer.producerID, event.RunID, dataToSend)
+	return producer.publishData(envelope.Serialize())
 }
 
 // PublishConsumption sends a consumption data item to the uploader service.
-func (producer *AmqpProducer) PublishConsumption(cons models.ConsumtionValue) {
+func (producer *AmqpProducer) PublishConsumption(cons models.ConsumtionValue) error {
 	dataToSend := models.DataUnit{DataType: models.Consumption, Data: cons.Serialize()}
-	producer.publishData(dataToSend.Serialize())
+	envelope := models.NewEnvelope(producer.producerID, cons.RunID, dataToSend)
+	return producer.publishData(envelope.Serialize())
+}
+
+// PublishRunManifest sends the manifest of a processed run to the uploader service.
+func (producer *AmqpProducer) PublishRunManifest(manifest models.RunManifest) error {
+	dataToSend := models.DataUnit{DataType: models.Manifest, Data: manifest.Serialize()}
+	envelope := models.NewEnvelope(producer.producerID, manifest.RunID, dataToSend)
+	return producer.publishData(envelope.Serialize())
 }
 
 // Connect opens a channel and a connection.
 func (producer *AmqpProducer) Connect() {
+	err := producer.connect()
+	utils.FailOnError(err, " [AMQP PRODUCER] Failed to connect to RabbitMQ")
+}
+
+// Flush waits until the broker confirms every published data unit.
+func (producer *AmqpProducer) Flush() error {
+	producer.mutex.Lock()
+	defer producer.mutex.Unlock()
+
+	return producer.waitForConfirmations()
+}
+
+// CloseChannelAndConnection waits for the confirmations of the published data units,
+// and closes the channel and connection received in parameter.
+func (producer *AmqpProducer) CloseChannelAndConnection() {
+	err := producer.Flush()
+	if err != nil {
+		log.Printf(" [AMQP PRODUCER] Closing with %d unconfirmed data units: %s", producer.window.Len(), err)
+	}
+
+	producer.connection.Close()
+	log.Println(" [AMQP PRODUCER] Closed connection")
+	producer.channel.Close()
+	log.Println(" [AMQP PRODUCER] Closed channel")
+}
+
+// connect opens a new connection, and a channel in confirm mode on it.
+func (producer *AmqpProducer) connect() error {
 	var err error
 	producer.connection, err = amqp.Dial(producer.rabbitMqURL)
-	utils.FailOnError(err, " [AMQP PRODUCER] Failed to connect to RabbitMQ")
+	if err != nil {
+		return err
+	}
 	log.Println(" [AMQP PRODUCER] Created connection")
 
+	return producer.openChannel()
+}
+
+// openChannel opens a channel in confirm mode, and declares the exchange.
+func (producer *AmqpProducer) openChannel() error {
+	var err error
+
 	// create the channel
 	producer.channel, err = producer.connection.Channel()
-	utils.FailOnError(err, " [AMQP PRODUCER] Failed to open a channel")
+	if err != nil {
+		return err
+	}
 	log.Println(" [AMQP PRODUCER] Created channel")
 
 	err = producer.channel.ExchangeDeclare(
@@ -63,21 +146,162 @@ func (producer *AmqpProducer) Connect() {
 		false,                 // no-wait
 		nil,                   // arguments
 	)
-	utils.FailOnError(err, " [AMQP PRODUCER] Failed to declare an exchange")
+	if err != nil {
+		return err
+	}
+
+	err = producer.channel.Confirm(false)
+	if err != nil {
+		return err
+	}
+
+	// The buffer holds the confirmations of a whole window, so the connection is never blocked by the producer.
+	producer.confirmations = producer.channel.NotifyPublish(make(chan amqp.Confirmation, publishWindowSize))
+	producer.window.ResetDeliveryTags()
+	return nil
 }
 
-// CloseChannelAndConnection closes the channel and connection received in parameter.
-func (producer *AmqpProducer) CloseChannelAndConnection() {
-	producer.connection.Close()
-	log.Println(" [AMQP PRODUCER] Closed connection")
-	producer.channel.Close()
-	log.Println(" [AMQP PRODUCER] Closed channel")
+// publishData publishes a message, and waits for the confirmations if the window is full.
+func (producer *AmqpProducer) publishData(data []byte) error {
+	producer.mutex.Lock()
+	defer producer.mutex.Unlock()
+
+	producer.window.Add(data)
+	err := producer.publishOnChannel(data)
+	if err != nil {
+		err = producer.reconnectAndRepublish(err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if producer.window.IsFull() {
+		return producer.waitForConfirmations()
+	}
+
+	return nil
+}
+
+// waitForConfirmations waits until the broker confirms every message of the window,
+// the messages nacked by the broker are published again.
+func (producer *AmqpProducer) waitForConfirmations() error {
+	for producer.window.Len() > 0 {
+		err := producer.collectConfirmations()
+		if err != nil {
+			err = producer.reconnectAndRepublish(err)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		nackedMessages := producer.window.TakeUnconfirmed()
+		if len(nackedMessages) > 0 {
+			log.Printf(" [AMQP PRODUCER] Publishing %d nacked messages again", len(nackedMessages))
+		}
+
+		err = producer.republish(nackedMessages)
+		if err != nil {
+			err = producer.reconnectAndRepublish(err)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectConfirmations reads the confirmations of the broker until every message of the window is settled.
+func (producer *AmqpProducer) collectConfirmations() error {
+	timeout := time.After(confirmTimeout)
+	for !producer.window.IsSettled() {
+		select {
+		case confirmation, ok := <-producer.confirmations:
+			if !ok {
+				return errChannelClosed
+			}
+			producer.window.Confirm(confirmation)
+		case <-timeout:
+			return errConfirmTimeout
+		}
+	}
+
+	return nil
 }
 
-func (producer *AmqpProducer) publishData(data []byte) {
+// reconnectAndRepublish opens a new channel, or a new connection if the connection is closed,
+// and publishes the unconfirmed messages of the window again.
+// Returns an error after maxReconnectAttempts, the unconfirmed messages are kept in the window.
+func (producer *AmqpProducer) reconnectAndRepublish(cause error) error {
+	log.Printf(" [AMQP PRODUCER] Publishing failed, reconnecting: %s", cause)
+
+	delay := initialReconnectDelay
+	for attempt := 1; ; attempt++ {
+		unconfirmedMessages := producer.window.TakeUnconfirmed()
+
+		err := producer.reconnect()
+		if err != nil {
+			// Keep the messages in the window for the next attempt.
+			for _, message := range unconfirmedMessages {
+				producer.window.Add(message)
+			}
+		} else {
+			err = producer.republish(unconfirmedMessages)
+			if err == nil {
+				log.Printf(" [AMQP PRODUCER] Reconnected, published %d unconfirmed messages again",
+					len(unconfirmedMessages))
+				return nil
+			}
+		}
+
+		if attempt == maxReconnectAttempts {
+			return fmt.Errorf("failed to reconnect to RabbitMQ after %d attempts: %w", attempt, err)
+		}
+
+		log.Printf(" [AMQP PRODUCER] Reconnect attempt %d failed: %s", attempt, err)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// reconnect replaces the channel of the producer, and the connection too if it is closed.
+func (producer *AmqpProducer) reconnect() error {
+	if producer.channel != nil {
+		producer.channel.Close()
+	}
+
+	if producer.connection != nil && !producer.connection.IsClosed() {
+		return producer.openChannel()
+	}
+
+	return producer.connect()
+}
+
+// republish adds the messages to the window, and publishes them on the current channel.
+// The messages are added to the window before publishing, so none of them are lost if publishing fails.
+func (producer *AmqpProducer) republish(messages [][]byte) error {
+	for _, message := range messages {
+		producer.window.Add(message)
+	}
+
+	for _, message := range messages {
+		err := producer.publishOnChannel(message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (producer *AmqpProducer) publishOnChannel(data []byte) error {
 	body := data
 
-	err := producer.channel.Publish(
+	return producer.channel.Publish(
 		producer.exchangeName, // exchange
 		producer.routingKey,   // routing key
 		false,                 // mandatory
@@ -87,6 +311,4 @@ func (producer *AmqpProducer) publishData(data []byte) {
 			ContentType:  "application/json",
 			Body:         body,
 		})
-
-	utils.FailOnError(err, " [AMQP PRODUCER] Failed to publish a message")
 }
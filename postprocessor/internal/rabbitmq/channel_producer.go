@@ -0,0 +1,88 @@
+package rabbitmq
+
+import (
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+	"github.com/streadway/amqp"
+)
+
+// ChannelProducer implements the MessageProducer interface, it sends the data units as deliveries
+// to an in-memory channel instead of RabbitMQ. The data units are wrapped in the same envelopes
+// as the messages of the AmqpProducer, so the uploader handles them the same way.
+type ChannelProducer struct {
+	deliveries  chan<- amqp.Delivery
+	deliveryTag uint64
+	producerID  string
+}
+
+// NewChannelProducer creates a new ChannelProducer that sends the deliveries to the given channel.
+func NewChannelProducer(deliveries chan<- amqp.Delivery) *ChannelProducer {
+	producer := ChannelProducer{deliveries: deliveries, producerID: models.NewProducerID("postprocessor")}
+	return &producer
+}
+
+// PublishEvent sends an SMC event to the uploader.
+func (producer *ChannelProducer) PublishEvent(event models.SmcEvent) error {
+	dataToSend := models.DataUnit{DataType: models.Event, Data: event.Serialize()}
+	envelope := models.NewEnvelope(producer.producerID, event.RunID, dataToSend)
+	producer.send(envelope.Serialize())
+	return nil
+}
+
+// PublishConsumption sends a consumption data item to the uploader.
+func (producer *ChannelProducer) PublishConsumption(cons models.ConsumtionValue) error {
+	dataToSend := models.DataUnit{DataType: models.Consumption, Data: cons.Serialize()}
+	envelope := models.NewEnvelope(producer.producerID, cons.RunID, dataToSend)
+	producer.send(envelope.Serialize())
+	return nil
+}
+
+// PublishRunManifest sends the manifest of a processed run to the uploader.
+func (producer *ChannelProducer) PublishRunManifest(manifest models.RunManifest) error {
+	dataToSend := models.DataUnit{DataType: models.Manifest, Data: manifest.Serialize()}
+	envelope := models.NewEnvelope(producer.producerID, manifest.RunID, dataToSend)
+	producer.send(envelope.Serialize())
+	return nil
+}
+
+// Connect does nothing, the channel is ready to use when the producer is created.
+func (producer *ChannelProducer) Connect() {
+	// NOOP
+}
+
+// Flush does nothing, the data units are received by the uploader when they are sent.
+func (producer *ChannelProducer) Flush() error {
+	return nil
+}
+
+// CloseChannelAndConnection does nothing, the channel stays open while the pipeline runs.
+func (producer *ChannelProducer) CloseChannelAndConnection() {
+	// NOOP
+}
+
+// send blocks until the uploader has room for the data unit, so a slow uploader slows down the processing.
+// The data units are only sent from the processing goroutine, so the delivery tags need no locking.
+func (producer *ChannelProducer) send(body []byte) {
+	producer.deliveryTag++
+	producer.deliveries <- amqp.Delivery{
+		Acknowledger: channelAcknowledger{},
+		DeliveryTag:  producer.deliveryTag,
+		ContentType:  "application/json",
+		Body:         body,
+	}
+}
+
+// channelAcknowledger implements the Acknowledger interface of amqp for the in-memory deliveries,
+// there is nothing to acknowledge, because the deliveries are not kept after they are received.
+type channelAcknowledger struct{}
+
+func (channelAcknowledger) Ack(tag uint64, multiple bool) error {
+	return nil
+}
+
+func (channelAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	return nil
+}
+
+func (channelAcknowledger) Reject(tag uint64, requeue bool) error {
+	return nil
+}
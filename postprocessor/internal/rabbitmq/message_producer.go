@@ -3,9 +3,14 @@ package rabbitmq
 import "github.com/kozgot/go-log-processing/postprocessor/pkg/models"
 
 // MessageProducer encapsulates methods used to publish data for ES uploader service.
+// The publish methods return an error if the data unit could not be delivered, eg.: the broker is unreachable.
 type MessageProducer interface {
-	PublishEvent(event models.SmcEvent)
-	PublishConsumption(cons models.ConsumtionValue)
+	PublishEvent(event models.SmcEvent) error
+	PublishConsumption(cons models.ConsumtionValue) error
+	PublishRunManifest(manifest models.RunManifest) error
 	Connect()
+
+	// Flush waits until every published data unit is delivered to the message queue.
+	Flush() error
 	CloseChannelAndConnection()
 }
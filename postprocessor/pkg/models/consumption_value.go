@@ -15,6 +15,20 @@ type ConsumtionValue struct {
 	Value        int
 	ServiceLevel int
 	SmcUID       string
+	PodUID       string
+
+	// IndexBaseline is the last index value of the pod stored in the DB, if it was read before the consumption.
+	IndexBaseline *IndexBaseline
+
+	// IndexSinceBaseline is the growth of the index of the pod since the baseline.
+	IndexSinceBaseline int
+
+	// BaselineMismatch is true if the index of the pod is lower than the baseline,
+	// or the consumption is more than the growth of the index since the baseline.
+	BaselineMismatch bool
+
+	// RunID identifies the parser run of the entry the consumption was created from.
+	RunID string `json:",omitempty"`
 }
 
 // Serialize serlializes a consumption value to JSON format and returns a byte array.
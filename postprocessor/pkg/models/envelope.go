@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kozgot/go-log-processing/postprocessor/internal/utils"
+)
+
+// LegacySchemaVersion is the schema version of the messages published before the envelope was introduced,
+// these are bare DataUnit documents.
+const LegacySchemaVersion = 0
+
+// CurrentSchemaVersion is the schema version of the envelopes published by the postprocessor.
+const CurrentSchemaVersion = 1
+
+// ErrUnsupportedSchemaVersion is returned for envelopes of a newer schema version than the consumer knows.
+var ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+
+var errMissingPayload = errors.New("envelope has no payload")
+
+// Envelope wraps every data unit published to the processed data exchange.
+// It has the same layout as the envelope of the log entries published by the parser.
+type Envelope struct {
+	SchemaVersion int
+
+	// ProducerID identifies the service instance that published the message.
+	ProducerID string
+
+	// RunID identifies the parser run the message belongs to, it is empty if the producer does not know the run.
+	RunID string `json:",omitempty"`
+
+	// Timestamp is the time the message was published.
+	Timestamp time.Time
+
+	Payload json.RawMessage
+}
+
+// NewEnvelope creates an envelope of the current schema version for the data unit.
+func NewEnvelope(producerID string, runID string, data DataUnit) Envelope {
+	return Envelope{
+		SchemaVersion: CurrentSchemaVersion,
+		ProducerID:    producerID,
+		RunID:         runID,
+		Timestamp:     time.Now().UTC(),
+		Payload:       data.Serialize(),
+	}
+}
+
+// Serialize serializes an envelope.
+func (e *Envelope) Serialize() []byte {
+	bytes, err := json.Marshal(e)
+	utils.FailOnError(err, "  [RABBITMQ DATA] Can't serialize envelope")
+	return bytes
+}
+
+// DataUnit deserializes the data unit carried by the envelope.
+func (e *Envelope) DataUnit() (DataUnit, error) {
+	data := DataUnit{}
+	err := json.Unmarshal(e.Payload, &data)
+	return data, err
+}
+
+// OpenEnvelope deserializes the envelope of a received message.
+// Legacy data units without an envelope are upgraded to the current schema version,
+// envelopes of a newer schema version are rejected with ErrUnsupportedSchemaVersion.
+func OpenEnvelope(body []byte) (*Envelope, error) {
+	versionedMessage := struct{ SchemaVersion *int }{}
+	if err := json.Unmarshal(body, &versionedMessage); err != nil {
+		return nil, err
+	}
+
+	if versionedMessage.SchemaVersion == nil || *versionedMessage.SchemaVersion == LegacySchemaVersion {
+		envelope := Envelope{SchemaVersion: CurrentSchemaVersion, Payload: body}
+		return &envelope, nil
+	}
+
+	if *versionedMessage.SchemaVersion != CurrentSchemaVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSchemaVersion, *versionedMessage.SchemaVersion)
+	}
+
+	envelope := Envelope{}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+
+	if len(envelope.Payload) == 0 {
+		return nil, errMissingPayload
+	}
+
+	return &envelope, nil
+}
+
+// NewProducerID creates the ID of a service instance from the name of the service and the host name.
+func NewProducerID(serviceName string) string {
+	hostName, err := os.Hostname()
+	if err != nil {
+		return serviceName
+	}
+
+	return serviceName + "@" + hostName
+}
@@ -27,6 +27,9 @@ const (
 	ConfigurationUpdated
 	InternalDiagnostics
 	StatisticsSent
+	GenericEvent       // created from entries parsed by a declarative entry format of the parser
+	SmcStateChanged    // created from the VERBOSE state change entries, if the parser is configured to parse them
+	TaskLifecycleEvent // created from the task launch entries and task failed warnings at the end of the entries
 )
 
 func EventTypeToString(eventType EventType) string {
@@ -97,6 +100,15 @@ func EventTypeToString(eventType EventType) string {
 	case SmcAddressInvalidated:
 		return "SmcAddressInvalidated"
 
+	case GenericEvent:
+		return "GenericEvent"
+
+	case SmcStateChanged:
+		return "SmcStateChanged"
+
+	case TaskLifecycleEvent:
+		return "TaskLifecycleEvent"
+
 	default:
 		return "None"
 	}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// IndexBaseline contains the last index value of a pod stored in the DB, it is read by the DC at startup.
+// The index values received later from the SMCs are expected to grow from this value.
+type IndexBaseline struct {
+	ReceiveTime time.Time
+	Time        time.Time
+	Value       int
+	PodUID      string
+	SmcUID      string
+}
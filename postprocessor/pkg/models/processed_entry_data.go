@@ -6,4 +6,5 @@ type ProcessedEntryData struct {
 	SmcEvent        *SmcEvent
 	ConsumtionValue *ConsumtionValue
 	IndexValue      *IndexValue
+	IndexBaseline   *IndexBaseline
 }
@@ -34,4 +34,5 @@ const (
 	UnknownDataType DataType = iota
 	Event
 	Consumption
+	Manifest // the manifest of a processed parser run
 )
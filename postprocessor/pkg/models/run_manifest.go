@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kozgot/go-log-processing/postprocessor/internal/utils"
+)
+
+// RunManifest describes a parser run processed by the postprocessor, it is published when the run is finished.
+type RunManifest struct {
+	RunID string
+
+	// StartedAt and EndedAt are the times the parser published the run start and the run end.
+	StartedAt time.Time
+	EndedAt   time.Time
+
+	// FinishedAt is the time the postprocessor finished processing the run.
+	FinishedAt time.Time
+
+	// FileNames contains the names of the log files parsed in the run.
+	FileNames []string
+
+	// EntryCounts contains the number of entries published by the parser by file name.
+	EntryCounts map[string]int
+
+	ExpectedEntries int
+	ReceivedEntries int
+
+	// Complete is false if the run was finished before every entry of the run was received.
+	Complete bool
+
+	EventCount       int
+	ConsumptionCount int
+}
+
+// Serialize serializes a run manifest and returns a byte array.
+func (m *RunManifest) Serialize() []byte {
+	bytes, err := json.Marshal(m)
+	utils.FailOnError(err, "Can't serialize run manifest")
+
+	return bytes
+}
+
+// Deserialize deserializes a run manifest.
+func (m *RunManifest) Deserialize(bytes []byte) {
+	err := json.Unmarshal(bytes, m)
+	utils.FailOnError(err, "Cannot deserialize run manifest.")
+}
@@ -15,6 +15,19 @@ type SmcEvent struct {
 	Label           string
 	SmcUID          string
 	SMC             SmcData
+	Source          SourceLocation
+
+	// Fields contains the fields of generic events, that are parsed by a declarative entry format.
+	Fields map[string]interface{}
+
+	// StateTransition contains the change of the state bitmask of state change events.
+	StateTransition *StateTransition `json:",omitempty"`
+
+	// Task contains the lifecycle of the task of task lifecycle events.
+	Task *TaskLifecycle `json:",omitempty"`
+
+	// RunID identifies the parser run of the entry the event was created from.
+	RunID string `json:",omitempty"`
 }
 
 // Serialize serializes an smc event and returns a byte array.
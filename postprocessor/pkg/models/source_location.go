@@ -0,0 +1,9 @@
+package models
+
+// SourceLocation identifies the log line an event was created from.
+type SourceLocation struct {
+	FileName   string
+	LineNumber int64
+	ByteOffset int64
+	RawLine    string
+}
@@ -0,0 +1,17 @@
+package models
+
+// StateTransition contains the change of the state bitmask of an SMC,
+// the flags are named by the parser, see the SmcStateFlag type of the parser models.
+type StateTransition struct {
+	// PreviousState is the state bitmask before the transition, it is only valid if HasPreviousState is true.
+	PreviousState    int64
+	HasPreviousState bool
+	NewState         int64
+
+	// Flags contains the flags of the new state.
+	Flags []string
+
+	// SetFlags and ClearedFlags contain the flags that have been set and cleared by the transition.
+	SetFlags     []string
+	ClearedFlags []string
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// TaskStatus represents the outcome of a task of the task scheduler of the dc.
+type TaskStatus int64
+
+const (
+	// UnknownTaskStatus is the default value of TaskStatus.
+	UnknownTaskStatus TaskStatus = iota
+	TaskFinished                 // the thread of the last launch has moved on to the next task without a failure
+	TaskFailed                   // the task has failed after its last launch, or it has only been seen failing
+	TaskCreated                  // neither the launch nor the failure of the task has been logged
+	TaskLaunched                 // the task has been launched, its outcome has not been logged yet
+)
+
+// TaskStatusToString returns the string representation of a task status.
+func TaskStatusToString(status TaskStatus) string {
+	switch status {
+	case UnknownTaskStatus:
+		return "UnknownTaskStatus"
+	case TaskFinished:
+		return "TaskFinished"
+	case TaskFailed:
+		return "TaskFailed"
+	case TaskCreated:
+		return "TaskCreated"
+	case TaskLaunched:
+		return "TaskLaunched"
+	default:
+		return "None"
+	}
+}
+
+// TaskLifecycle contains the lifecycle of a task of the task scheduler, built from the task launch
+// entries and the task failed warnings of a DC with the same task UID.
+type TaskLifecycle struct {
+	DC       string // the directory of the log files of the DC writing the entries
+	UID      int
+	Name     string
+	Type     string // only logged by the task launch entries
+	Thread   string // the thread of the last launch of the task
+	SmcUID   string
+	Priority int
+
+	CreatedAt       time.Time
+	FirstLaunchedAt *time.Time
+	LastLaunchedAt  *time.Time
+	LastFailedAt    *time.Time
+
+	// EndedAt is the time the thread of the task launched the next task, it is nil if it has not been logged.
+	EndedAt *time.Time
+
+	Status       TaskStatus
+	StatusString string
+
+	Launches  int
+	Failures  int
+	Retry     int    // the highest retry counter logged for the task
+	LastError string // the message of the last failure
+
+	// QueueLatencySeconds is the time between the creation and the first launch of the task,
+	// it is zero if the launch of the task has not been logged.
+	QueueLatencySeconds float64
+}
@@ -0,0 +1,96 @@
+// Package service contains the postprocessor service, it processes the entries received from RabbitMQ,
+// or from an in-process parser in the pipeline mode.
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/kozgot/go-log-processing/postprocessor/internal/processing"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/rabbitmq"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/utils"
+	"github.com/streadway/amqp"
+)
+
+// Run processes the entries consumed from RabbitMQ, and publishes the processed data to RabbitMQ, until it is stopped.
+func Run() {
+	log.Println("PostProcessor service starting...")
+	rabbitMqURL := os.Getenv("RABBIT_URL")
+	fmt.Println("RABBIT_URL:", rabbitMqURL)
+	if len(rabbitMqURL) == 0 {
+		log.Fatal("The RABBIT_URL environment variable is not set")
+	}
+
+	saveDataExchangeName := os.Getenv("PROCESSED_DATA_EXCHANGE")
+	fmt.Println("PROCESSED_DATA_EXCHANGE:", saveDataExchangeName)
+	if len(saveDataExchangeName) == 0 {
+		log.Fatal("The PROCESSED_DATA_EXCHANGE environment variable is not set")
+	}
+
+	saveDataRoutingKey := os.Getenv("SAVE_DATA_ROUTING_KEY")
+	fmt.Println("SAVE_DATA_ROUTING_KEY:", saveDataRoutingKey)
+	if len(saveDataRoutingKey) == 0 {
+		log.Fatal("The SAVE_DATA_ROUTING_KEY environment variable is not set")
+	}
+
+	processEntriesExchangeName := os.Getenv("LOG_ENTRIES_EXCHANGE")
+	fmt.Println("LOG_ENTRIES_EXCHANGE:", processEntriesExchangeName)
+	if len(processEntriesExchangeName) == 0 {
+		log.Fatal("The LOG_ENTRIES_EXCHANGE environment variable is not set")
+	}
+
+	processingQueueName := os.Getenv("PROCESSING_QUEUE")
+	fmt.Println("PROCESSING_QUEUE:", processingQueueName)
+	if len(processingQueueName) == 0 {
+		log.Fatal("The PROCESSING_QUEUE environment variable is not set")
+	}
+
+	processEntryRoutingKey := os.Getenv("PROCESS_ENTRY_ROUTING_KEY")
+	fmt.Println("PROCESS_ENTRY_ROUTING_KEY:", processEntryRoutingKey)
+	if len(processEntryRoutingKey) == 0 {
+		log.Fatal("The PROCESS_ENTRY_ROUTING_KEY environment variable is not set")
+	}
+
+	// Init message consumer.
+	rabbitMQConsumer := rabbitmq.NewAmqpConsumer(
+		rabbitMqURL,
+		processEntryRoutingKey,
+		processEntriesExchangeName,
+		processingQueueName)
+
+	// Open consumer channel and connection.
+	rabbitMQConsumer.Connect()
+	defer rabbitMQConsumer.CloseConnectionAndChannel()
+
+	// Init message producer.
+	rabbitMqProducer := rabbitmq.NewAmqpProducer(
+		rabbitMqURL,
+		saveDataExchangeName,
+		saveDataRoutingKey)
+
+	// Open producer channel and connection.
+	rabbitMqProducer.Connect()
+	defer rabbitMqProducer.CloseChannelAndConnection()
+
+	processor := processing.NewEntryProcessor(rabbitMqProducer, rabbitMQConsumer)
+	errs := processor.HandleEntries()
+
+	log.Printf(" [POSTPROCESSOR] Waiting for messages. To exit press CTRL+C...")
+
+	// The unacknowledged messages are delivered again when the service is restarted.
+	err := <-errs
+	utils.FailOnError(err, " [POSTPROCESSOR] Could not process the entries")
+	log.Printf(" [POSTPROCESSOR] The consumer has no more messages, stopping...")
+}
+
+// StartInProcess starts processing the entries received on the entries channel in the background,
+// the processed data is sent as deliveries to the data channel, in the same format as it is published to RabbitMQ.
+func StartInProcess(entries <-chan amqp.Delivery, data chan<- amqp.Delivery) {
+	processor := processing.NewEntryProcessor(rabbitmq.NewChannelProducer(data), rabbitmq.NewChannelConsumer(entries))
+
+	// The in-memory producer cannot fail, the processing only stops when the entries channel is closed.
+	processor.HandleEntries()
+
+	log.Printf(" [POSTPROCESSOR] Processing entries in process...")
+}
@@ -1,11 +1,14 @@
 package mocks
 
-// MockAcknowledger implements the Acknowledger interface of amqp.
+// MockAcknowledger implements the Acknowledger interface of amqp, it records how the delivery was settled.
 type MockAcknowledger struct {
+	Acked    bool
+	Rejected bool
 }
 
 // Ack is the implementation of the Ack() function of the Acknowledger interface.
 func (m *MockAcknowledger) Ack(tag uint64, multiple bool) error {
+	m.Acked = true
 	return nil
 }
 
@@ -16,5 +19,6 @@ func (m *MockAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
 
 // Reject is the implementation of the Reject() function of the Acknowledger interface.
 func (m *MockAcknowledger) Reject(tag uint64, requeue bool) error {
+	m.Rejected = true
 	return nil
 }
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
 	"github.com/kozgot/go-log-processing/postprocessor/tests/testmodels"
 	"github.com/streadway/amqp"
 )
@@ -8,21 +9,54 @@ import (
 // MockMessageConsumer mocks a message consumer, implements the MessageConsumer interface.
 type MockMessageConsumer struct {
 	TestParsedLogFile testmodels.TestParsedLogFile
+
+	// Legacy makes the mock send bare entries and END messages without an envelope,
+	// like the parser did before the envelope was introduced.
+	Legacy bool
+
+	// LateEntries is the number of entries sent after the run end, like entries redelivered by RabbitMQ.
+	LateEntries int
 }
 
+const mockRunID = "mock-run"
+
 // ConsumeMessages creates a channel from the parsed log file of the MockMessageConsumer.
 func (m *MockMessageConsumer) ConsumeMessages() <-chan amqp.Delivery {
-	deliveries := make(chan amqp.Delivery, 100)
+	lines := m.TestParsedLogFile.Lines
+	deliveries := make(chan amqp.Delivery, len(lines)+2)
+
+	deliveryTag := uint64(0)
+	send := func(data []byte) {
+		deliveryTag++
+		deliveries <- NewMockDelivery(data, deliveryTag)
+	}
 
-	for i, entry := range m.TestParsedLogFile.Lines {
-		data := entry.Serialize()
+	if m.Legacy {
+		for _, entry := range lines {
+			send(entry.Serialize())
+		}
 
-		mockDelivery := NewMockDelivery(data, uint64(i+1))
-		deliveries <- mockDelivery
+		send([]byte("END"))
+		return deliveries
 	}
 
-	doneDelivery := NewMockDelivery([]byte("END"), uint64(len(m.TestParsedLogFile.Lines)+1))
-	deliveries <- doneDelivery
+	runStart := parsermodels.NewRunControlEnvelope("mock-producer", parsermodels.NewRunStart(mockRunID, []string{}))
+	send(runStart.Serialize())
+
+	onTimeEntries := len(lines) - m.LateEntries
+	for _, entry := range lines[:onTimeEntries] {
+		envelope := parsermodels.NewEnvelope("mock-producer", mockRunID, entry.Serialize())
+		send(envelope.Serialize())
+	}
+
+	runEnd := parsermodels.NewRunControlEnvelope("mock-producer",
+		parsermodels.NewRunEnd(mockRunID, []string{}, m.TestParsedLogFile.EntryCounts()))
+	send(runEnd.Serialize())
+
+	for _, entry := range lines[onTimeEntries:] {
+		envelope := parsermodels.NewEnvelope("mock-producer", mockRunID, entry.Serialize())
+		send(envelope.Serialize())
+	}
 
 	return deliveries
 }
@@ -7,6 +7,7 @@ import (
 
 type MockMessageProducer struct {
 	Data               testmodels.TestProcessedData
+	Manifests          []models.RunManifest
 	done               chan string
 	expectedDataCount  int
 	publishedDataCount int
@@ -27,23 +28,39 @@ func NewMockMessageProducer(
 
 // PublishEvent is the implementation of the PublishEvent(event models.SmcEvent)
 // function of the MessageProducer interface.
-func (m *MockMessageProducer) PublishEvent(event models.SmcEvent) {
+func (m *MockMessageProducer) PublishEvent(event models.SmcEvent) error {
 	m.Data.Events = append(m.Data.Events, event)
 	m.publishedDataCount++
 	if m.publishedDataCount == m.expectedDataCount {
 		m.done <- "DONE"
 	}
+
+	return nil
 }
 
 // PublishConsumption is the implementation
 // of the PublishConsumption(cons models.ConsumtionValue)
 // function of the MessageProducer interface.
-func (m *MockMessageProducer) PublishConsumption(cons models.ConsumtionValue) {
+func (m *MockMessageProducer) PublishConsumption(cons models.ConsumtionValue) error {
 	m.Data.Consumptions = append(m.Data.Consumptions, cons)
 	m.publishedDataCount++
 	if m.publishedDataCount == m.expectedDataCount {
 		m.done <- "DONE"
 	}
+
+	return nil
+}
+
+// PublishRunManifest is the implementation of the PublishRunManifest(manifest models.RunManifest)
+// function of the MessageProducer interface.
+func (m *MockMessageProducer) PublishRunManifest(manifest models.RunManifest) error {
+	m.Manifests = append(m.Manifests, manifest)
+	m.publishedDataCount++
+	if m.publishedDataCount == m.expectedDataCount {
+		m.done <- "DONE"
+	}
+
+	return nil
 }
 
 // Connect is the implementation of the Connect() function of the MessageProducer interface.
@@ -52,6 +69,11 @@ func (m *MockMessageProducer) Connect() {
 
 }
 
+// Flush is the implementation of the Flush() function of the MessageProducer interface.
+func (m *MockMessageProducer) Flush() error {
+	return nil
+}
+
 // CloseChannelAndConnection is the implementation of
 // the CloseChannelAndConnection() function of the MessageProducer interface.
 func (m *MockMessageProducer) CloseChannelAndConnection() {
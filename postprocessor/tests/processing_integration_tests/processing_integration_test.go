@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
 	"github.com/kozgot/go-log-processing/postprocessor/internal/processing"
 	"github.com/kozgot/go-log-processing/postprocessor/internal/rabbitmq"
 	"github.com/kozgot/go-log-processing/postprocessor/internal/utils"
@@ -190,8 +191,11 @@ func getSentProcessedData(
 	}
 	gotMessageCount := 0
 	for delivery := range deliveries {
-		dataUnit := models.DataUnit{}
-		dataUnit.Deserialize(delivery.Body)
+		envelope, err := models.OpenEnvelope(delivery.Body)
+		utils.FailOnError(err, "Could not open envelope")
+
+		dataUnit, err := envelope.DataUnit()
+		utils.FailOnError(err, "Could not deserialize data unit")
 		switch dataUnit.DataType {
 		case models.UnknownDataType:
 			break
@@ -212,7 +216,7 @@ func getSentProcessedData(
 		}
 
 		// Acknowledge message
-		err := delivery.Ack(false)
+		err = delivery.Ack(false)
 		utils.FailOnError(err, "Could not acknowledge message")
 	}
 
@@ -224,12 +228,15 @@ func sendTestInput(
 	testInputProducer *testutils.TestRabbitMqProducer,
 	testparsedFile testmodels.TestParsedLogFile,
 ) {
+	const runID = "test-run"
+	testInputProducer.PublishRunControl(parsermodels.NewRunStart(runID, []string{}))
+
 	for _, parsedEntry := range testparsedFile.Lines {
-		testInputProducer.PublishEntry(parsedEntry)
+		testInputProducer.PublishEntry(runID, parsedEntry)
 	}
 
 	// Send a message indicating that this is the end of the entries.
-	testInputProducer.PublishStringMessage("END")
+	testInputProducer.PublishRunControl(parsermodels.NewRunEnd(runID, []string{}, testparsedFile.EntryCounts()))
 }
 
 func updateResourcesIfEnabled(resourceFileName string, newData []byte) {
@@ -0,0 +1,151 @@
+package processingunittests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/processing"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/tests/mocks"
+	"github.com/kozgot/go-log-processing/postprocessor/tests/testmodels"
+)
+
+func TestProcessLastIndexEntry(t *testing.T) {
+	infoProcessor := processing.InfoProcessor{
+		PodUIDToSmcUID:         map[string]string{"1478": "dc18-smc3"},
+		IndexBaselinesByPodUID: make(map[string][]models.IndexBaseline),
+	}
+
+	entry := parsermodels.ParsedLogEntry{
+		Timestamp: time.Date(2020, time.June, 10, 9, 18, 30, 0, time.UTC),
+		Level:     "INFO",
+		InfoParams: &parsermodels.InfoParams{
+			EntryType: parsermodels.DCMessage,
+			DCMessage: &parsermodels.DCMessageParams{
+				IsInComing:       true,
+				SourceOrDestName: "DB",
+				MessageType:      parsermodels.LastIndex,
+				Payload: &parsermodels.DcMessagePayload{
+					PodUID: "1478",
+					Value:  14989,
+					Time:   time.Date(2020, time.June, 10, 8, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	data, event, consumption, indexvalue := infoProcessor.ProcessInfoEntry(entry)
+	if data != nil || event != nil || consumption != nil || indexvalue != nil {
+		t.Fatal("Expected no data, event, consumption or index value for a last index entry")
+	}
+
+	expectedBaselines := map[string][]models.IndexBaseline{
+		"1478": {{
+			ReceiveTime: time.Date(2020, time.June, 10, 9, 18, 30, 0, time.UTC),
+			Time:        time.Date(2020, time.June, 10, 8, 0, 0, 0, time.UTC),
+			Value:       14989,
+			PodUID:      "1478",
+			SmcUID:      "dc18-smc3",
+		}},
+	}
+	if !reflect.DeepEqual(infoProcessor.IndexBaselinesByPodUID, expectedBaselines) {
+		t.Fatalf("Expected index baselines %+v, got %+v", expectedBaselines, infoProcessor.IndexBaselinesByPodUID)
+	}
+}
+
+func TestCheckConsumptionAgainstIndexBaseline(t *testing.T) {
+	startupTime := time.Date(2020, time.June, 10, 9, 18, 30, 0, time.UTC)
+	baseline := models.IndexBaseline{ReceiveTime: startupTime, Value: 14989, PodUID: "1478", SmcUID: "dc18-smc3"}
+
+	indexValues := []models.IndexValue{}
+	consumptionValues := []models.ConsumtionValue{}
+	for i, value := range []int{15000, 14900} {
+		receiveTime := startupTime.Add(time.Duration(i+1) * time.Hour)
+		startTime := receiveTime.Add(-10 * time.Minute)
+		indexValues = append(indexValues, models.IndexValue{
+			ReceiveTime:  receiveTime,
+			PreviousTime: startTime,
+			Value:        value,
+			ServiceLevel: 9,
+			PodUID:       "1478",
+			SmcUID:       "dc18-smc3",
+		})
+		consumptionValues = append(consumptionValues, models.ConsumtionValue{
+			ReceiveTime:  receiveTime,
+			StartTime:    startTime,
+			Value:        5,
+			ServiceLevel: 9,
+		})
+	}
+
+	producer := mocks.NewMockMessageProducer(testmodels.TestProcessedData{}, nil, 0)
+	consumptionProcessor := processing.NewConsumptionProcessor(
+		consumptionValues,
+		indexValues,
+		map[string][]models.IndexBaseline{"1478": {baseline}},
+		producer,
+	)
+	publishedCount, err := consumptionProcessor.ProcessConsumptionAndIndexValues()
+	if err != nil {
+		t.Fatalf("Could not publish the consumption values: %s", err)
+	}
+
+	published := producer.Data.Consumptions
+	if len(published) != 2 || publishedCount != 2 {
+		t.Fatalf("Expected 2 published consumption values, got %d and a count of %d", len(published), publishedCount)
+	}
+
+	// The index grew by 11 since the baseline, which covers the consumption.
+	if published[0].PodUID != "1478" || published[0].IndexBaseline == nil ||
+		published[0].IndexSinceBaseline != 11 || published[0].BaselineMismatch {
+		t.Fatalf("Unexpected first consumption value: %+v", published[0])
+	}
+
+	// The index is lower than the baseline.
+	if published[1].IndexSinceBaseline != -89 || !published[1].BaselineMismatch {
+		t.Fatalf("Expected a baseline mismatch, got %+v", published[1])
+	}
+}
+
+// TestUnsortedIndexBaselines checks that the consumption is compared to the last baseline read before it,
+// even if the baselines were received out of order.
+func TestUnsortedIndexBaselines(t *testing.T) {
+	startupTime := time.Date(2020, time.June, 10, 9, 18, 30, 0, time.UTC)
+	baselines := []models.IndexBaseline{
+		{ReceiveTime: startupTime.Add(3 * time.Hour), Value: 15100, PodUID: "1478", SmcUID: "dc18-smc3"},
+		{ReceiveTime: startupTime.Add(time.Hour), Value: 14995, PodUID: "1478", SmcUID: "dc18-smc3"},
+		{ReceiveTime: startupTime, Value: 14989, PodUID: "1478", SmcUID: "dc18-smc3"},
+	}
+
+	receiveTime := startupTime.Add(2 * time.Hour)
+	startTime := receiveTime.Add(-10 * time.Minute)
+	indexValue := models.IndexValue{
+		ReceiveTime:  receiveTime,
+		PreviousTime: startTime,
+		Value:        15000,
+		ServiceLevel: 9,
+		PodUID:       "1478",
+		SmcUID:       "dc18-smc3",
+	}
+	consumption := models.ConsumtionValue{ReceiveTime: receiveTime, StartTime: startTime, Value: 5, ServiceLevel: 9}
+
+	producer := mocks.NewMockMessageProducer(testmodels.TestProcessedData{}, nil, 0)
+	consumptionProcessor := processing.NewConsumptionProcessor(
+		[]models.ConsumtionValue{consumption},
+		[]models.IndexValue{indexValue},
+		map[string][]models.IndexBaseline{"1478": baselines},
+		producer,
+	)
+	_, err := consumptionProcessor.ProcessConsumptionAndIndexValues()
+	if err != nil {
+		t.Fatalf("Could not publish the consumption values: %s", err)
+	}
+
+	published := producer.Data.Consumptions
+	if len(published) != 1 || published[0].IndexBaseline == nil || published[0].IndexBaseline.Value != 14995 ||
+		published[0].IndexSinceBaseline != 5 || published[0].BaselineMismatch {
+		t.Fatalf("Expected the consumption to be compared to the baseline read an hour after the startup, got %+v", published)
+	}
+}
@@ -0,0 +1,72 @@
+package processingunittests
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/processing"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/tests/testutils"
+)
+
+type genericProcessorTest struct {
+	inputEntry       parsermodels.ParsedLogEntry
+	expectedSmcData  *models.SmcData
+	expectedSmcEvent *models.SmcEvent
+}
+
+func TestProcessGenericEntries(t *testing.T) {
+	genericTests := []genericProcessorTest{
+		{
+			inputEntry: parsermodels.ParsedLogEntry{
+				Timestamp: time.Date(2020, time.June, 10, 14, 55, 31, 0, time.UTC),
+				Level:     "INFO",
+			},
+			expectedSmcData:  nil,
+			expectedSmcEvent: nil,
+		},
+		{
+			inputEntry: parsermodels.ParsedLogEntry{
+				Timestamp: time.Date(2020, time.June, 10, 10, 26, 37, 0, time.UTC),
+				Level:     "INFO",
+				GenericParams: &parsermodels.GenericParams{
+					FormatName: "firmware_update",
+					EntryType:  "FirmwareUpdate",
+					Fields: map[string]interface{}{
+						"SmcUID":  "dc18-smc32",
+						"Version": "IMETER190530",
+					},
+				},
+			},
+			expectedSmcData: &models.SmcData{
+				SmcUID: "dc18-smc32",
+			},
+			expectedSmcEvent: &models.SmcEvent{
+				Time:            time.Date(2020, time.June, 10, 10, 26, 37, 0, time.UTC),
+				EventType:       models.GenericEvent,
+				EventTypeString: models.EventTypeToString(models.GenericEvent),
+				Label:           "FirmwareUpdate",
+				SmcUID:          "dc18-smc32",
+				SMC: models.SmcData{
+					SmcUID: "dc18-smc32",
+				},
+				Fields: map[string]interface{}{
+					"SmcUID":  "dc18-smc32",
+					"Version": "IMETER190530",
+				},
+			},
+		},
+	}
+
+	for i, test := range genericTests {
+		genericProcessor := processing.GenericProcessor{}
+		data, event := genericProcessor.ProcessGeneric(test.inputEntry)
+
+		testutils.AssertEqualSmcData(data, test.expectedSmcData, t, i)
+		testutils.AssertEqualSmcEvent(event, test.expectedSmcEvent, t, i)
+	}
+
+	log.Printf("Successfully run %d tests", len(genericTests))
+}
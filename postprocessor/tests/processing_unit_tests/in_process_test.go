@@ -0,0 +1,74 @@
+package processingunittests
+
+import (
+	"io/ioutil"
+	"testing"
+
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/processing"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/rabbitmq"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/utils"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/tests/mocks"
+	"github.com/kozgot/go-log-processing/postprocessor/tests/testmodels"
+	"github.com/streadway/amqp"
+)
+
+// TestProcessEntriesInProcess checks that the entries received on in-memory channels
+// are processed the same way as the entries received from RabbitMQ.
+func TestProcessEntriesInProcess(t *testing.T) {
+	parsedInputBytes, err := ioutil.ReadFile("./resources/parsed_test_dc_main.json")
+	utils.FailOnError(err, "Could not open test input")
+
+	testData := testmodels.TestParsedLogFile{}
+	testData.FromJSON(parsedInputBytes)
+
+	entries := make(chan amqp.Delivery, len(testData.Lines)+2)
+	processedData := make(chan amqp.Delivery, 100)
+
+	startEnvelope := parsermodels.NewRunControlEnvelope("parser@test", parsermodels.NewRunStart("run-1", []string{}))
+	entries <- mocks.NewMockDelivery(startEnvelope.Serialize(), 1)
+
+	for i, entry := range testData.Lines {
+		envelope := parsermodels.NewEnvelope("parser@test", "run-1", entry.Serialize())
+		entries <- mocks.NewMockDelivery(envelope.Serialize(), uint64(i+2))
+	}
+
+	endEnvelope := parsermodels.NewRunControlEnvelope("parser@test",
+		parsermodels.NewRunEnd("run-1", []string{}, testData.EntryCounts()))
+	entries <- mocks.NewMockDelivery(endEnvelope.Serialize(), uint64(len(testData.Lines)+2))
+
+	processor := processing.NewEntryProcessor(
+		rabbitmq.NewChannelProducer(processedData),
+		rabbitmq.NewChannelConsumer(entries),
+	)
+	processor.HandleEntries()
+
+	actualData := testmodels.TestProcessedData{
+		Events:       []models.SmcEvent{},
+		Consumptions: []models.ConsumtionValue{},
+	}
+	for len(actualData.Events) < 23 {
+		delivery := <-processedData
+		envelope, err := models.OpenEnvelope(delivery.Body)
+		utils.FailOnError(err, "Could not open envelope")
+
+		dataUnit, err := envelope.DataUnit()
+		utils.FailOnError(err, "Could not deserialize data unit")
+
+		if dataUnit.DataType != models.Event {
+			t.Fatalf("Unexpected data type: %d", dataUnit.DataType)
+		}
+
+		event := models.SmcEvent{}
+		event.Deserialize(dataUnit.Data)
+		actualData.Events = append(actualData.Events, event)
+	}
+
+	expectedBytes, err := ioutil.ReadFile("./resources/expected_processed_dc_main.json")
+	utils.FailOnError(err, "Could not read expected processed data")
+
+	if string(actualData.ToJSON()) != string(expectedBytes) {
+		t.Fatalf("Expected json does not match actual json value of the data processed in process")
+	}
+}
@@ -19,6 +19,9 @@ type postProcessorTest struct {
 	expectedDataFile         string
 	expectedEventCount       int
 	expectedConsumptionCount int
+
+	// legacy entries are sent without an envelope, they are upgraded by the processor.
+	legacy bool
 }
 
 func TestProcessEntries(t *testing.T) {
@@ -35,6 +38,13 @@ func TestProcessEntries(t *testing.T) {
 			expectedEventCount:       19,
 			expectedConsumptionCount: 0,
 		},
+		{
+			inputDataFile:            "./resources/parsed_test_dc_main.json",
+			expectedDataFile:         "./resources/expected_processed_dc_main.json",
+			expectedEventCount:       23,
+			expectedConsumptionCount: 0,
+			legacy:                   true,
+		},
 	}
 
 	for index, test := range postProcessorTests {
@@ -56,7 +66,7 @@ func TestProcessEntries(t *testing.T) {
 
 		testData := testmodels.TestParsedLogFile{}
 		testData.FromJSON(parsedInputBytes)
-		mockMessageConsumer := mocks.MockMessageConsumer{TestParsedLogFile: testData}
+		mockMessageConsumer := mocks.MockMessageConsumer{TestParsedLogFile: testData, Legacy: test.legacy}
 
 		// Run processor
 		processor := processing.NewEntryProcessor(
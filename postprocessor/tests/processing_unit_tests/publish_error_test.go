@@ -0,0 +1,46 @@
+package processingunittests
+
+import (
+	"errors"
+	"testing"
+
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/processing"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/tests/mocks"
+	"github.com/kozgot/go-log-processing/postprocessor/tests/testmodels"
+)
+
+var errBrokerUnreachable = errors.New("broker unreachable")
+
+// eventFailingProducer publishes the consumption values and the manifests, but fails to publish the events.
+type eventFailingProducer struct {
+	mocks.MockMessageProducer
+}
+
+func (producer *eventFailingProducer) PublishEvent(event models.SmcEvent) error {
+	return errBrokerUnreachable
+}
+
+// TestFailedPublishing checks that the processing stops with the publishing error,
+// and the run is not finished, so its end is not acknowledged.
+func TestFailedPublishing(t *testing.T) {
+	testData := testmodels.TestParsedLogFile{Lines: []parsermodels.ParsedLogEntry{
+		taskLaunchEntry("dc_main.log", 10, 1, 0, "1"),
+	}}
+	testData.Lines[0].Source = parsermodels.SourceLocation{FileName: "dc_main.log", LineNumber: 1}
+	testData.Lines[0].RunID = "mock-run"
+
+	producer := eventFailingProducer{}
+	mockMessageConsumer := mocks.MockMessageConsumer{TestParsedLogFile: testData}
+
+	processor := processing.NewEntryProcessor(&producer, &mockMessageConsumer)
+	err := <-processor.HandleEntries()
+	if !errors.Is(err, errBrokerUnreachable) {
+		t.Fatalf("Expected the publishing error, got %v", err)
+	}
+
+	if len(producer.Manifests) != 0 {
+		t.Fatalf("Expected no manifest for the unfinished run, got %d", len(producer.Manifests))
+	}
+}
@@ -0,0 +1,243 @@
+package processingunittests
+
+import (
+	"testing"
+	"time"
+
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/processing"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/tests/mocks"
+	"github.com/kozgot/go-log-processing/postprocessor/tests/testmodels"
+	"github.com/streadway/amqp"
+)
+
+// TestWaitForLateEntries checks that a run is only finished when every entry promised in the run end is received,
+// the lifecycle of the task and the manifest of the run are published when the late entries are processed too.
+func TestWaitForLateEntries(t *testing.T) {
+	testData := testmodels.TestParsedLogFile{Lines: []parsermodels.ParsedLogEntry{
+		taskLaunchEntry("dc_main.log", 10, 1, 0, "1"),
+		taskFailedEntry("dc_main.log", 12, 1, 1),
+		taskLaunchEntry("dc_main.log", 20, 1, 1, "1"),
+	}}
+	for i := range testData.Lines {
+		testData.Lines[i].Source = parsermodels.SourceLocation{FileName: "dc_main.log", LineNumber: int64(i + 1)}
+		testData.Lines[i].RunID = "mock-run"
+	}
+
+	done := make(chan string)
+	mockMessageProducer := mocks.NewMockMessageProducer(
+		testmodels.TestProcessedData{Events: []models.SmcEvent{}, Consumptions: []models.ConsumtionValue{}},
+		done,
+		2,
+	)
+	mockMessageConsumer := mocks.MockMessageConsumer{TestParsedLogFile: testData, LateEntries: 2}
+
+	processor := processing.NewEntryProcessor(mockMessageProducer, &mockMessageConsumer)
+	processor.HandleEntries()
+
+	<-done
+
+	lifecycleEvent := mockMessageProducer.Data.Events[0]
+	if lifecycleEvent.EventType != models.TaskLifecycleEvent {
+		t.Fatalf("Expected a task lifecycle event, got %s", lifecycleEvent.EventTypeString)
+	}
+
+	lifecycle := lifecycleEvent.Task
+	if lifecycle.Launches != 2 || lifecycle.Failures != 1 || lifecycle.Status != models.TaskLaunched {
+		t.Fatalf("Expected the lifecycle of every entry of the run, got %+v", lifecycle)
+	}
+
+	if lifecycleEvent.RunID != "mock-run" {
+		t.Errorf("Expected the lifecycle event to carry run ID mock-run, got %q", lifecycleEvent.RunID)
+	}
+
+	manifest := mockMessageProducer.Manifests[0]
+	if manifest.RunID != "mock-run" || !manifest.Complete || manifest.ExpectedEntries != 3 ||
+		manifest.ReceivedEntries != 3 || manifest.EntryCounts["dc_main.log"] != 3 || manifest.EventCount != 1 {
+		t.Errorf("Unexpected run manifest: %s", manifest.Serialize())
+	}
+
+	if manifest.StartedAt.IsZero() || manifest.EndedAt.Before(manifest.StartedAt) ||
+		manifest.FinishedAt.Before(manifest.EndedAt) {
+		t.Errorf("Unexpected times in the run manifest: %s", manifest.Serialize())
+	}
+}
+
+// deliveryConsumer sends the given messages, and closes the channel of the deliveries.
+// The sent deliveries are kept, so the tests can check how they were settled.
+type deliveryConsumer struct {
+	bodies     [][]byte
+	deliveries []amqp.Delivery
+}
+
+func (consumer *deliveryConsumer) ConsumeMessages() <-chan amqp.Delivery {
+	deliveries := make(chan amqp.Delivery, len(consumer.bodies))
+	for i, body := range consumer.bodies {
+		delivery := mocks.NewMockDelivery(body, uint64(i+1))
+		consumer.deliveries = append(consumer.deliveries, delivery)
+		deliveries <- delivery
+	}
+
+	close(deliveries)
+	return deliveries
+}
+
+// acknowledger returns the acknowledger of the i-th sent delivery.
+func (consumer *deliveryConsumer) acknowledger(i int) *mocks.MockAcknowledger {
+	return consumer.deliveries[i].Acknowledger.(*mocks.MockAcknowledger)
+}
+
+func (consumer *deliveryConsumer) CloseConnectionAndChannel() {}
+func (consumer *deliveryConsumer) Connect()                   {}
+
+// TestDropEntriesOfOtherRuns checks that the entries of another run, and the entries of the previous run
+// redelivered after it has been finished, are not processed in the tracked run.
+func TestDropEntriesOfOtherRuns(t *testing.T) {
+	entry := taskLaunchEntry("dc_main.log", 10, 1, 0, "1")
+	entry.RunID = "run-1"
+	otherEntry := taskLaunchEntry("dc_main.log", 12, 2, 0, "1")
+	otherEntry.RunID = "run-0"
+
+	runStart := parsermodels.NewRunControlEnvelope("parser@test", parsermodels.NewRunStart("run-1", []string{}))
+	otherEnvelope := parsermodels.NewEnvelope("parser@test", "run-0", otherEntry.Serialize())
+	envelope := parsermodels.NewEnvelope("parser@test", "run-1", entry.Serialize())
+	runEnd := parsermodels.NewRunControlEnvelope("parser@test",
+		parsermodels.NewRunEnd("run-1", []string{}, map[string]int{"dc_main.log": 1}))
+
+	// The entry of run-1 is redelivered after the run end.
+	consumer := deliveryConsumer{bodies: [][]byte{
+		runStart.Serialize(),
+		otherEnvelope.Serialize(),
+		envelope.Serialize(),
+		runEnd.Serialize(),
+		envelope.Serialize(),
+	}}
+	mockMessageProducer := mocks.NewMockMessageProducer(
+		testmodels.TestProcessedData{Events: []models.SmcEvent{}, Consumptions: []models.ConsumtionValue{}},
+		nil,
+		0,
+	)
+
+	processor := processing.NewEntryProcessor(mockMessageProducer, &consumer)
+	for err := range processor.HandleEntries() {
+		t.Fatalf("Could not process the entries: %s", err)
+	}
+
+	if len(mockMessageProducer.Manifests) != 1 {
+		t.Fatalf("Expected 1 run manifest, got %d", len(mockMessageProducer.Manifests))
+	}
+
+	manifest := mockMessageProducer.Manifests[0]
+	if manifest.RunID != "run-1" || !manifest.Complete || manifest.ReceivedEntries != 1 || manifest.EventCount != 1 {
+		t.Errorf("Unexpected run manifest: %s", manifest.Serialize())
+	}
+
+	lifecycle := mockMessageProducer.Data.Events[0].Task
+	if lifecycle.UID != 1 || lifecycle.Launches != 1 {
+		t.Errorf("Expected the lifecycle of the only task of run-1, got %+v", lifecycle)
+	}
+}
+
+// TestEntriesBeforeRunStart checks that the entries received before the start of their run
+// are counted in the run, so the run is finished when its end is received.
+func TestEntriesBeforeRunStart(t *testing.T) {
+	entry := taskLaunchEntry("dc_main.log", 10, 1, 0, "1")
+	entry.RunID = "run-1"
+
+	envelope := parsermodels.NewEnvelope("parser@test", "run-1", entry.Serialize())
+	runStart := parsermodels.NewRunControlEnvelope("parser@test", parsermodels.NewRunStart("run-1", []string{"dc_main.log"}))
+	runEnd := parsermodels.NewRunControlEnvelope("parser@test",
+		parsermodels.NewRunEnd("run-1", []string{"dc_main.log"}, map[string]int{"dc_main.log": 1}))
+
+	consumer := deliveryConsumer{bodies: [][]byte{
+		envelope.Serialize(),
+		runStart.Serialize(),
+		runEnd.Serialize(),
+	}}
+	mockMessageProducer := mocks.NewMockMessageProducer(
+		testmodels.TestProcessedData{Events: []models.SmcEvent{}, Consumptions: []models.ConsumtionValue{}},
+		nil,
+		0,
+	)
+
+	processor := processing.NewEntryProcessor(mockMessageProducer, &consumer)
+	for err := range processor.HandleEntries() {
+		t.Fatalf("Could not process the entries: %s", err)
+	}
+
+	if len(mockMessageProducer.Manifests) != 1 {
+		t.Fatalf("Expected 1 run manifest, got %d", len(mockMessageProducer.Manifests))
+	}
+
+	manifest := mockMessageProducer.Manifests[0]
+	if manifest.RunID != "run-1" || !manifest.Complete || manifest.ReceivedEntries != 1 || manifest.EventCount != 1 ||
+		manifest.StartedAt.IsZero() {
+		t.Errorf("Unexpected run manifest: %s", manifest.Serialize())
+	}
+
+	if !consumer.acknowledger(2).Acked {
+		t.Error("Expected the run end to be acknowledged")
+	}
+}
+
+// TestLegacyEndMessage checks that only the END string message finishes the runs of the old parsers,
+// and not the entries containing END.
+func TestLegacyEndMessage(t *testing.T) {
+	entry := parsermodels.ParsedLogEntry{
+		Timestamp: time.Date(2020, time.June, 10, 10, 26, 37, 0, time.UTC),
+		Level:     "ERROR",
+		ErrorParams: &parsermodels.ErrorParams{
+			Source:   "dc18-smc32",
+			Message:  "SEND failed",
+			Severity: 3,
+		},
+	}
+
+	consumer := deliveryConsumer{bodies: [][]byte{entry.Serialize(), []byte("END")}}
+	mockMessageProducer := mocks.NewMockMessageProducer(
+		testmodels.TestProcessedData{Events: []models.SmcEvent{}, Consumptions: []models.ConsumtionValue{}},
+		nil,
+		0,
+	)
+
+	processor := processing.NewEntryProcessor(mockMessageProducer, &consumer)
+	for err := range processor.HandleEntries() {
+		t.Fatalf("Could not process the entries: %s", err)
+	}
+
+	if len(mockMessageProducer.Data.Events) != 1 || mockMessageProducer.Data.Events[0].SmcUID != "dc18-smc32" {
+		t.Fatalf("Expected the event of the entry, got %+v", mockMessageProducer.Data.Events)
+	}
+}
+
+// TestRejectUnknownStringMessage checks that a string message other than END is rejected,
+// and the processing continues.
+func TestRejectUnknownStringMessage(t *testing.T) {
+	consumer := deliveryConsumer{bodies: [][]byte{[]byte("STOP"), []byte("END")}}
+	assertRejectedFirstDelivery(t, &consumer)
+}
+
+// TestRejectUndecodableEntry checks that an entry that cannot be decoded is rejected,
+// and the processing continues.
+func TestRejectUndecodableEntry(t *testing.T) {
+	consumer := deliveryConsumer{bodies: [][]byte{[]byte(`{"Timestamp": 42}`), []byte("END")}}
+	assertRejectedFirstDelivery(t, &consumer)
+}
+
+func assertRejectedFirstDelivery(t *testing.T, consumer *deliveryConsumer) {
+	mockMessageProducer := mocks.NewMockMessageProducer(testmodels.TestProcessedData{}, nil, 0)
+
+	processor := processing.NewEntryProcessor(mockMessageProducer, consumer)
+	for err := range processor.HandleEntries() {
+		t.Fatalf("Could not process the entries: %s", err)
+	}
+
+	if rejected := consumer.acknowledger(0); !rejected.Rejected || rejected.Acked {
+		t.Fatalf("Expected the first message to be rejected, got %+v", rejected)
+	}
+
+	if end := consumer.acknowledger(1); !end.Acked {
+		t.Fatal("Expected the END message to be processed")
+	}
+}
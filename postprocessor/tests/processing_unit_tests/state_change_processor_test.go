@@ -0,0 +1,74 @@
+package processingunittests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/processing"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+)
+
+func stateChangeEntry(second int, state int64) parsermodels.ParsedLogEntry {
+	return parsermodels.ParsedLogEntry{
+		Timestamp: time.Date(2020, time.June, 10, 9, 18, second, 0, time.UTC),
+		Level:     "VERBOSE",
+		InfoParams: &parsermodels.InfoParams{
+			EntryType: parsermodels.SmcStateChange,
+			StateChange: &parsermodels.SmcStateChangeParams{
+				SmcUID: "dc18-smc3",
+				State:  state,
+				Flags:  parsermodels.DecodeSmcStateFlags(state),
+			},
+		},
+	}
+}
+
+func TestProcessStateChangeEntries(t *testing.T) {
+	infoProcessor := processing.InfoProcessor{
+		PodUIDToSmcUID: make(map[string]string),
+		SmcStatesByUID: make(map[string]int64),
+	}
+
+	// The first state of an SMC has no previous state.
+	_, event, _, _ := infoProcessor.ProcessInfoEntry(stateChangeEntry(30, 0b100001))
+	expectedTransition := &models.StateTransition{
+		NewState:     0b100001,
+		Flags:        []string{"Joined", "Connected"},
+		SetFlags:     []string{"Joined", "Connected"},
+		ClearedFlags: []string{},
+	}
+	assertStateTransition(t, event, expectedTransition)
+
+	// Repeating the same state does not create an event.
+	_, event, _, _ = infoProcessor.ProcessInfoEntry(stateChangeEntry(31, 0b100001))
+	if event != nil {
+		t.Fatalf("Expected no event for a repeated state, got %+v", event)
+	}
+
+	_, event, _, _ = infoProcessor.ProcessInfoEntry(stateChangeEntry(32, 0b000011))
+	expectedTransition = &models.StateTransition{
+		PreviousState:    0b100001,
+		HasPreviousState: true,
+		NewState:         0b000011,
+		Flags:            []string{"Joined", "AddressKnown"},
+		SetFlags:         []string{"AddressKnown"},
+		ClearedFlags:     []string{"Connected"},
+	}
+	assertStateTransition(t, event, expectedTransition)
+}
+
+func assertStateTransition(t *testing.T, event *models.SmcEvent, expectedTransition *models.StateTransition) {
+	if event == nil {
+		t.Fatal("Expected a state change event, got nil")
+	}
+
+	if event.EventType != models.SmcStateChanged || event.SmcUID != "dc18-smc3" {
+		t.Fatalf("Expected a state change event of dc18-smc3, got %s of %s", event.EventTypeString, event.SmcUID)
+	}
+
+	if !reflect.DeepEqual(event.StateTransition, expectedTransition) {
+		t.Fatalf("Expected state transition %+v, got %+v", expectedTransition, event.StateTransition)
+	}
+}
@@ -0,0 +1,125 @@
+package processingunittests
+
+import (
+	"testing"
+	"time"
+
+	parsermodels "github.com/kozgot/go-log-processing/parser/pkg/models"
+	"github.com/kozgot/go-log-processing/postprocessor/internal/processing"
+	"github.com/kozgot/go-log-processing/postprocessor/pkg/models"
+)
+
+var taskCreation = time.Date(2020, time.June, 10, 9, 18, 0, 0, time.UTC)
+
+func taskLaunchEntry(fileName string, second int, uid int, retry int, thread string) parsermodels.ParsedLogEntry {
+	return parsermodels.ParsedLogEntry{
+		Timestamp: time.Date(2020, time.June, 10, 9, 18, second, 0, time.UTC),
+		Level:     "VERBOSE",
+		Source:    parsermodels.SourceLocation{FileName: fileName},
+		InfoParams: &parsermodels.InfoParams{
+			EntryType: parsermodels.TaskLaunch,
+			TaskLaunch: &parsermodels.TaskLaunchParams{
+				Name:     "Connect",
+				Type:     "connect",
+				SmcUID:   "dc18-smc3",
+				UID:      uid,
+				Priority: 2,
+				Retry:    retry,
+				Creation: taskCreation,
+				Thread:   thread,
+			},
+		},
+	}
+}
+
+func taskFailedEntry(fileName string, second int, uid int, retry int) parsermodels.ParsedLogEntry {
+	return parsermodels.ParsedLogEntry{
+		Timestamp: time.Date(2020, time.June, 10, 9, 18, second, 0, time.UTC),
+		Level:     "WARN",
+		Source:    parsermodels.SourceLocation{FileName: fileName},
+		WarningParams: &parsermodels.WarningParams{
+			TaskFailedWarningParams: &parsermodels.TaskFailedWarningParams{
+				Name:     "Connect",
+				SmcUID:   "dc18-smc3",
+				UID:      uid,
+				Priority: 2,
+				Retry:    retry,
+				Creation: taskCreation,
+				Details:  &parsermodels.ErrorParams{Message: "Timeout"},
+			},
+		},
+	}
+}
+
+func TestTaskLifecycles(t *testing.T) {
+	taskProcessor := processing.NewTaskProcessor()
+
+	// Task 1 fails after its first launch, and finishes after the retry, when its thread launches task 3.
+	taskProcessor.ProcessEntry(taskLaunchEntry("dc18/dc_main.log", 10, 1, 0, "1"))
+	taskProcessor.ProcessEntry(taskFailedEntry("dc18/dc_main.log", 12, 1, 1))
+	taskProcessor.ProcessEntry(taskLaunchEntry("dc18/dc_main.log", 20, 1, 1, "1"))
+
+	// Task 2 fails after its only launch.
+	taskProcessor.ProcessEntry(taskLaunchEntry("dc18/dc_main.log", 15, 2, 0, "2"))
+	taskProcessor.ProcessEntry(taskFailedEntry("dc18/dc_main.log", 17, 2, 1))
+
+	// Task 3 is still running, its outcome has not been logged.
+	taskProcessor.ProcessEntry(taskLaunchEntry("dc18/dc_main.log", 25, 3, 0, "1"))
+
+	// Task 1 of another DC is a different task, and its threads do not end the tasks of dc18.
+	taskProcessor.ProcessEntry(taskLaunchEntry("dc19/dc_main.log", 11, 1, 0, "2"))
+	taskProcessor.ProcessEntry(taskLaunchEntry("dc19/dc_main.log", 30, 4, 0, "1"))
+
+	lifecycles := taskProcessor.Lifecycles()
+	if len(lifecycles) != 5 {
+		t.Fatalf("Expected 5 task lifecycles, got %d", len(lifecycles))
+	}
+
+	first := lifecycles[0]
+	if first.DC != "dc18" || first.UID != 1 || first.Status != models.TaskFinished || first.Launches != 2 ||
+		first.Failures != 1 || first.Retry != 1 || first.QueueLatencySeconds != 10 {
+		t.Fatalf("Unexpected lifecycle of task 1: %+v", first)
+	}
+
+	second := lifecycles[1]
+	if second.DC != "dc18" || second.UID != 2 || second.Status != models.TaskFailed || second.Launches != 1 ||
+		second.Failures != 1 || second.LastError != "Timeout" || second.QueueLatencySeconds != 15 {
+		t.Fatalf("Unexpected lifecycle of task 2: %+v", second)
+	}
+
+	third := lifecycles[2]
+	if third.DC != "dc18" || third.UID != 3 || third.Status != models.TaskLaunched {
+		t.Fatalf("Unexpected lifecycle of task 3: %+v", third)
+	}
+
+	otherDC := lifecycles[3]
+	if otherDC.DC != "dc19" || otherDC.UID != 1 || otherDC.Status != models.TaskLaunched || otherDC.Launches != 1 {
+		t.Fatalf("Unexpected lifecycle of task 1 of dc19: %+v", otherDC)
+	}
+
+	event := processing.CreateTaskLifecycleEvent(second)
+	if event.EventType != models.TaskLifecycleEvent || event.SmcUID != "dc18-smc3" || event.Task.UID != 2 {
+		t.Fatalf("Unexpected task lifecycle event: %+v", event)
+	}
+
+	taskProcessor.Reset()
+	if len(taskProcessor.Lifecycles()) != 0 {
+		t.Fatal("Expected no task lifecycles after reset")
+	}
+}
+
+// TestRetriedTaskOnAnotherThread checks that a task retried on another thread is not ended
+// by the next launch on the thread of its previous attempt.
+func TestRetriedTaskOnAnotherThread(t *testing.T) {
+	taskProcessor := processing.NewTaskProcessor()
+
+	taskProcessor.ProcessEntry(taskLaunchEntry("dc18/dc_main.log", 10, 1, 0, "1"))
+	taskProcessor.ProcessEntry(taskFailedEntry("dc18/dc_main.log", 12, 1, 1))
+	taskProcessor.ProcessEntry(taskLaunchEntry("dc18/dc_main.log", 20, 1, 1, "2"))
+	taskProcessor.ProcessEntry(taskLaunchEntry("dc18/dc_main.log", 25, 2, 0, "1"))
+
+	lifecycles := taskProcessor.Lifecycles()
+	if len(lifecycles) != 2 || lifecycles[0].UID != 1 || lifecycles[0].Status != models.TaskLaunched {
+		t.Fatalf("Expected task 1 to be running, got %+v", lifecycles)
+	}
+}
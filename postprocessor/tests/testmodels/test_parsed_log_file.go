@@ -26,3 +26,13 @@ func (t *TestParsedLogFile) FromJSON(bytes []byte) {
 		log.Fatal("Can't deserialize", bytes)
 	}
 }
+
+// EntryCounts returns the number of entries by file name, as they are sent in the run end of the parser.
+func (t *TestParsedLogFile) EntryCounts() map[string]int {
+	entryCounts := make(map[string]int)
+	for _, line := range t.Lines {
+		entryCounts[line.Source.FileName]++
+	}
+
+	return entryCounts
+}
@@ -56,15 +56,16 @@ func (producer *TestRabbitMqProducer) CloseChannelAndConnection() {
 	producer.channel.Close()
 }
 
-// PublishStringMessage sends a string message to the message queue.
-func (producer *TestRabbitMqProducer) PublishStringMessage(message string) {
-	bytes := []byte(message)
-	producer.sendDataToPostprocessor(bytes)
+// PublishRunControl sends a run control message to the message queue.
+func (producer *TestRabbitMqProducer) PublishRunControl(control parsermodels.RunControl) {
+	envelope := parsermodels.NewRunControlEnvelope("test-producer", control)
+	producer.sendDataToPostprocessor(envelope.Serialize())
 }
 
-// PublishEntry sends the parsed log lines to the message queue.
-func (producer *TestRabbitMqProducer) PublishEntry(line parsermodels.ParsedLogEntry) {
-	producer.sendDataToPostprocessor(line.Serialize())
+// PublishEntry sends the parsed log lines of a run to the message queue.
+func (producer *TestRabbitMqProducer) PublishEntry(runID string, line parsermodels.ParsedLogEntry) {
+	envelope := parsermodels.NewEnvelope("test-producer", runID, line.Serialize())
+	producer.sendDataToPostprocessor(envelope.Serialize())
 }
 
 func (producer *TestRabbitMqProducer) sendDataToPostprocessor(data []byte) {